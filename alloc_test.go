@@ -51,6 +51,11 @@ func TestZeroAlloc(t *testing.T) {
 				return d.Validate()
 			})
 		})
+		t.Run("Walk", func(t *testing.T) {
+			zeroAllocDec(t, benchData, func(d *Decoder) error {
+				return d.Walk(NopHandler{})
+			})
+		})
 		t.Run("ObjBytes", func(t *testing.T) {
 			zeroAllocDec(t, benchData, func(d *Decoder) error {
 				return d.Arr(func(d *Decoder) error {
@@ -96,6 +101,37 @@ func TestZeroAlloc(t *testing.T) {
 			})
 		})
 	})
+	t.Run("Doc", func(t *testing.T) {
+		doc, err := ParseDoc(benchData)
+		if err != nil {
+			t.Fatal(err)
+		}
+		zeroAlloc(t, func() {
+			v, err := doc.Root().Pointer("/0/person/name/fullName")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if raw := v.Raw(); string(raw) != `"Leonid Bugaev"` {
+				t.Fatal(raw)
+			}
+		})
+	})
+	t.Run("Raw", func(t *testing.T) {
+		var (
+			obj = Raw(`{"a": 1, "b": [1, 2]}`)
+			num = Raw(`12345`)
+		)
+		zeroAlloc(t, func() {
+			v, err := obj.Len()
+			if err != nil || v != 2 {
+				t.Fatal(v, err)
+			}
+			n, err := num.Int64()
+			if err != nil || n != 12345 {
+				t.Fatal(n, err)
+			}
+		})
+	})
 	t.Run("Encoder", func(t *testing.T) {
 		t.Run("Manual", func(t *testing.T) {
 			zeroAllocEnc(t, func(e *Encoder) {
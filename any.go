@@ -0,0 +1,397 @@
+package jx
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/go-faster/errors"
+)
+
+// AnyType is type of Any value.
+type AnyType byte
+
+// Possible types for Any.
+const (
+	AnyInvalid AnyType = iota
+	AnyStr
+	AnyNumber
+	AnyNull
+	AnyObj
+	AnyArr
+	AnyBool
+)
+
+func (t AnyType) String() string {
+	switch t {
+	case AnyInvalid:
+		return "invalid"
+	case AnyStr:
+		return "string"
+	case AnyNumber:
+		return "number"
+	case AnyNull:
+		return "null"
+	case AnyObj:
+		return "object"
+	case AnyArr:
+		return "array"
+	case AnyBool:
+		return "bool"
+	default:
+		return "unknown"
+	}
+}
+
+// Any represents any json value as sum type.
+//
+// Object fields are stored in Child in the order of appearance,
+// duplicate keys are preserved.
+type Any struct {
+	Type AnyType // zero value if AnyInvalid, can be AnyNull
+
+	Str    string // AnyStr
+	Bool   bool   // AnyBool
+	Number Num    // AnyNumber
+
+	// Key in object. Valid only if KeyValid.
+	Key string
+	// KeyValid denotes whether Any is element of object.
+	// Needed for representing Key that is blank.
+	//
+	// Can be true only for Child of AnyObj.
+	KeyValid bool
+
+	Child []Any // AnyArr or AnyObj
+}
+
+// Equal reports whether v is equal to b.
+//
+// Numbers are compared by their representation, see Num.Equal. Object
+// members are compared regardless of order, like in rawEqual, but
+// duplicate keys should match in number.
+func (v Any) Equal(b Any) bool {
+	if v.KeyValid != b.KeyValid || v.KeyValid && v.Key != b.Key {
+		return false
+	}
+	if v.Type != b.Type {
+		return false
+	}
+	switch v.Type {
+	case AnyNull, AnyInvalid:
+		return true
+	case AnyBool:
+		return v.Bool == b.Bool
+	case AnyStr:
+		return v.Str == b.Str
+	case AnyNumber:
+		return v.Number.Equal(b.Number)
+	}
+	if len(v.Child) != len(b.Child) {
+		return false
+	}
+	if v.Type == AnyObj {
+		return objEqual(v.Child, b.Child)
+	}
+	for i := range v.Child {
+		if !v.Child[i].Equal(b.Child[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// objEqual reports whether object members a and b are equal regardless
+// of order.
+func objEqual(a, b []Any) bool {
+	used := make([]bool, len(b))
+next:
+	for i, m := range a {
+		// Fast path for same order.
+		if !used[i] && m.Equal(b[i]) {
+			used[i] = true
+			continue
+		}
+		for j := range b {
+			if !used[j] && m.Equal(b[j]) {
+				used[j] = true
+				continue next
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// Any reads Any value.
+func (d *Decoder) Any() (Any, error) {
+	var v Any
+	if err := v.Read(d); err != nil {
+		return Any{}, err
+	}
+	return v, nil
+}
+
+// Any encodes Any value.
+func (e *Encoder) Any(a Any) {
+	a.Write(e)
+}
+
+// Read reads json value from Decoder.
+//
+// Child and Number buffers of v are truncated and reused, so reading into
+// same Any does not allocate for already seen structure.
+func (v *Any) Read(d *Decoder) error {
+	num := v.Number[:0]
+	*v = Any{Child: v.Child[:0]}
+	switch d.Next() {
+	case Invalid:
+		return errors.New("invalid")
+	case Number:
+		n, err := d.NumAppend(num)
+		if err != nil {
+			return errors.Wrap(err, "number")
+		}
+		v.Number = n
+		v.Type = AnyNumber
+	case String:
+		s, err := d.Str()
+		if err != nil {
+			return errors.Wrap(err, "str")
+		}
+		v.Str = s
+		v.Type = AnyStr
+	case Null:
+		if err := d.Null(); err != nil {
+			return errors.Wrap(err, "null")
+		}
+		v.Type = AnyNull
+	case Bool:
+		b, err := d.Bool()
+		if err != nil {
+			return errors.Wrap(err, "bool")
+		}
+		v.Bool = b
+		v.Type = AnyBool
+	case Object:
+		v.Type = AnyObj
+		if err := d.Obj(func(r *Decoder, s string) error {
+			elem := v.grow()
+			if err := elem.Read(r); err != nil {
+				return errors.Wrap(err, "elem")
+			}
+			elem.Key = s
+			elem.KeyValid = true
+			return nil
+		}); err != nil {
+			return errors.Wrap(err, "obj")
+		}
+		return nil
+	case Array:
+		v.Type = AnyArr
+		if err := d.Arr(func(r *Decoder) error {
+			if err := v.grow().Read(r); err != nil {
+				return errors.Wrap(err, "elem")
+			}
+			return nil
+		}); err != nil {
+			return errors.Wrap(err, "array")
+		}
+		return nil
+	}
+	return nil
+}
+
+// grow appends element to Child, reusing truncated one if any.
+func (v *Any) grow() *Any {
+	if n := len(v.Child); n < cap(v.Child) {
+		v.Child = v.Child[:n+1]
+	} else {
+		v.Child = append(v.Child, Any{})
+	}
+	return &v.Child[len(v.Child)-1]
+}
+
+// Write json representation of Any to Encoder.
+func (v Any) Write(w *Encoder) {
+	if v.KeyValid {
+		w.FieldStart(v.Key)
+	}
+	switch v.Type {
+	case AnyStr:
+		w.Str(v.Str)
+	case AnyNumber:
+		w.Num(v.Number)
+	case AnyBool:
+		w.Bool(v.Bool)
+	case AnyNull:
+		w.Null()
+	case AnyArr:
+		w.ArrStart()
+		for _, c := range v.Child {
+			c.Write(w)
+		}
+		w.ArrEnd()
+	case AnyObj:
+		w.ObjStart()
+		for _, c := range v.Child {
+			c.Write(w)
+		}
+		w.ObjEnd()
+	}
+}
+
+func (v Any) String() string {
+	var b strings.Builder
+	if v.KeyValid {
+		if v.Key == "" {
+			b.WriteString("<blank>")
+		}
+		b.WriteString(v.Key)
+		b.WriteString(": ")
+	}
+	switch v.Type {
+	case AnyStr:
+		b.WriteString(`'` + v.Str + `'`)
+	case AnyNumber:
+		b.WriteString(v.Number.String())
+	case AnyBool:
+		b.WriteString(strconv.FormatBool(v.Bool))
+	case AnyNull:
+		b.WriteString("null")
+	case AnyArr:
+		b.WriteString("[")
+		for i, c := range v.Child {
+			if i != 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(c.String())
+		}
+		b.WriteString("]")
+	case AnyObj:
+		b.WriteString("{")
+		for i, c := range v.Child {
+			if i != 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(c.String())
+		}
+		b.WriteString("}")
+	default:
+		b.WriteString("<invalid>")
+	}
+	return b.String()
+}
+
+// Reset Any value to reuse.
+func (v *Any) Reset() {
+	v.Type = AnyInvalid
+	v.Child = v.Child[:0]
+	v.KeyValid = false
+
+	v.Str = ""
+	v.Key = ""
+	v.Bool = false
+	v.Number = v.Number[:0]
+}
+
+// Obj calls f for any child that is field if v is AnyObj.
+func (v Any) Obj(f func(k string, v Any)) {
+	if v.Type != AnyObj {
+		return
+	}
+	for _, c := range v.Child {
+		if !c.KeyValid {
+			continue
+		}
+		f(c.Key, c)
+	}
+}
+
+// Arr calls f for any child if v is AnyArr.
+func (v Any) Arr(f func(v Any)) {
+	if v.Type != AnyArr {
+		return
+	}
+	for _, c := range v.Child {
+		f(c)
+	}
+}
+
+func (v Any) expect(t AnyType) error {
+	if v.Type != t {
+		return errors.Errorf("expected %s, got %s", t, v.Type)
+	}
+	return nil
+}
+
+// AsStr returns string value if v is AnyStr.
+func (v Any) AsStr() (string, error) {
+	if err := v.expect(AnyStr); err != nil {
+		return "", err
+	}
+	return v.Str, nil
+}
+
+// AsBool returns boolean value if v is AnyBool.
+func (v Any) AsBool() (bool, error) {
+	if err := v.expect(AnyBool); err != nil {
+		return false, err
+	}
+	return v.Bool, nil
+}
+
+// AsNum returns number value if v is AnyNumber.
+func (v Any) AsNum() (Num, error) {
+	if err := v.expect(AnyNumber); err != nil {
+		return nil, err
+	}
+	return v.Number, nil
+}
+
+// AsInt64 decodes number value as int64 if v is AnyNumber.
+func (v Any) AsInt64() (int64, error) {
+	if err := v.expect(AnyNumber); err != nil {
+		return 0, err
+	}
+	return v.Number.Int64()
+}
+
+// AsFloat64 decodes number value as float64 if v is AnyNumber.
+func (v Any) AsFloat64() (float64, error) {
+	if err := v.expect(AnyNumber); err != nil {
+		return 0, err
+	}
+	return v.Number.Float64()
+}
+
+// Len returns count of children if v is AnyObj or AnyArr.
+func (v Any) Len() (int, error) {
+	if v.Type != AnyObj && v.Type != AnyArr {
+		return 0, errors.Errorf("expected object or array, got %s", v.Type)
+	}
+	return len(v.Child), nil
+}
+
+// Field returns first field with given key if v is AnyObj.
+func (v Any) Field(key string) (Any, error) {
+	if err := v.expect(AnyObj); err != nil {
+		return Any{}, err
+	}
+	for _, c := range v.Child {
+		if c.KeyValid && c.Key == key {
+			return c, nil
+		}
+	}
+	return Any{}, errors.Errorf("field %q not found", key)
+}
+
+// Index returns i-th element if v is AnyArr.
+func (v Any) Index(i int) (Any, error) {
+	if err := v.expect(AnyArr); err != nil {
+		return Any{}, err
+	}
+	if i < 0 || i >= len(v.Child) {
+		return Any{}, errors.Errorf("index %d out of range [0:%d]", i, len(v.Child))
+	}
+	return v.Child[i], nil
+}
@@ -3,250 +3,12 @@ package jx
 import (
 	hexEnc "encoding/hex"
 	"encoding/json"
-	"strconv"
-	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
-
-	"github.com/go-faster/errors"
-)
-
-// NB: Any left intentionally unexported
-
-// AnyType is type of Any value.
-type AnyType byte
-
-// Possible types for Any.
-const (
-	AnyInvalid AnyType = iota
-	AnyStr
-	AnyNumber
-	AnyNull
-	AnyObj
-	AnyArr
-	AnyBool
 )
 
-// Any represents any json value as sum type.
-type Any struct {
-	Type AnyType // zero value if AnyInvalid, can be AnyNull
-
-	Str    string // AnyStr
-	Bool   bool   // AnyBool
-	Number Num    // AnyNumber
-
-	// Key in object. Valid only if KeyValid.
-	Key string
-	// KeyValid denotes whether Any is element of object.
-	// Needed for representing Key that is blank.
-	//
-	// Can be true only for Child of AnyObj.
-	KeyValid bool
-
-	Child []Any // AnyArr or AnyObj
-}
-
-// Equal reports whether v is equal to b.
-func (v Any) Equal(b Any) bool {
-	if v.KeyValid && v.Key != b.Key {
-		return false
-	}
-	if v.Type != b.Type {
-		return false
-	}
-	switch v.Type {
-	case AnyNull, AnyInvalid:
-		return true
-	case AnyBool:
-		return v.Bool == b.Bool
-	case AnyStr:
-		return v.Str == b.Str
-	case AnyNumber:
-		return v.Number.Equal(b.Number)
-	}
-	if len(v.Child) != len(b.Child) {
-		return false
-	}
-	for i := range v.Child {
-		if !v.Child[i].Equal(b.Child[i]) {
-			return false
-		}
-	}
-	return true
-}
-
-// Any reads Any value.
-func (d *Decoder) Any() (Any, error) {
-	var v Any
-	if err := v.Read(d); err != nil {
-		return Any{}, err
-	}
-	return v, nil
-}
-
-// Any encodes Any value.
-func (e *Encoder) Any(a Any) {
-	a.Write(e)
-}
-
-func (v *Any) Read(d *Decoder) error {
-	switch d.Next() {
-	case Invalid:
-		return errors.New("invalid")
-	case Number:
-		n, err := d.NumAppend(nil)
-		if err != nil {
-			return errors.Wrap(err, "number")
-		}
-		v.Number = n
-		v.Type = AnyNumber
-	case String:
-		s, err := d.Str()
-		if err != nil {
-			return errors.Wrap(err, "str")
-		}
-		v.Str = s
-		v.Type = AnyStr
-	case Null:
-		if err := d.Null(); err != nil {
-			return errors.Wrap(err, "null")
-		}
-		v.Type = AnyNull
-	case Bool:
-		b, err := d.Bool()
-		if err != nil {
-			return errors.Wrap(err, "bool")
-		}
-		v.Bool = b
-		v.Type = AnyBool
-	case Object:
-		v.Type = AnyObj
-		if err := d.Obj(func(r *Decoder, s string) error {
-			var elem Any
-			if err := elem.Read(r); err != nil {
-				return errors.Wrap(err, "elem")
-			}
-			elem.Key = s
-			elem.KeyValid = true
-			v.Child = append(v.Child, elem)
-			return nil
-		}); err != nil {
-			return errors.Wrap(err, "obj")
-		}
-		return nil
-	case Array:
-		v.Type = AnyArr
-		if err := d.Arr(func(r *Decoder) error {
-			var elem Any
-			if err := elem.Read(r); err != nil {
-				return errors.Wrap(err, "elem")
-			}
-			v.Child = append(v.Child, elem)
-			return nil
-		}); err != nil {
-			return errors.Wrap(err, "array")
-		}
-		return nil
-	}
-	return nil
-}
-
-// Write json representation of Any to Encoder.
-func (v Any) Write(w *Encoder) {
-	if v.KeyValid {
-		w.FieldStart(v.Key)
-	}
-	switch v.Type {
-	case AnyStr:
-		w.Str(v.Str)
-	case AnyNumber:
-		w.Num(v.Number)
-	case AnyBool:
-		w.Bool(v.Bool)
-	case AnyNull:
-		w.Null()
-	case AnyArr:
-		w.ArrStart()
-		for _, c := range v.Child {
-			c.Write(w)
-		}
-		w.ArrEnd()
-	case AnyObj:
-		w.ObjStart()
-		for _, c := range v.Child {
-			c.Write(w)
-		}
-		w.ObjEnd()
-	}
-}
-
-func (v Any) String() string {
-	var b strings.Builder
-	if v.KeyValid {
-		if v.Key == "" {
-			b.WriteString("<blank>")
-		}
-		b.WriteString(v.Key)
-		b.WriteString(": ")
-	}
-	switch v.Type {
-	case AnyStr:
-		b.WriteString(`'` + v.Str + `'`)
-	case AnyNumber:
-		b.WriteString(v.Number.String())
-	case AnyBool:
-		b.WriteString(strconv.FormatBool(v.Bool))
-	case AnyNull:
-		b.WriteString("null")
-	case AnyArr:
-		b.WriteString("[")
-		for i, c := range v.Child {
-			if i != 0 {
-				b.WriteString(", ")
-			}
-			b.WriteString(c.String())
-		}
-		b.WriteString("]")
-	case AnyObj:
-		b.WriteString("{")
-		for i, c := range v.Child {
-			if i != 0 {
-				b.WriteString(", ")
-			}
-			b.WriteString(c.String())
-		}
-		b.WriteString("}")
-	default:
-		b.WriteString("<invalid>")
-	}
-	return b.String()
-}
-
-// Reset Any value to reuse.
-func (v *Any) Reset() {
-	v.Type = AnyInvalid
-	v.Child = v.Child[:0]
-	v.KeyValid = false
-
-	v.Str = ""
-	v.Key = ""
-}
-
-// Obj calls f for any child that is field if v is AnyObj.
-func (v Any) Obj(f func(k string, v Any)) {
-	if v.Type != AnyObj {
-		return
-	}
-	for _, c := range v.Child {
-		if !c.KeyValid {
-			continue
-		}
-		f(c.Key, c)
-	}
-}
-
 func TestAny_Read(t *testing.T) {
 	t.Run("Obj", func(t *testing.T) {
 		var v Any
@@ -259,6 +21,29 @@ func TestAny_Read(t *testing.T) {
 		e.Any(v)
 		require.Equal(t, input, e.String(), "encoded value should equal to input")
 	})
+	t.Run("Reuse", func(t *testing.T) {
+		var v Any
+		require.NoError(t, v.Read(DecodeStr(`{"a":[1,2]}`)))
+		require.NoError(t, v.Read(DecodeStr(`{"b":true}`)))
+		require.Equal(t, "{b: true}", v.String())
+		require.NoError(t, v.Read(DecodeStr(`"foo"`)))
+		require.Empty(t, v.Child)
+	})
+	t.Run("ReuseAllocs", func(t *testing.T) {
+		var (
+			v    Any
+			d    = DecodeStr("")
+			data = []byte(`{"a":[1,2,{"b":null}],"c":true}`)
+		)
+		require.NoError(t, v.Read(DecodeBytes(data)))
+		require.Zero(t, testing.AllocsPerRun(10, func() {
+			d.ResetBytes(data)
+			if err := v.Read(d); err != nil {
+				t.Fatal(err)
+			}
+		}))
+		require.Equal(t, "{a: [1, 2, {b: null}], c: true}", v.String())
+	})
 	t.Run("Inputs", func(t *testing.T) {
 		for _, tt := range []struct {
 			Input string
@@ -374,9 +159,135 @@ func TestAny_Equal(t *testing.T) {
 				require.True(t, a.Equal(b))
 				b.Key = "1"
 				require.False(t, a.Equal(b))
+				b.Key, b.KeyValid = "", false
+				require.False(t, a.Equal(b))
+				require.False(t, b.Equal(a))
 			})
 		}
 	})
+	t.Run("Order", func(t *testing.T) {
+		read := func(t *testing.T, s string) Any {
+			t.Helper()
+			v, err := DecodeStr(s).Any()
+			require.NoError(t, err)
+			return v
+		}
+		for _, tt := range []struct {
+			a, b  string
+			equal bool
+		}{
+			{`{"a":1,"b":2}`, `{"b":2,"a":1}`, true},
+			{`{"a":{"x":1,"y":2}}`, `{"a":{"y":2,"x":1}}`, true},
+			{`{"a":1,"a":2}`, `{"a":2,"a":1}`, true},
+			{`{"a":1,"a":1}`, `{"a":1,"b":1}`, false},
+			{`{"a":1,"b":2}`, `{"b":1,"a":2}`, false},
+			{`[1,2]`, `[2,1]`, false},
+		} {
+			a, b := read(t, tt.a), read(t, tt.b)
+			require.Equal(t, tt.equal, a.Equal(b), "%s == %s", tt.a, tt.b)
+			require.Equal(t, tt.equal, b.Equal(a), "%s == %s", tt.b, tt.a)
+		}
+	})
+}
+
+func TestAny_Reader(t *testing.T) {
+	const input = `{"foo":[1,"bar",true,null,{"":-1.5}],"baz":{}}`
+	testBufferReader(input, func(t *testing.T, d *Decoder) {
+		v, err := d.Any()
+		require.NoError(t, err)
+
+		e := GetEncoder()
+		defer PutEncoder(e)
+		e.Any(v)
+		require.Equal(t, input, e.String())
+	})(t)
+}
+
+func TestAny_Accessors(t *testing.T) {
+	v, err := DecodeStr(`{"s":"str","n":10,"f":1.5,"b":true,"a":[1,2],"o":{"k":null}}`).Any()
+	require.NoError(t, err)
+
+	a := require.New(t)
+	n, err := v.Len()
+	a.NoError(err)
+	a.Equal(6, n)
+
+	field := func(key string) Any {
+		f, err := v.Field(key)
+		a.NoError(err)
+		return f
+	}
+
+	s, err := field("s").AsStr()
+	a.NoError(err)
+	a.Equal("str", s)
+
+	i, err := field("n").AsInt64()
+	a.NoError(err)
+	a.Equal(int64(10), i)
+
+	f, err := field("f").AsFloat64()
+	a.NoError(err)
+	a.Equal(1.5, f)
+
+	num, err := field("f").AsNum()
+	a.NoError(err)
+	a.Equal("1.5", num.String())
+
+	b, err := field("b").AsBool()
+	a.NoError(err)
+	a.True(b)
+
+	elem, err := field("a").Index(1)
+	a.NoError(err)
+	a.Equal("2", elem.Number.String())
+	var elems int
+	field("a").Arr(func(v Any) {
+		elems++
+	})
+	a.Equal(2, elems)
+
+	null, err := field("o").Field("k")
+	a.NoError(err)
+	a.Equal(AnyNull, null.Type)
+
+	t.Run("Errors", func(t *testing.T) {
+		a := require.New(t)
+
+		_, err := v.Field("missing")
+		a.EqualError(err, `field "missing" not found`)
+
+		_, err = field("a").Index(2)
+		a.EqualError(err, "index 2 out of range [0:2]")
+		_, err = field("a").Index(-1)
+		a.Error(err)
+
+		_, err = field("s").AsBool()
+		a.EqualError(err, "expected bool, got string")
+		_, err = field("b").AsStr()
+		a.Error(err)
+		_, err = field("b").AsNum()
+		a.Error(err)
+		_, err = field("b").AsInt64()
+		a.Error(err)
+		_, err = field("b").AsFloat64()
+		a.Error(err)
+		_, err = field("s").Len()
+		a.Error(err)
+		_, err = field("s").Field("k")
+		a.Error(err)
+		_, err = field("s").Index(0)
+		a.Error(err)
+	})
+}
+
+func TestAnyType_String(t *testing.T) {
+	met := map[string]bool{}
+	for i := AnyInvalid; i <= AnyBool+1; i++ {
+		s := i.String()
+		require.False(t, met[s], s)
+		met[s] = true
+	}
 }
 
 func BenchmarkAny(b *testing.B) {
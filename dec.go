@@ -87,6 +87,33 @@ type Decoder struct {
 
 	streamOffset int // for reader, offset in stream to start of current buf contents
 	depth        int
+	limits       Limits
+
+	// noDupKeys enables checking of duplicate keys.
+	noDupKeys bool
+	// keys are keys of objects being decoded, by depth.
+	keys []map[string]struct{}
+	// utf8 is handling mode of invalid UTF-8 in strings.
+	utf8 UTF8Mode
+	// json5 enables relaxed JSON5 syntax.
+	json5 bool
+
+	// noIndex disables structural indexing after invalid input is found,
+	// so error is reported by scalar path.
+	noIndex bool
+
+	// tokens are states of objects and arrays read by Token.
+	tokens []tokenFrame
+	// tokenBuf is buffer for unescaped strings of Token.
+	tokenBuf []byte
+
+	// lineBase is running line count of input discarded before streamOffset.
+	lineBase linePos
+	// lineLast caches last position computed in current buffer.
+	lineLast linePos
+
+	// owner marks errors created by decoder, see errOwner.
+	owner *byte
 }
 
 const defaultBuf = 512
@@ -125,6 +152,9 @@ func (d *Decoder) Reset(reader io.Reader) {
 	d.head = 0
 	d.tail = 0
 	d.depth = 0
+	d.tokens = d.tokens[:0]
+	d.noIndex = false
+	d.resetPosition()
 
 	// Reads from reader need buffer.
 	if cap(d.buf) == 0 {
@@ -137,12 +167,21 @@ func (d *Decoder) Reset(reader io.Reader) {
 	}
 }
 
+func (d *Decoder) resetPosition() {
+	d.streamOffset = 0
+	d.lineBase = linePos{}
+	d.lineLast = linePos{}
+}
+
 // ResetBytes resets underlying state, next reads will use provided buffer.
 func (d *Decoder) ResetBytes(input []byte) {
 	d.reader = nil
 	d.head = 0
-	d.tail = len(input)
 	d.depth = 0
+	d.tokens = d.tokens[:0]
+	d.noIndex = false
+	d.resetPosition()
 
 	d.buf = input
+	d.limitInput()
 }
@@ -27,16 +27,20 @@ func (d *Decoder) Elem() (ok bool, err error) {
 	case ']':
 		return false, nil
 	case ',':
-		return true, nil
+		end, err := d.trailingComma(']')
+		if err != nil {
+			return false, err
+		}
+		return !end, nil
 	default:
-		return false, errors.Wrap(badToken(c, d.offset()), `"[", "," or "]" expected`)
+		return false, errors.Wrap(d.badToken(c, d.offset()), `"[", "," or "]" expected`)
 	}
 }
 
 // Arr decodes array and invokes callback on each array element.
 func (d *Decoder) Arr(f func(d *Decoder) error) error {
-	if err := d.consume('['); err != nil {
-		return errors.Wrap(err, `"[" expected`)
+	if err := d.consumeType('[', Array); err != nil {
+		return err
 	}
 	if f == nil {
 		return d.skipArr()
@@ -53,28 +57,38 @@ func (d *Decoder) Arr(f func(d *Decoder) error) error {
 	}
 	d.unread()
 	if err := f(d); err != nil {
-		return errors.Wrap(err, "callback")
+		return d.elemError(err, 0, types[c])
 	}
 
 	c, err = d.more()
 	if err != nil {
 		return errors.Wrap(err, `"," or "]" expected`)
 	}
-	for c == ',' {
+	for i := 1; c == ','; i++ {
+		end, err := d.trailingComma(']')
+		if err != nil {
+			return err
+		}
+		if end {
+			return d.decDepth()
+		}
+		if err := d.checkArrLen(i + 1); err != nil {
+			return err
+		}
 		// Skip whitespace before reading element.
-		if _, err := d.next(); err != nil {
+		if c, err = d.next(); err != nil {
 			return err
 		}
 		d.unread()
 		if err := f(d); err != nil {
-			return errors.Wrap(err, "callback")
+			return d.elemError(err, i, types[c])
 		}
 		if c, err = d.next(); err != nil {
 			return err
 		}
 	}
 	if c != ']' {
-		err := badToken(c, d.offset()-1)
+		err := d.badToken(c, d.offset()-1)
 		return errors.Wrap(err, `"]" expected`)
 	}
 	return d.decDepth()
@@ -10,12 +10,13 @@ type ArrIter struct {
 	err    error
 	closed bool
 	comma  bool
+	index  int
 }
 
 // ArrIter creates new array iterator.
 func (d *Decoder) ArrIter() (ArrIter, error) {
-	if err := d.consume('['); err != nil {
-		return ArrIter{}, errors.Wrap(err, `"[" expected`)
+	if err := d.consumeType('[', Array); err != nil {
+		return ArrIter{}, err
 	}
 	if err := d.incDepth(); err != nil {
 		return ArrIter{}, err
@@ -46,10 +47,30 @@ func (i *ArrIter) Next() bool {
 	}
 	if i.comma {
 		if c != ',' {
-			err := badToken(c, dec.offset()-1)
+			err := dec.badToken(c, dec.offset()-1)
 			i.err = errors.Wrap(err, `"," expected`)
 			return false
 		}
+		end, err := dec.trailingComma(']')
+		if err == nil && !end {
+			// Skip whitespace before element.
+			_, err = dec.more()
+		}
+		if err != nil {
+			i.err = dec.elemError(errors.Wrap(err, "value expected"), i.index+1, Invalid)
+			return false
+		}
+		if end {
+			i.closed = true
+			i.err = dec.decDepth()
+			return false
+		}
+		dec.unread()
+		i.index++
+		if err := dec.checkArrLen(i.index + 1); err != nil {
+			i.err = err
+			return false
+		}
 	} else {
 		dec.unread()
 	}
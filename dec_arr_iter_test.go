@@ -37,10 +37,22 @@ func TestDecoder_ArrIter(t *testing.T) {
 		d := DecodeStr(`[`)
 		// Emulate depth
 		d.depth = maxDepth
-		require.ErrorIs(t, testIter(d), errMaxDepth)
+		require.ErrorIs(t, testIter(d), ErrMaxDepth)
 	})
 	t.Run("Empty", func(t *testing.T) {
 		d := DecodeStr(``)
 		require.ErrorIs(t, testIter(d), io.ErrUnexpectedEOF)
 	})
+	t.Run("Whitespace", testBufferReader(`[ 1 , "a" ,  true ]`, func(t *testing.T, d *Decoder) {
+		iter, err := d.ArrIter()
+		require.NoError(t, err)
+		var elems []string
+		for iter.Next() {
+			raw, err := d.Raw()
+			require.NoError(t, err)
+			elems = append(elems, raw.String())
+		}
+		require.NoError(t, iter.Err())
+		require.Equal(t, []string{"1", `"a"`, "true"}, elems)
+	}))
 }
@@ -29,6 +29,7 @@ func (d *Decoder) Base64Append(b []byte) ([]byte, error) {
 		}
 		return b, nil
 	}
+	offset := d.offset()
 	buf, err := d.StrBytes()
 	if err != nil {
 		return nil, errors.Wrap(err, "bytes")
@@ -40,7 +41,7 @@ func (d *Decoder) Base64Append(b []byte) ([]byte, error) {
 
 	n, err := base64.StdEncoding.Decode(b[start:], buf)
 	if err != nil {
-		return nil, errors.Wrap(err, "decode")
+		return nil, d.errorAt(errors.Wrap(err, "decode"), offset)
 	}
 
 	return b[:start+n], nil
@@ -16,11 +16,13 @@ func (d *Decoder) Capture(f func(d *Decoder) error) error {
 		var (
 			buf          bytes.Buffer
 			streamOffset = d.streamOffset
+			lineBase     = d.lineBase
 		)
 		reader := io.TeeReader(d.reader, &buf)
 		defer func() {
 			d.reader = io.MultiReader(&buf, d.reader)
 			d.streamOffset = streamOffset
+			d.lineBase = lineBase
 		}()
 		d.reader = reader
 	}
@@ -5,12 +5,14 @@ import "github.com/go-faster/errors"
 // limit maximum depth of nesting, as allowed by https://tools.ietf.org/html/rfc7159#section-9
 const maxDepth = 10000
 
-var errMaxDepth = errors.New("depth: maximum")
-
 func (d *Decoder) incDepth() error {
 	d.depth++
-	if d.depth > maxDepth {
-		return errMaxDepth
+	max := d.limits.Depth
+	if max <= 0 {
+		max = maxDepth
+	}
+	if d.depth > max {
+		return d.errorAt(ErrMaxDepth, d.offset())
 	}
 	return nil
 }
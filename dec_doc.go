@@ -0,0 +1,447 @@
+package jx
+
+import (
+	"bytes"
+	"io"
+	"math"
+
+	"github.com/go-faster/errors"
+)
+
+// Doc is json document, parsed to tape for random access.
+//
+// Tape is built in one pass by Parse and has entry for every value and
+// object key, with offsets of value in input and index of next entry
+// after value. So any value is skipped in O(1), and values are decoded
+// only on demand:
+//
+//	var doc jx.Doc
+//	if err := doc.Parse(data); err != nil {
+//		return err
+//	}
+//	name, err := doc.Root().Pointer("/user/name")
+//	if err != nil {
+//		return err
+//	}
+//	fmt.Println(name.Str())
+//
+// Doc references input, which should not be modified while Doc is used.
+// Doc is safe for concurrent reads after Parse.
+type Doc struct {
+	d    Decoder
+	buf  []byte
+	tape []tapeEntry
+}
+
+// tapeEntry is value or object key of Doc.
+type tapeEntry struct {
+	start uint32 // offset of value
+	end   uint32 // offset after value
+	next  uint32 // index of entry after value and its children
+	n     uint32 // count of object members or array elements
+	typ   uint8  // Type of value
+}
+
+// ParseDoc parses json document from byte slice.
+func ParseDoc(data []byte) (*Doc, error) {
+	doc := &Doc{}
+	if err := doc.Parse(data); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// SetLimits sets limits of parsed input.
+func (doc *Doc) SetLimits(l Limits) {
+	doc.d.limits = l
+}
+
+// Parse parses json document from byte slice, reusing tape of previous
+// document.
+//
+// Input should be single json value without trailing data.
+func (doc *Doc) Parse(data []byte) error {
+	if uint64(len(data)) > math.MaxUint32 {
+		return errors.New("input is too large")
+	}
+	doc.buf = nil
+	doc.tape = doc.tape[:0]
+
+	d := &doc.d
+	d.ResetBytes(data)
+	if err := doc.value(); err != nil {
+		doc.tape = doc.tape[:0]
+		return err
+	}
+	if err := d.Skip(); err != io.EOF {
+		doc.tape = doc.tape[:0]
+		return errors.Wrap(err, "unexpected trialing data")
+	}
+	doc.buf = data
+	return nil
+}
+
+// Root returns root value of document.
+//
+// Returns zero Node if document is not parsed.
+func (doc *Doc) Root() Node {
+	if len(doc.tape) == 0 {
+		return Node{}
+	}
+	return Node{doc: doc}
+}
+
+// value appends entries of value to tape.
+func (doc *Doc) value() error {
+	d := &doc.d
+	c, err := d.next()
+	if err != nil {
+		return err
+	}
+	i := len(doc.tape)
+	doc.tape = append(doc.tape, tapeEntry{
+		start: uint32(d.head - 1),
+		typ:   uint8(types[c]),
+	})
+
+	var n int
+	switch c {
+	case '{':
+		n, err = doc.obj()
+	case '[':
+		n, err = doc.arr()
+	default:
+		d.unread()
+		err = d.Skip()
+	}
+	if err != nil {
+		return err
+	}
+
+	e := &doc.tape[i]
+	e.end = uint32(d.head)
+	e.next = uint32(len(doc.tape))
+	e.n = uint32(n)
+	return nil
+}
+
+// obj appends entries of object members to tape, returning their count.
+//
+// Assumes first bracket was consumed.
+func (doc *Doc) obj() (int, error) {
+	d := &doc.d
+	if err := d.incDepth(); err != nil {
+		return 0, errors.Wrap(err, "inc")
+	}
+	c, err := d.more()
+	if err != nil {
+		return 0, errors.Wrap(err, `'"' or "}" expected`)
+	}
+	if c == '}' {
+		return 0, d.decDepth()
+	}
+	for n := 1; ; n++ {
+		if c != '"' {
+			err := d.badToken(c, d.offset()-1)
+			return 0, errors.Wrap(err, `'"' expected`)
+		}
+		i := len(doc.tape)
+		doc.tape = append(doc.tape, tapeEntry{
+			start: uint32(d.head - 1),
+			typ:   uint8(String),
+		})
+		if err := d.skipStr(); err != nil {
+			return 0, errors.Wrap(err, "field name")
+		}
+		doc.tape[i].end = uint32(d.head)
+		doc.tape[i].next = uint32(i + 1)
+
+		if err := d.consume(':'); err != nil {
+			return 0, errors.Wrap(err, `":" expected`)
+		}
+		if err := doc.value(); err != nil {
+			return 0, errors.Wrap(err, "object value")
+		}
+
+		c, err = d.more()
+		if err != nil {
+			return 0, errors.Wrap(err, `"," or "}" expected`)
+		}
+		switch c {
+		case '}':
+			return n, d.decDepth()
+		case ',':
+		default:
+			err := d.badToken(c, d.offset()-1)
+			return 0, errors.Wrap(err, `"," or "}" expected`)
+		}
+		if err := d.checkObjLen(n + 1); err != nil {
+			return 0, err
+		}
+		if c, err = d.more(); err != nil {
+			return 0, errors.Wrap(err, `'"' expected`)
+		}
+	}
+}
+
+// arr appends entries of array elements to tape, returning their count.
+//
+// Assumes first bracket was consumed.
+func (doc *Doc) arr() (int, error) {
+	d := &doc.d
+	if err := d.incDepth(); err != nil {
+		return 0, errors.Wrap(err, "inc")
+	}
+	c, err := d.more()
+	if err != nil {
+		return 0, errors.Wrap(err, `value or "]" expected`)
+	}
+	if c == ']' {
+		return 0, d.decDepth()
+	}
+	d.unread()
+	for n := 1; ; n++ {
+		if err := doc.value(); err != nil {
+			return 0, errors.Wrap(err, "array value")
+		}
+
+		c, err := d.more()
+		if err != nil {
+			return 0, errors.Wrap(err, `"," or "]" expected`)
+		}
+		switch c {
+		case ']':
+			return n, d.decDepth()
+		case ',':
+		default:
+			err := d.badToken(c, d.offset()-1)
+			return 0, errors.Wrap(err, `"," or "]" expected`)
+		}
+		if err := d.checkArrLen(n + 1); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// Node is value of Doc.
+//
+// Zero value is invalid value.
+type Node struct {
+	doc *Doc
+	i   int // index of entry in tape
+}
+
+func (n Node) entry() tapeEntry {
+	if n.doc == nil {
+		return tapeEntry{}
+	}
+	return n.doc.tape[n.i]
+}
+
+// Type returns type of value.
+func (n Node) Type() Type {
+	return Type(n.entry().typ)
+}
+
+// Raw returns value as raw json.
+//
+// Do not modify returned value, it references input of Doc.
+func (n Node) Raw() Raw {
+	if n.doc == nil {
+		return nil
+	}
+	e := n.entry()
+	return n.doc.buf[e.start:e.end]
+}
+
+// Len returns count of object members or array elements.
+//
+// Returns zero for other types.
+func (n Node) Len() int {
+	return int(n.entry().n)
+}
+
+// Get returns value of object field.
+//
+// If object has duplicate fields, first one is returned. Returns
+// ErrNotFound if field does not exist or value is not object.
+func (n Node) Get(key string) (Node, error) {
+	if t := n.Type(); t != Object {
+		return Node{}, errors.Wrapf(ErrNotFound, "%s has no %q", t, key)
+	}
+	v, ok := n.field(func(k []byte) bool {
+		return string(k) == key
+	})
+	if !ok {
+		return Node{}, errors.Wrapf(ErrNotFound, "field %q", key)
+	}
+	return v, nil
+}
+
+// Index returns array element by index.
+//
+// Offsets of elements are not recorded, so Index is O(idx): preceding
+// elements are skipped in O(1) each. Use Arr to iterate over elements.
+//
+// Returns ErrNotFound if index is out of range or value is not array.
+func (n Node) Index(idx int) (Node, error) {
+	e := n.entry()
+	if t := Type(e.typ); t != Array {
+		return Node{}, errors.Wrapf(ErrNotFound, "%s has no index %d", t, idx)
+	}
+	if idx < 0 || idx >= int(e.n) {
+		return Node{}, errors.Wrapf(ErrNotFound, "index %d", idx)
+	}
+	tape := n.doc.tape
+	i := n.i + 1
+	for ; idx > 0; idx-- {
+		i = int(tape[i].next)
+	}
+	return Node{doc: n.doc, i: i}, nil
+}
+
+// Pointer returns value referenced by JSON Pointer, as defined in RFC 6901.
+//
+// Returns ErrNotFound if referenced value does not exist.
+func (n Node) Pointer(ptr string) (Node, error) {
+	if ptr == "" {
+		return n, nil
+	}
+	if ptr[0] != '/' {
+		return Node{}, errors.Errorf("invalid pointer %q: must start with %q", ptr, "/")
+	}
+	ptr = ptr[1:]
+	for {
+		tok, rest, more := cutPointer(ptr)
+		if err := validatePointerToken(tok); err != nil {
+			return Node{}, errors.Wrapf(err, "invalid pointer token %q", tok)
+		}
+		switch t := n.Type(); t {
+		case Object:
+			v, ok := n.field(func(k []byte) bool {
+				return pointerTokenEqual(tok, k)
+			})
+			if !ok {
+				return Node{}, errors.Wrapf(ErrNotFound, "field %q", unescapePointer(tok))
+			}
+			n = v
+		case Array:
+			idx, err := pointerIndex(tok)
+			if err != nil {
+				return Node{}, err
+			}
+			if n, err = n.Index(idx); err != nil {
+				return Node{}, err
+			}
+		default:
+			return Node{}, errors.Wrapf(ErrNotFound, "%s has no %q", t, tok)
+		}
+		if !more {
+			return n, nil
+		}
+		ptr = rest
+	}
+}
+
+// field returns value of first object field, which key matches.
+func (n Node) field(match func(key []byte) bool) (Node, bool) {
+	var (
+		tape    = n.doc.tape
+		end     = int(tape[n.i].next)
+		scratch []byte // reused for keys with escape sequences
+	)
+	for i := n.i + 1; i < end; i = int(tape[i+1].next) {
+		key, escaped := n.doc.key(tape[i], scratch[:0])
+		if escaped {
+			scratch = key
+		}
+		if match(key) {
+			return Node{doc: n.doc, i: i + 1}, true
+		}
+	}
+	return Node{}, false
+}
+
+// key returns unescaped object key, appending it to buf if key has
+// escape sequences, so buf can be reused for next key.
+func (doc *Doc) key(e tapeEntry, buf []byte) (key []byte, escaped bool) {
+	raw := doc.buf[e.start+1 : e.end-1]
+	if bytes.IndexByte(raw, '\\') < 0 {
+		return raw, false
+	}
+	d := Decoder{buf: doc.buf[e.start:e.end], tail: int(e.end - e.start)}
+	// Key is validated by Parse.
+	buf, _ = d.StrAppend(buf)
+	return buf, true
+}
+
+// Obj calls f for every member of object.
+//
+// Key is valid only until f is returned. Returns error if value is not
+// object.
+func (n Node) Obj(f func(key []byte, v Node) error) error {
+	if t := n.Type(); t != Object {
+		return errors.Errorf("unexpected type %s, expected %s", t, Object)
+	}
+	var (
+		tape    = n.doc.tape
+		end     = int(tape[n.i].next)
+		scratch []byte // reused for keys with escape sequences
+	)
+	for i := n.i + 1; i < end; i = int(tape[i+1].next) {
+		key, escaped := n.doc.key(tape[i], scratch[:0])
+		if escaped {
+			scratch = key
+		}
+		if err := f(key, Node{doc: n.doc, i: i + 1}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Arr calls f for every element of array.
+//
+// Returns error if value is not array.
+func (n Node) Arr(f func(v Node) error) error {
+	if t := n.Type(); t != Array {
+		return errors.Errorf("unexpected type %s, expected %s", t, Array)
+	}
+	var (
+		tape = n.doc.tape
+		end  = int(tape[n.i].next)
+	)
+	for i := n.i + 1; i < end; i = int(tape[i].next) {
+		if err := f(Node{doc: n.doc, i: i}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Str decodes string value.
+func (n Node) Str() (string, error) {
+	return n.Raw().Str()
+}
+
+// Num decodes number value.
+//
+// Returned value references input of Doc.
+func (n Node) Num() (Num, error) {
+	return n.Raw().Num()
+}
+
+// Int64 decodes integer value.
+func (n Node) Int64() (int64, error) {
+	return n.Raw().Int64()
+}
+
+// Float64 decodes number value.
+func (n Node) Float64() (float64, error) {
+	return n.Raw().Float64()
+}
+
+// Bool decodes boolean value.
+func (n Node) Bool() (bool, error) {
+	return n.Raw().Bool()
+}
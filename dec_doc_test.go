@@ -0,0 +1,265 @@
+package jx
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// nodeAny converts Node to Any, checking Node methods.
+func nodeAny(t *testing.T, n Node) Any {
+	t.Helper()
+
+	var (
+		v   Any
+		err error
+	)
+	switch n.Type() {
+	case Object:
+		v.Type = AnyObj
+		err = n.Obj(func(key []byte, n Node) error {
+			child := nodeAny(t, n)
+			child.Key = string(key)
+			child.KeyValid = true
+			v.Child = append(v.Child, child)
+			return nil
+		})
+	case Array:
+		v.Type = AnyArr
+		var elems []Node
+		err = n.Arr(func(n Node) error {
+			elems = append(elems, n)
+			v.Child = append(v.Child, nodeAny(t, n))
+			return nil
+		})
+		for i, expected := range elems {
+			if elem, err := n.Index(i); err != nil || elem != expected {
+				t.Fatalf("Index(%d): %v, %v", i, elem, err)
+			}
+		}
+	case String:
+		v.Type = AnyStr
+		v.Str, err = n.Str()
+	case Number:
+		v.Type = AnyNumber
+		v.Number, err = n.Num()
+	case Bool:
+		v.Type = AnyBool
+		v.Bool, err = n.Bool()
+	case Null:
+		v.Type = AnyNull
+	default:
+		t.Fatalf("unexpected type %s", n.Type())
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(v.Child) != n.Len() {
+		t.Fatalf("Len: %d != %d", n.Len(), len(v.Child))
+	}
+	return v
+}
+
+func TestDoc(t *testing.T) {
+	runTestdata(t.Fatal, func(name string, data []byte) {
+		t.Run(name, func(t *testing.T) {
+			doc, err := ParseDoc(data)
+			require.NoError(t, err)
+
+			expected, err := DecodeBytes(data).Any()
+			require.NoError(t, err)
+			require.True(t, expected.Equal(nodeAny(t, doc.Root())))
+
+			// Raw values of members are decoded to the same values.
+			if root := doc.Root(); root.Type() == Object {
+				require.NoError(t, root.Obj(func(key []byte, v Node) error {
+					expected, err := DecodeBytes(v.Raw()).Any()
+					require.NoError(t, err)
+					require.True(t, expected.Equal(nodeAny(t, v)))
+					return nil
+				}))
+			}
+		})
+	})
+}
+
+func TestDoc_Pointer(t *testing.T) {
+	doc, err := ParseDoc([]byte(rfc6901Doc))
+	require.NoError(t, err)
+	root := doc.Root()
+
+	for i, tt := range []struct {
+		ptr    string
+		expect string
+	}{
+		{"", rfc6901Doc},
+		{"/foo", `["bar", "baz"]`},
+		{"/foo/0", `"bar"`},
+		{"/foo/1", `"baz"`},
+		{"/", `0`},
+		{"/a~1b", `1`},
+		{"/i\\j", `5`},
+		{"/k\"l", `6`},
+		{"/ ", `7`},
+		{"/m~0n", `8`},
+		{"/escaped/nested/1/x", `true`},
+		{"/escaped/nested/0", `null`},
+	} {
+		tt := tt
+		t.Run(fmt.Sprintf("Test%d", i+1), func(t *testing.T) {
+			v, err := root.Pointer(tt.ptr)
+			require.NoError(t, err)
+			require.Equal(t, tt.expect, v.Raw().String())
+		})
+	}
+	for _, ptr := range []string{
+		"/bar",
+		"/foo/2",
+		"/foo/-",
+		"/foo/0/bar",
+		"/escaped/nested/1/y",
+	} {
+		_, err := root.Pointer(ptr)
+		require.ErrorIs(t, err, ErrNotFound, ptr)
+	}
+	for _, ptr := range []string{
+		"foo",
+		"/m~2n",
+		"/foo/01",
+	} {
+		_, err := root.Pointer(ptr)
+		require.Error(t, err, ptr)
+		require.NotErrorIs(t, err, ErrNotFound, ptr)
+	}
+}
+
+func TestDoc_Get(t *testing.T) {
+	a := require.New(t)
+	doc, err := ParseDoc([]byte(`{"a":{"b":[1,{"c":2}]},"d":"e","a":3}`))
+	a.NoError(err)
+	root := doc.Root()
+	a.Equal(Object, root.Type())
+	a.Equal(3, root.Len())
+
+	// First duplicate field is returned.
+	v, err := root.Get("a")
+	a.NoError(err)
+	a.Equal(`{"b":[1,{"c":2}]}`, v.Raw().String())
+
+	// Keys are unescaped.
+	v, err = root.Get("d")
+	a.NoError(err)
+	s, err := v.Str()
+	a.NoError(err)
+	a.Equal("e", s)
+
+	v, err = root.Pointer("/a/b/1/c")
+	a.NoError(err)
+	n, err := v.Int64()
+	a.NoError(err)
+	a.Equal(int64(2), n)
+	f, err := v.Float64()
+	a.NoError(err)
+	a.Equal(2.0, f)
+
+	_, err = root.Get("x")
+	a.ErrorIs(err, ErrNotFound)
+	_, err = root.Index(0)
+	a.ErrorIs(err, ErrNotFound)
+	_, err = v.Get("x")
+	a.ErrorIs(err, ErrNotFound)
+	a.Error(v.Obj(func(key []byte, v Node) error { return nil }))
+	a.Error(v.Arr(func(v Node) error { return nil }))
+	_, err = v.Str()
+	a.Error(err)
+	_, err = v.Bool()
+	a.Error(err)
+
+	arr, err := root.Pointer("/a/b")
+	a.NoError(err)
+	for _, idx := range []int{-1, 2} {
+		_, err = arr.Index(idx)
+		a.ErrorIs(err, ErrNotFound)
+	}
+
+	// Errors of callbacks are returned.
+	testErr := fmt.Errorf("test")
+	a.ErrorIs(root.Obj(func(key []byte, v Node) error { return testErr }), testErr)
+	a.ErrorIs(arr.Arr(func(v Node) error { return testErr }), testErr)
+}
+
+func TestDoc_Parse(t *testing.T) {
+	t.Run("Reuse", func(t *testing.T) {
+		a := require.New(t)
+		var doc Doc
+		a.Equal(Invalid, doc.Root().Type())
+		a.Nil(doc.Root().Raw())
+
+		a.NoError(doc.Parse([]byte(`[1,2,3]`)))
+		a.Equal(3, doc.Root().Len())
+		a.NoError(doc.Parse([]byte(` "foo" `)))
+		a.Equal(String, doc.Root().Type())
+		a.Equal(`"foo"`, doc.Root().Raw().String())
+
+		a.Error(doc.Parse([]byte(`[1,2`)))
+		a.Equal(Invalid, doc.Root().Type())
+	})
+	t.Run("Limits", func(t *testing.T) {
+		var doc Doc
+		doc.SetLimits(Limits{Depth: 2, ObjLen: 2})
+		require.NoError(t, doc.Parse([]byte(`{"a":[1],"b":2}`)))
+		require.ErrorIs(t, doc.Parse([]byte(`[[[1]]]`)), ErrMaxDepth)
+		require.ErrorIs(t, doc.Parse([]byte(`{"a":1,"b":2,"c":3}`)), ErrMaxObjLen)
+	})
+	t.Run("Error", func(t *testing.T) {
+		for _, input := range []string{
+			``,
+			` `,
+			`{`,
+			`{"a"}`,
+			`{"a":}`,
+			`{"a":1,}`,
+			`{"a":1 "b":2}`,
+			`{1:1}`,
+			`[1,]`,
+			`[1 2]`,
+			`[1,2`,
+			`"foo`,
+			`tru`,
+			`1 2`,
+			`{} x`,
+		} {
+			_, err := ParseDoc([]byte(input))
+			require.Error(t, err, input)
+		}
+	})
+}
+
+func TestDoc_KeyAllocs(t *testing.T) {
+	for _, tt := range []struct {
+		input  string
+		allocs float64
+	}{
+		{`{"a":1,"b":2,"c":3,"d":4}`, 0},
+		// Buffer of escaped keys is reused.
+		{`{"\u0061":1,"\u0062":2,"\u0063":3,"\u0064":4}`, 1},
+	} {
+		a := require.New(t)
+		doc, err := ParseDoc([]byte(tt.input))
+		a.NoError(err)
+		root := doc.Root()
+
+		a.LessOrEqual(testing.AllocsPerRun(100, func() {
+			if _, err := root.Get("d"); err != nil {
+				t.Fatal(err)
+			}
+		}), tt.allocs)
+		var keys []string
+		a.NoError(root.Obj(func(key []byte, v Node) error {
+			keys = append(keys, string(key))
+			return nil
+		}))
+		a.Equal([]string{"a", "b", "c", "d"}, keys)
+	}
+}
@@ -1,17 +1,383 @@
 package jx
 
-import "fmt"
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
 
-// badTokenErr means that Token was unexpected while decoding.
-type badTokenErr struct {
+	"github.com/go-faster/errors"
+)
+
+// SyntaxError means that Token was unexpected while decoding.
+//
+// Use errors.As to get position of invalid input.
+type SyntaxError struct {
 	Token  byte
-	Offset int
+	Offset int // offset of Token in input, starting from 0
+
+	// Line and Column of Token, starting from 1.
+	//
+	// Column is counted in bytes. Both are zero if position is unknown.
+	Line   int
+	Column int
+
+	// Snippet of input line around Token, if available.
+	Snippet string
+
+	owner *byte // see Decoder.errOwner
+}
+
+func (e *SyntaxError) Error() string {
+	if e.Line == 0 {
+		return fmt.Sprintf("unexpected byte %d %q at %d", e.Token, e.Token, e.Offset)
+	}
+	return fmt.Sprintf("unexpected byte %d %q at %d (line %d, column %d)",
+		e.Token, e.Token, e.Offset, e.Line, e.Column,
+	)
+}
+
+// badToken creates SyntaxError with position of offset.
+func (d *Decoder) badToken(c byte, offset int) error {
+	return d.syntaxError(c, offset)
+}
+
+func (d *Decoder) syntaxError(c byte, offset int) *SyntaxError {
+	e := &SyntaxError{Token: c, Offset: offset, owner: d.errOwner()}
+	e.Line, e.Column = d.position(offset)
+	e.Snippet = d.snippet(offset)
+	return e
+}
+
+// linePos is line counter state at offset.
+type linePos struct {
+	offset int // offset in stream
+	line   int // count of newlines before offset
+	start  int // offset of current line start
+}
+
+func (p linePos) count(buf []byte) linePos {
+	if n := bytes.Count(buf, []byte{'\n'}); n > 0 {
+		p.line += n
+		p.start = p.offset + bytes.LastIndexByte(buf, '\n') + 1
+	}
+	p.offset += len(buf)
+	return p
+}
+
+// discardLines returns line counter state after first n bytes of buffer,
+// which are about to be discarded.
+//
+// Only running count of lines is kept for discarded input, position in
+// current buffer is computed lazily by position.
+func (d *Decoder) discardLines(n int) linePos {
+	return d.lineBase.count(d.buf[:n])
+}
+
+// position returns line and column of offset.
+func (d *Decoder) position(offset int) (line, column int) {
+	p := d.lineBase
+	if last := d.lineLast; last.offset >= p.offset && last.offset <= offset {
+		// Continue from last computed position in current buffer.
+		p = last
+	}
+	from, to := p.offset-d.streamOffset, offset-d.streamOffset
+	if to > d.tail {
+		to = d.tail
+	}
+	if from >= 0 && from < to {
+		p = p.count(d.buf[from:to])
+		d.lineLast = p
+	}
+	if offset < p.start {
+		// Token is newline in previous buffer.
+		return p.line, 0
+	}
+	return p.line + 1, offset - p.start + 1
+}
+
+// snippet returns part of line around offset, if it is still in buffer.
+func (d *Decoder) snippet(offset int) string {
+	const maxWidth = 32
+	pos := offset - d.streamOffset
+	if pos < 0 || pos >= d.tail {
+		return ""
+	}
+	start, end := pos, pos
+	for start > 0 && pos-start < maxWidth && d.buf[start-1] != '\n' {
+		start--
+	}
+	for end < d.tail && end-pos < maxWidth && d.buf[end] != '\n' {
+		end++
+	}
+	return string(d.buf[start:end])
+}
+
+// DecodeError is error of decoding value at some path in json document.
+//
+// Path is collected while error propagates out of Obj, ObjBytes, Arr
+// and iterators, so it is relative to the value on which decoding started.
+// Other errors, returned by callbacks, are kept without path.
+// Use errors.As to get DecodeError from returned error.
+//
+// Unexpected end of input, exceeded Limits and type mismatch are also
+// reported as DecodeError, even outside of objects and arrays.
+type DecodeError struct {
+	Offset int // offset of error in input, starting from 0
+
+	// Line and Column of Offset, starting from 1.
+	//
+	// Column is counted in bytes. Both are zero if position is unknown.
+	Line   int
+	Column int
+
+	// Expected and Actual types of value.
+	//
+	// Expected is Invalid if error is not caused by type mismatch.
+	Expected Type
+	Actual   Type
+
+	Err error
+
+	// path from outermost element, shared with errors of inner levels.
+	path *pathNode
+	// local is count of path elements written by Error, the rest is
+	// written by DecodeError wrapped in Err if nested is set.
+	local  int
+	nested bool
+	owner  *byte // see Decoder.errOwner
+}
+
+// pathElem is object key or array index.
+type pathElem struct {
+	key   string
+	index int // -1 for key
 }
 
-func (e *badTokenErr) Error() string {
-	return fmt.Sprintf("unexpected byte %d %q at %d", e.Token, e.Token, e.Offset)
+// pathNode is element of path list.
+type pathNode struct {
+	elem pathElem
+	next *pathNode
 }
 
-func badToken(c byte, offset int) error {
-	return &badTokenErr{Token: c, Offset: offset}
+// Path returns JSONPath of invalid value, like $.items[3].price.
+func (e *DecodeError) Path() string {
+	return e.jsonPath(-1)
 }
+
+// jsonPath returns JSONPath of first n elements of path, or of whole path
+// if n is negative.
+func (e *DecodeError) jsonPath(n int) string {
+	var b strings.Builder
+	b.WriteByte('$')
+	for node := e.path; node != nil && n != 0; node, n = node.next, n-1 {
+		p := node.elem
+		if p.index >= 0 {
+			b.WriteByte('[')
+			b.WriteString(strconv.Itoa(p.index))
+			b.WriteByte(']')
+			continue
+		}
+		if isPathShorthand(p.key) {
+			b.WriteByte('.')
+			b.WriteString(p.key)
+			continue
+		}
+		b.WriteString("['")
+		for _, r := range p.key {
+			switch {
+			case r == '\'' || r == '\\':
+				b.WriteByte('\\')
+				b.WriteRune(r)
+			case r < 0x20:
+				fmt.Fprintf(&b, `\u%04x`, r)
+			default:
+				b.WriteRune(r)
+			}
+		}
+		b.WriteString("']")
+	}
+	return b.String()
+}
+
+// Pointer returns RFC 6901 JSON Pointer of invalid value, like /items/3/price.
+func (e *DecodeError) Pointer() string {
+	var b strings.Builder
+	for node := e.path; node != nil; node = node.next {
+		p := node.elem
+		b.WriteByte('/')
+		if p.index >= 0 {
+			b.WriteString(strconv.Itoa(p.index))
+			continue
+		}
+		b.WriteString(escapePointer(p.key))
+	}
+	return b.String()
+}
+
+func (e *DecodeError) Error() string {
+	var b strings.Builder
+	if e.local > 0 {
+		b.WriteString(e.jsonPath(e.local))
+		b.WriteString(": ")
+	}
+	if e.nested {
+		// Wrapped error is written with the rest of path.
+		b.WriteString(e.Err.Error())
+		return b.String()
+	}
+	if e.Expected != Invalid {
+		fmt.Fprintf(&b, "expected %s, got %s: ", e.Expected, e.Actual)
+	}
+	b.WriteString(e.Err.Error())
+	if se := (*SyntaxError)(nil); e.Line > 0 && !errors.As(e.Err, &se) {
+		fmt.Fprintf(&b, " at %d (line %d, column %d)", e.Offset, e.Line, e.Column)
+	}
+	return b.String()
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// isPathShorthand reports whether key can be written as .key in JSONPath.
+func isPathShorthand(key string) bool {
+	if key == "" {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		isFirst := c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+		isDigit := c >= '0' && c <= '9'
+		if !isFirst && (!isDigit || i == 0) {
+			return false
+		}
+	}
+	return true
+}
+
+// errOwner returns token, which marks errors created by d.
+//
+// Path is attached only to errors of d, not to errors of other decoders,
+// even if they are returned by callback.
+func (d *Decoder) errOwner() *byte {
+	if d.owner == nil {
+		d.owner = new(byte)
+	}
+	return d.owner
+}
+
+// errorAt returns DecodeError for err at offset, with line and column.
+func (d *Decoder) errorAt(err error, offset int) error {
+	e := &DecodeError{Offset: offset, Err: err, owner: d.errOwner()}
+	e.Line, e.Column = d.position(offset)
+	return e
+}
+
+// unexpectedEOF returns io.ErrUnexpectedEOF at end of input.
+func (d *Decoder) unexpectedEOF() error {
+	return d.errorAt(io.ErrUnexpectedEOF, d.streamOffset+d.tail)
+}
+
+// typeError returns error for value of unexpected type, starting with c at offset.
+//
+// Returns SyntaxError if c does not start any value.
+func (d *Decoder) typeError(expected Type, c byte, offset int) error {
+	se := d.syntaxError(c, offset)
+	actual := types[c]
+	if actual == Invalid {
+		return se
+	}
+	return &DecodeError{
+		Offset:   offset,
+		Line:     se.Line,
+		Column:   se.Column,
+		Expected: expected,
+		Actual:   actual,
+		Err:      se,
+		owner:    se.owner,
+	}
+}
+
+// fieldError attaches key to path of error, returned by decoding value of
+// type t.
+func (d *Decoder) fieldError(err error, key []byte, t Type) error {
+	return d.pathError(err, pathElem{key: string(key), index: -1}, t)
+}
+
+// elemError attaches array index to path of error, returned by decoding value
+// of type t.
+func (d *Decoder) elemError(err error, index int, t Type) error {
+	return d.pathError(err, pathElem{index: index}, t)
+}
+
+// pathError attaches elem to path of error, returned by decoding value of
+// type t.
+//
+// Path is attached only to errors created by d, other errors are returned
+// as is, only marked as already checked.
+func (d *Decoder) pathError(err error, elem pathElem, t Type) error {
+	// Callbacks can wrap error on each level, so only wrappers added since
+	// previous level are checked, until error returned by it.
+	owner := d.errOwner()
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		switch e := e.(type) {
+		case *DecodeError:
+			if e.owner != owner {
+				return &opaqueError{err: err}
+			}
+			node := &pathNode{elem: elem, next: e.path}
+			if e == err {
+				// Not wrapped, so path can be merged.
+				c := *e
+				c.path, c.local = node, e.local+1
+				return &c
+			}
+			return &DecodeError{
+				Offset:   e.Offset,
+				Line:     e.Line,
+				Column:   e.Column,
+				Expected: e.Expected,
+				Actual:   e.Actual,
+				Err:      err,
+				path:     node,
+				local:    1,
+				nested:   true,
+				owner:    owner,
+			}
+		case *SyntaxError:
+			if e.owner != owner {
+				return &opaqueError{err: err}
+			}
+			return &DecodeError{
+				Offset: e.Offset,
+				Line:   e.Line,
+				Column: e.Column,
+				Actual: t,
+				Err:    err,
+				path:   &pathNode{elem: elem},
+				local:  1,
+				owner:  owner,
+			}
+		case *opaqueError:
+			if e == err {
+				return err
+			}
+			return &opaqueError{err: err}
+		}
+	}
+	return &opaqueError{err: err}
+}
+
+// opaqueError is error without path, returned from callback of container.
+//
+// It marks error as checked, so pathError of outer containers stops
+// searching here.
+type opaqueError struct {
+	err error
+}
+
+func (e *opaqueError) Error() string { return e.err.Error() }
+
+func (e *opaqueError) Unwrap() error { return e.err }
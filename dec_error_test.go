@@ -1,16 +1,353 @@
 package jx
 
 import (
+	"io"
+	"strconv"
+	"strings"
 	"testing"
 
+	"github.com/go-faster/errors"
 	"github.com/stretchr/testify/require"
 )
 
-func Test_badTokenErr_Error(t *testing.T) {
-	e := &badTokenErr{
+func Test_SyntaxError_Error(t *testing.T) {
+	e := &SyntaxError{
 		Token:  'c',
 		Offset: 10,
 	}
 	s := error(e).Error()
 	require.Equal(t, "unexpected byte 99 'c' at 10", s)
+
+	e.Line = 2
+	e.Column = 3
+	require.Equal(t, "unexpected byte 99 'c' at 10 (line 2, column 3)", e.Error())
+}
+
+func TestSyntaxError_Position(t *testing.T) {
+	for _, tt := range []struct {
+		input        string
+		line, column int
+		snippet      string
+	}{
+		{"[1, x]", 1, 5, "[1, x]"},
+		{"{\n  \"a\": [1,\n    2,\n    x]\n}", 4, 5, "    x]"},
+		{"\n\n\n\"foo\" bar", 4, 7, `"foo" bar`},
+		{"[\r\n\r\nnul1]", 3, 4, "nul1]"},
+		{"[\n" + strings.Repeat(" ", 100) + "tru]", 2, 104, strings.Repeat(" ", 29) + "tru]"},
+		{"[1,\n2,\n3}", 3, 2, "3}"},
+	} {
+		tt := tt
+		t.Run(tt.input, testBufferReader(tt.input, func(t *testing.T, d *Decoder) {
+			a := require.New(t)
+			err := d.Validate()
+			a.Error(err)
+
+			e, ok := errors.Into[*SyntaxError](err)
+			a.True(ok, "%+v", err)
+			a.Equal(tt.input[e.Offset], e.Token)
+			a.Equal(tt.line, e.Line)
+			a.Equal(tt.column, e.Column)
+			a.Contains(tt.snippet, e.Snippet)
+			if d.reader == nil {
+				a.Equal(tt.snippet, e.Snippet)
+			}
+		}))
+	}
+}
+
+func TestDecodeError_Position(t *testing.T) {
+	readStr := func(d *Decoder) error { _, err := d.Str(); return err }
+	for _, tt := range []struct {
+		input        string
+		limits       Limits
+		fn           func(d *Decoder) error
+		err          error
+		line, column int
+	}{
+		{"[1,\n2,\n", Limits{}, (*Decoder).Validate, io.ErrUnexpectedEOF, 3, 1},
+		{"{\"a\":\n  \"b", Limits{}, (*Decoder).Validate, io.ErrUnexpectedEOF, 2, 5},
+		{"\n  \"abc", Limits{}, readStr, io.ErrUnexpectedEOF, 2, 7},
+		{"[\n[\n[1]]]", Limits{Depth: 2}, (*Decoder).Validate, ErrMaxDepth, 3, 2},
+		{"[1,\n 2,\n 3]", Limits{ArrLen: 2}, (*Decoder).Validate, ErrMaxArrLen, 2, 4},
+		{"\n\n\"abcd\"", Limits{StrLen: 2}, readStr, ErrMaxStrLen, 3, 2},
+		{"[1,\n22]", Limits{Bytes: 6}, (*Decoder).Validate, ErrMaxBytes, 2, 3},
+		{"\n\"\xff\"", Limits{}, func(d *Decoder) error {
+			d.SetUTF8Mode(UTF8Strict)
+			return readStr(d)
+		}, ErrInvalidUTF8, 2, 2},
+		{"\n  1", Limits{}, readStr, nil, 2, 3},
+		{"[\n 1000]", Limits{}, func(d *Decoder) error {
+			return d.Arr(func(d *Decoder) error { _, err := d.Int8(); return err })
+		}, errOverflow, 2, 2},
+		{"\n -129", Limits{}, func(d *Decoder) error { _, err := d.Int8(); return err }, errOverflow, 2, 2},
+		{"\n  1e400", Limits{}, func(d *Decoder) error { _, err := d.Float64(); return err }, strconv.ErrRange, 2, 3},
+		{"\n \"!!\"", Limits{}, func(d *Decoder) error { _, err := d.Base64(); return err }, nil, 2, 2},
+		{"\n  +1000", Limits{}, func(d *Decoder) error {
+			d.SetJSON5(true)
+			_, err := d.Int8()
+			return err
+		}, errOverflow, 2, 3},
+		{"\n  NaN", Limits{}, func(d *Decoder) error {
+			d.SetJSON5(true)
+			_, err := d.Int()
+			return err
+		}, nil, 2, 3},
+	} {
+		tt := tt
+		t.Run(strings.ReplaceAll(tt.input, "\n", " "), testBufferReader(tt.input, func(t *testing.T, d *Decoder) {
+			a := require.New(t)
+			d.SetLimits(tt.limits)
+			err := tt.fn(d)
+			if tt.err != nil {
+				a.ErrorIs(err, tt.err)
+			}
+
+			e, ok := errors.Into[*DecodeError](err)
+			a.True(ok, "%+v", err)
+			a.Equal(tt.line, e.Line, "%+v", err)
+			a.Equal(tt.column, e.Column, "%+v", err)
+			a.Contains(e.Error(), "line")
+		}))
+	}
+}
+
+func TestDecoder_position(t *testing.T) {
+	const input = "a\nbc\n\ndef\n"
+	expect := func(offset int) (line, column int) {
+		before := input[:offset]
+		line = strings.Count(before, "\n") + 1
+		column = offset - (strings.LastIndexByte(before, '\n') + 1) + 1
+		return line, column
+	}
+	d := DecodeStr(input)
+	// Check both forward and backward order to test cached positions.
+	for _, offset := range []int{0, 3, 2, 9, 7, 1, 8, 6, 4, 5} {
+		line, column := d.position(offset)
+		expectLine, expectColumn := expect(offset)
+		require.Equal(t, expectLine, line, offset)
+		require.Equal(t, expectColumn, column, offset)
+	}
+}
+
+func TestDecodeError(t *testing.T) {
+	decodeItems := func(d *Decoder) error {
+		return d.Obj(func(d *Decoder, key string) error {
+			return d.Arr(func(d *Decoder) error {
+				return d.Obj(func(d *Decoder, key string) error {
+					_, err := d.Float64()
+					return err
+				})
+			})
+		})
+	}
+	t.Run("Type", func(t *testing.T) {
+		const input = `{"items":[{"price":1},{"price":2},{"price":3},{"id":4,"price":"x"}]}`
+		testBufferReader(input, func(t *testing.T, d *Decoder) {
+			a := require.New(t)
+			err := decodeItems(d)
+
+			e, ok := errors.Into[*DecodeError](err)
+			a.True(ok, "%+v", err)
+			a.Equal("$.items[3].price", e.Path())
+			a.Equal("/items/3/price", e.Pointer())
+			a.Equal(Number, e.Expected)
+			a.Equal(String, e.Actual)
+			a.Equal(strings.Index(input, `"x"`), e.Offset)
+			a.Equal(`$.items[3].price: expected number, got string: `+
+				`unexpected byte 34 '"' at 62 (line 1, column 63)`, e.Error())
+		})(t)
+	})
+	t.Run("Syntax", func(t *testing.T) {
+		const input = `{"items":[{"price":1},{"price":1x}]}`
+		testBufferReader(input, func(t *testing.T, d *Decoder) {
+			a := require.New(t)
+			err := decodeItems(d)
+
+			e, ok := errors.Into[*DecodeError](err)
+			a.True(ok, "%+v", err)
+			a.Equal("$.items[1].price", e.Path())
+			a.Equal(Invalid, e.Expected)
+			a.Equal(Number, e.Actual)
+			a.Equal(strings.Index(input, "x"), e.Offset)
+			a.ErrorAs(err, new(*SyntaxError))
+		})(t)
+	})
+	t.Run("Wrapped", func(t *testing.T) {
+		const depth = 10000
+		input := strings.Repeat("[", depth) + "x"
+		testBufferReader(input, func(t *testing.T, d *Decoder) {
+			d.SetLimits(Limits{Depth: depth + 1})
+			var f func(d *Decoder) error
+			f = func(d *Decoder) error {
+				return errors.Wrap(d.Arr(f), "elem")
+			}
+			err := d.Arr(f)
+
+			e, ok := errors.Into[*DecodeError](err)
+			require.True(t, ok, "%+v", err)
+			require.Equal(t, strings.Repeat("/0", depth), e.Pointer())
+			require.Equal(t, depth, e.Offset)
+		})(t)
+	})
+	t.Run("Swallowed", func(t *testing.T) {
+		// Path of ignored error is not attached to next one.
+		err := DecodeStr(`{"a":[1,"x"],"b":[true]}`).Obj(func(d *Decoder, key string) error {
+			if key == "a" {
+				_ = d.Capture(func(d *Decoder) error {
+					return d.Arr(func(d *Decoder) error {
+						_, err := d.Int()
+						return err
+					})
+				})
+				return d.Skip()
+			}
+			return errors.Wrap(d.Arr(func(d *Decoder) error {
+				_, err := d.Int()
+				return err
+			}), "b")
+		})
+		e, ok := errors.Into[*DecodeError](err)
+		require.True(t, ok, "%+v", err)
+		require.Equal(t, "$.b[0]", e.Path())
+	})
+	t.Run("Callback", func(t *testing.T) {
+		// Path is not attached to errors, created outside of decoder.
+		errStop := errors.New("stop")
+		err := DecodeStr(`[{"a b":{"x/y~":[null,true]}}]`).Arr(func(d *Decoder) error {
+			return d.Obj(func(d *Decoder, key string) error {
+				return d.Obj(func(d *Decoder, key string) error {
+					return d.Arr(func(d *Decoder) error {
+						if d.Next() == Bool {
+							return errStop
+						}
+						return d.Skip()
+					})
+				})
+			})
+		})
+		a := require.New(t)
+		a.ErrorIs(err, errStop)
+		a.Equal("stop", err.Error())
+		a.False(errors.As(err, new(*DecodeError)), "%+v", err)
+	})
+	t.Run("Foreign", func(t *testing.T) {
+		// Errors of other decoders are not modified.
+		inner := DecodeStr(`"x"`)
+		_, innerErr := inner.Int()
+		innerMsg := innerErr.Error()
+
+		err := DecodeStr(`{"a":[1]}`).Obj(func(d *Decoder, key string) error {
+			return d.Arr(func(d *Decoder) error {
+				if err := d.Skip(); err != nil {
+					return err
+				}
+				return errors.Wrap(innerErr, "min")
+			})
+		})
+		a := require.New(t)
+		a.ErrorIs(err, innerErr)
+		a.Equal("min: "+innerMsg, err.Error())
+		a.Equal(innerMsg, innerErr.Error())
+
+		e, ok := errors.Into[*DecodeError](err)
+		a.True(ok)
+		a.Same(innerErr, e)
+		a.Equal("$", e.Path())
+	})
+	t.Run("Copy", func(t *testing.T) {
+		// Error returned by inner level is not modified.
+		var innerErr error
+		err := DecodeStr(`{"a":{"b":"x"}}`).Obj(func(d *Decoder, key string) error {
+			innerErr = d.Obj(func(d *Decoder, key string) error {
+				_, err := d.Int()
+				return err
+			})
+			return innerErr
+		})
+		a := require.New(t)
+		e, ok := errors.Into[*DecodeError](err)
+		a.True(ok, "%+v", err)
+		a.Equal("$.a.b", e.Path())
+		a.Equal(`$.a.b: expected number, got string: `+
+			`unexpected byte 34 '"' at 10 (line 1, column 11)`, e.Error())
+
+		e, ok = errors.Into[*DecodeError](innerErr)
+		a.True(ok, "%+v", innerErr)
+		a.Equal("$.b", e.Path())
+	})
+	t.Run("Nested", func(t *testing.T) {
+		// Wrapped errors of inner levels print their part of path.
+		err := DecodeStr(`{"a":[{"b":1},{"b":"x"}]}`).Obj(func(d *Decoder, key string) error {
+			return errors.Wrap(d.Arr(func(d *Decoder) error {
+				return d.Obj(func(d *Decoder, key string) error {
+					_, err := d.Int()
+					return err
+				})
+			}), "items")
+		})
+		a := require.New(t)
+		e, ok := errors.Into[*DecodeError](err)
+		a.True(ok, "%+v", err)
+		a.Equal("$.a[1].b", e.Path())
+		a.Equal("/a/1/b", e.Pointer())
+		a.Equal(Number, e.Expected)
+		a.Equal(String, e.Actual)
+		a.Equal(`$.a: items: $[1].b: expected number, got string: `+
+			`unexpected byte 34 '"' at 19 (line 1, column 20)`, e.Error())
+	})
+	t.Run("Leaf", func(t *testing.T) {
+		for _, tt := range []struct {
+			input    string
+			expected Type
+			fn       func(d *Decoder) error
+		}{
+			{`1`, String, func(d *Decoder) error { _, err := d.Str(); return err }},
+			{`"1"`, Number, func(d *Decoder) error { _, err := d.Int(); return err }},
+			{`"1"`, Number, func(d *Decoder) error { _, err := d.UInt8(); return err }},
+			{`true`, Number, func(d *Decoder) error { _, err := d.Float32(); return err }},
+			{`[1]`, Number, func(d *Decoder) error { _, err := d.Num(); return err }},
+			{`null`, Bool, func(d *Decoder) error { _, err := d.Bool(); return err }},
+			{`false`, Null, func(d *Decoder) error { return d.Null() }},
+			{`[]`, Object, func(d *Decoder) error { return d.ObjBytes(nil) }},
+			{`{}`, Array, func(d *Decoder) error { return d.Arr(nil) }},
+			{`"a"`, Object, func(d *Decoder) error { _, err := d.ObjIter(); return err }},
+			{`"a"`, Array, func(d *Decoder) error { _, err := d.ArrIter(); return err }},
+		} {
+			tt := tt
+			t.Run(tt.input, func(t *testing.T) {
+				a := require.New(t)
+				input := `{"key": ` + tt.input + `}`
+				err := DecodeStr(input).Obj(func(d *Decoder, key string) error {
+					return tt.fn(d)
+				})
+				e, ok := errors.Into[*DecodeError](err)
+				a.True(ok, "%+v", err)
+				a.Equal("$.key", e.Path())
+				a.Equal(tt.expected, e.Expected)
+				a.Equal(types[tt.input[0]], e.Actual)
+				a.Equal(8, e.Offset)
+			})
+		}
+	})
+	t.Run("Iter", func(t *testing.T) {
+		a := require.New(t)
+		iter, err := DecodeStr(`{"a":1,"b":`).ObjIter()
+		a.NoError(err)
+		for iter.Next() {
+			a.NoError(iter.d.Skip())
+		}
+		e, ok := errors.Into[*DecodeError](iter.Err())
+		a.True(ok, "%+v", iter.Err())
+		a.Equal("$.b", e.Path())
+		a.ErrorIs(e, io.ErrUnexpectedEOF)
+
+		arr, err := DecodeStr(`[1,2, `).ArrIter()
+		a.NoError(err)
+		for arr.Next() {
+			a.NoError(arr.d.Skip())
+		}
+		e, ok = errors.Into[*DecodeError](arr.Err())
+		a.True(ok, "%+v", arr.Err())
+		a.Equal("$[2]", e.Path())
+	})
 }
@@ -51,6 +51,20 @@ func (d *Decoder) Float32() (float32, error) {
 	if err != nil {
 		return 0, err
 	}
+	if d.json5 {
+		d.unread()
+		offset := d.offset()
+		num, f, err := d.json5Num()
+		if err != nil || num == nil {
+			return float32(f), err
+		}
+		var sd Decoder
+		d.subDecoder(&sd, num, offset)
+		return sd.Float32()
+	}
+	if t := types[c]; t != Number && t != Invalid {
+		return 0, d.typeError(Number, c, d.offset()-1)
+	}
 	if c != '-' {
 		d.unread()
 	}
@@ -75,12 +89,12 @@ func (d *Decoder) positiveFloat32() (float32, error) {
 	ind := floatDigits[c]
 	switch ind {
 	case invalidCharForNumber, endOfNumber:
-		return 0, badToken(c, d.offset())
+		return 0, d.badToken(c, d.offset())
 	case dotInNumber, plusInNumber, expInNumber:
-		err := badToken(c, d.offset())
+		err := d.badToken(c, d.offset())
 		return 0, errors.Wrapf(err, "leading %q", c)
 	case minusInNumber: // minus handled by caller
-		err := badToken(c, d.offset())
+		err := d.badToken(c, d.offset())
 		return 0, errors.Wrap(err, "double minus")
 	case 0:
 		if i == d.tail {
@@ -88,7 +102,7 @@ func (d *Decoder) positiveFloat32() (float32, error) {
 		}
 		c = d.buf[i]
 		if floatDigits[c] >= 0 {
-			err := badToken(c, d.offset()+1)
+			err := d.badToken(c, d.offset()+1)
 			return 0, errors.Wrap(err, "leading zero")
 		}
 	}
@@ -100,7 +114,7 @@ NonDecimalLoop:
 		ind := floatDigits[c]
 		switch ind {
 		case invalidCharForNumber:
-			return 0, badToken(c, d.offset()+i)
+			return 0, d.badToken(c, d.streamOffset+i)
 		case endOfNumber:
 			d.head = i
 			return float32(value), nil
@@ -133,7 +147,7 @@ NonDecimalLoop:
 			case dotInNumber, expInNumber, plusInNumber, minusInNumber:
 				return d.float32Slow()
 			case invalidCharForNumber:
-				return 0, badToken(c, d.offset()+i)
+				return 0, d.badToken(c, d.streamOffset+i)
 			}
 			decimalPlaces++
 			if value > uint64SafeToMultiple10 {
@@ -151,6 +165,20 @@ func (d *Decoder) Float64() (float64, error) {
 	if err != nil {
 		return 0, err
 	}
+	if d.json5 {
+		d.unread()
+		offset := d.offset()
+		num, f, err := d.json5Num()
+		if err != nil || num == nil {
+			return float64(f), err
+		}
+		var sd Decoder
+		d.subDecoder(&sd, num, offset)
+		return sd.Float64()
+	}
+	if t := types[c]; t != Number && t != Invalid {
+		return 0, d.typeError(Number, c, d.offset()-1)
+	}
 	if c != '-' {
 		d.unread()
 	}
@@ -175,12 +203,12 @@ func (d *Decoder) positiveFloat64() (float64, error) {
 	ind := floatDigits[c]
 	switch ind {
 	case invalidCharForNumber, endOfNumber:
-		return 0, badToken(c, d.offset())
+		return 0, d.badToken(c, d.offset())
 	case dotInNumber, plusInNumber, expInNumber:
-		err := badToken(c, d.offset())
+		err := d.badToken(c, d.offset())
 		return 0, errors.Wrapf(err, "leading %q", c)
 	case minusInNumber: // minus handled by caller
-		err := badToken(c, d.offset())
+		err := d.badToken(c, d.offset())
 		return 0, errors.Wrap(err, "double minus")
 	case 0:
 		if i == d.tail {
@@ -188,7 +216,7 @@ func (d *Decoder) positiveFloat64() (float64, error) {
 		}
 		c = d.buf[i]
 		if floatDigits[c] >= 0 {
-			err := badToken(c, d.offset()+1)
+			err := d.badToken(c, d.offset()+1)
 			return 0, errors.Wrap(err, "leading zero")
 		}
 	}
@@ -200,7 +228,7 @@ NonDecimal:
 		ind := floatDigits[c]
 		switch ind {
 		case invalidCharForNumber:
-			return 0, badToken(c, d.offset()+i)
+			return 0, d.badToken(c, d.streamOffset+i)
 		case endOfNumber:
 			d.head = i
 			return float64(value), nil
@@ -233,7 +261,7 @@ NonDecimal:
 			case dotInNumber, expInNumber, plusInNumber, minusInNumber:
 				return d.float64Slow()
 			case invalidCharForNumber:
-				return 0, badToken(c, d.offset()+i)
+				return 0, d.badToken(c, d.streamOffset+i)
 			}
 			decimalPlaces++
 			// Not checking for uint64SafeToMultiple10 here because
@@ -269,38 +297,38 @@ func (d *Decoder) floatSlow(size int) (float64, error) {
 		return 0, errors.Wrap(err, "number")
 	}
 
-	if err := validateFloat(str, offset); err != nil {
+	if err := d.validateFloat(str, offset); err != nil {
 		return 0, err
 	}
 
 	val, err := strconv.ParseFloat(string(str), size)
 	if err != nil {
-		return 0, err
+		return 0, d.errorAt(err, offset)
 	}
 
 	return val, nil
 }
 
-func validateFloat(str []byte, offset int) error {
+func (d *Decoder) validateFloat(str []byte, offset int) error {
 	// strconv.ParseFloat is not validating `1.` or `1.e1`
 	if len(str) == 0 {
 		// FIXME(tdakkota): use io.ErrUnexpectedEOF?
-		return errors.New("empty")
+		return d.errorAt(errors.New("empty"), offset)
 	}
 
 	switch c := str[0]; floatDigits[c] {
 	case dotInNumber, plusInNumber, expInNumber:
-		err := badToken(c, offset)
+		err := d.badToken(c, offset)
 		return errors.Wrapf(err, "leading %q", c)
 	case minusInNumber: // minus handled by caller
-		err := badToken(c, offset)
+		err := d.badToken(c, offset)
 		return errors.Wrap(err, "double minus")
 	case 0:
 		if len(str) >= 2 {
 			switch str[1] {
 			case 'e', 'E', '.':
 			default:
-				err := badToken(str[1], offset+1)
+				err := d.badToken(str[1], offset+1)
 				return errors.Wrap(err, "leading zero")
 			}
 		}
@@ -310,12 +338,12 @@ func validateFloat(str []byte, offset int) error {
 	if dotPos != -1 {
 		if dotPos == len(str)-1 {
 			// FIXME(tdakkota): use io.ErrUnexpectedEOF?
-			return errors.New("dot as last char")
+			return d.errorAt(errors.New("dot as last char"), offset+dotPos)
 		}
 		switch c := str[dotPos+1]; c {
 		case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
 		default:
-			err := badToken(c, offset+dotPos+1)
+			err := d.badToken(c, offset+dotPos+1)
 			return errors.Wrap(err, "no digit after dot")
 		}
 	}
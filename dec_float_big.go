@@ -9,6 +9,7 @@ import (
 
 // BigFloat read big.Float
 func (d *Decoder) BigFloat() (*big.Float, error) {
+	offset := d.offset()
 	str, err := d.numberAppend(nil)
 	if err != nil {
 		return nil, errors.Wrap(err, "number")
@@ -19,13 +20,14 @@ func (d *Decoder) BigFloat() (*big.Float, error) {
 	}
 	val, _, err := big.ParseFloat(string(str), 10, uint(prec), big.ToZero)
 	if err != nil {
-		return nil, errors.Wrap(err, "float")
+		return nil, d.errorAt(errors.Wrap(err, "float"), offset)
 	}
 	return val, nil
 }
 
 // BigInt read big.Int
 func (d *Decoder) BigInt() (*big.Int, error) {
+	offset := d.offset()
 	str, err := d.numberAppend(nil)
 	if err != nil {
 		return nil, errors.Wrap(err, "number")
@@ -33,7 +35,7 @@ func (d *Decoder) BigInt() (*big.Int, error) {
 	v := big.NewInt(0)
 	var ok bool
 	if v, ok = v.SetString(string(str), 10); !ok {
-		return nil, errors.New("invalid")
+		return nil, d.errorAt(errors.New("invalid"), offset)
 	}
 	return v, nil
 }
@@ -44,7 +46,7 @@ func (d *Decoder) number() ([]byte, error) {
 	for i, c := range buf {
 		switch floatDigits[c] {
 		case invalidCharForNumber:
-			return nil, badToken(c, d.offset()+i)
+			return nil, d.badToken(c, d.offset()+i)
 		case endOfNumber:
 			// End of number.
 			d.head += i
@@ -66,6 +68,9 @@ func (d *Decoder) numberAppend(b []byte) ([]byte, error) {
 		}
 
 		b = append(b, r...)
+		if err := d.checkNumLen(len(b)); err != nil {
+			return b, err
+		}
 		if d.head != d.tail {
 			return b, nil
 		}
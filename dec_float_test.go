@@ -159,29 +159,29 @@ func TestDecoderFloatUnexpectedChar(t *testing.T) {
 		{" -10", 0, ""},
 
 		// Digit after leading zero.
-		{"00", 0, "leading zero: unexpected byte 48 '0' at 1"},
-		{"01", 0, "leading zero: unexpected byte 49 '1' at 1"},
-		{"-00", 0, "leading zero: unexpected byte 48 '0' at 2"},
-		{"-01", 0, "leading zero: unexpected byte 49 '1' at 2"},
+		{"00", 0, "leading zero: unexpected byte 48 '0' at 1 (line 1, column 2)"},
+		{"01", 0, "leading zero: unexpected byte 49 '1' at 1 (line 1, column 2)"},
+		{"-00", 0, "leading zero: unexpected byte 48 '0' at 2 (line 1, column 3)"},
+		{"-01", 0, "leading zero: unexpected byte 49 '1' at 2 (line 1, column 3)"},
 
 		// Double minus.
-		{"--10", 0, "unexpected byte 45 '-' at 1"},
+		{"--10", 0, "unexpected byte 45 '-' at 1 (line 1, column 2)"},
 
 		// Leading dot.
-		{".0", 0, "unexpected byte 46 '.' at 0"},
+		{".0", 0, "unexpected byte 46 '.' at 0 (line 1, column 1)"},
 		// Leading exponent.
-		{"e0", 0, "unexpected byte 101 'e' at 0"},
-		{"E0", 0, "unexpected byte 69 'E' at 0"},
+		{"e0", 0, "unexpected byte 101 'e' at 0 (line 1, column 1)"},
+		{"E0", 0, "unexpected byte 69 'E' at 0 (line 1, column 1)"},
 
 		// Non-digit after minus.
-		{"-.0", 0, "unexpected byte 46 '.' at 1"},
-		{"-e0", 0, "unexpected byte 101 'e' at 1"},
-		{"-E0", 0, "unexpected byte 69 'E' at 1"},
+		{"-.0", 0, "unexpected byte 46 '.' at 1 (line 1, column 2)"},
+		{"-e0", 0, "unexpected byte 101 'e' at 1 (line 1, column 2)"},
+		{"-E0", 0, "unexpected byte 69 'E' at 1 (line 1, column 2)"},
 
 		// Unexpected character.
-		{"-a", 0, "unexpected byte 97 'a' at 1"},
-		{"0a", 0, "unexpected byte 97 'a' at 1"},
-		{"0.a", 0, "unexpected byte 97 'a' at 2"},
+		{"-a", 0, "unexpected byte 97 'a' at 1 (line 1, column 2)"},
+		{"0a", 0, "unexpected byte 97 'a' at 1 (line 1, column 2)"},
+		{"0.a", 0, "unexpected byte 97 'a' at 2 (line 1, column 3)"},
 	}
 
 	for i, tt := range tests {
@@ -200,7 +200,7 @@ func TestDecoderFloatUnexpectedChar(t *testing.T) {
 							err := intFn.fn(d)
 							if e := tt.errContains; e != "" {
 								a.ErrorContains(err, e)
-								v, ok := errors.Into[*badTokenErr](err)
+								v, ok := errors.Into[*SyntaxError](err)
 								if !ok {
 									return
 								}
@@ -0,0 +1,358 @@
+package jx
+
+import "math/bits"
+
+// Structural indexing of byte slice input, similar to stage 1 of simdjson.
+//
+// Input is processed in 64-byte blocks, producing bitmaps of structural
+// characters, where bit i corresponds to byte i of block. Strings are
+// skipped by masks, without byte-by-byte state machine.
+//
+// Character classes are computed by AVX2 on amd64. Other platforms,
+// including arm64, use scalar path of Skip with SWAR string scanning,
+// because portable classification is slower than it.
+//
+// See https://arxiv.org/abs/1902.08318.
+
+const (
+	// indexMaxDepth is maximum depth supported by skipIndexed, deeper
+	// values are skipped by scalar path.
+	indexMaxDepth = 4 * 64
+	// indexMinSize is minimum size of input to use structural index,
+	// smaller inputs are skipped faster by scalar path.
+	indexMinSize = 64
+)
+
+// Character classes of indexing.
+const (
+	classQuote byte = 1 << iota
+	classBackslash
+	classControl
+	classSpace
+	classOp
+)
+
+var classSet [256]byte
+
+func init() {
+	for c := 0; c < ' '; c++ {
+		classSet[c] = classControl
+	}
+	classSet['"'] = classQuote
+	classSet['\\'] = classBackslash
+	for _, c := range []byte(" \t\n\r") {
+		classSet[c] |= classSpace
+	}
+	for _, c := range []byte("{}[]:,") {
+		classSet[c] = classOp
+	}
+}
+
+// blockMasks are bitmaps of character classes in 64-byte block.
+type blockMasks struct {
+	quote     uint64
+	backslash uint64
+	control   uint64
+	space     uint64
+	op        uint64
+}
+
+// blockMasksScalar is portable implementation of indexBlock.
+func blockMasksScalar(b *[64]byte) (m blockMasks) {
+	for i, c := range b {
+		class := classSet[c]
+		if class == 0 {
+			continue
+		}
+		bit := uint64(1) << i
+		switch {
+		case class&classQuote != 0:
+			m.quote |= bit
+		case class&classBackslash != 0:
+			m.backslash |= bit
+		case class&classOp != 0:
+			m.op |= bit
+		}
+		if class&classControl != 0 {
+			m.control |= bit
+		}
+		if class&classSpace != 0 {
+			m.space |= bit
+		}
+	}
+	return m
+}
+
+// prefixXor returns bitmap where bit i is xor of bits 0..i of x.
+func prefixXor(x uint64) uint64 {
+	x ^= x << 1
+	x ^= x << 2
+	x ^= x << 4
+	x ^= x << 8
+	x ^= x << 16
+	x ^= x << 32
+	return x
+}
+
+// indexScanner iterates over structural characters of input.
+type indexScanner struct {
+	buf  []byte
+	pos  int    // offset of next block
+	base int    // offset of current block
+	bits uint64 // structural characters of current block
+
+	// State carried between blocks.
+	inStr   uint64 // all ones if previous block ended inside string
+	escaped uint64 // 1 if first byte of block is escaped
+	scalar  uint64 // 1 if previous block ended with non-quote scalar
+	end     bool   // invalid string is found
+}
+
+// next returns offset of next structural character.
+//
+// Returns -1 if input is ended or string is invalid.
+func (s *indexScanner) next() int {
+	for s.bits == 0 {
+		if s.end || s.pos >= len(s.buf) {
+			return -1
+		}
+		s.block()
+	}
+	i := bits.TrailingZeros64(s.bits)
+	s.bits &= s.bits - 1
+	return s.base + i
+}
+
+// block indexes next block, validating strings.
+func (s *indexScanner) block() {
+	var (
+		b   *[64]byte
+		pad [64]byte
+	)
+	if rest := s.buf[s.pos:]; len(rest) >= len(pad) {
+		b = (*[64]byte)(rest[:len(pad)])
+	} else {
+		for i := range pad {
+			pad[i] = ' '
+		}
+		copy(pad[:], rest)
+		b = &pad
+	}
+	m := indexBlock(b)
+
+	escaped := s.escape(m.backslash)
+	quote := m.quote &^ escaped
+	inStr := prefixXor(quote) ^ s.inStr
+	s.inStr = uint64(int64(inStr) >> 63)
+	invalid := m.control & inStr
+	for esc := m.backslash &^ escaped & inStr; esc != 0; esc &= esc - 1 {
+		if i := bits.TrailingZeros64(esc); !validEscape(s.buf, s.pos+i) {
+			invalid |= 1 << i
+			break
+		}
+	}
+
+	// Scalar starts are characters that follow whitespace, operator or
+	// quote, so every value has structural character.
+	scalar := ^(m.op | m.space)
+	nonQuote := scalar &^ quote
+	follows := nonQuote<<1 | s.scalar
+	s.scalar = nonQuote >> 63
+	// String contents and closing quotes are not structural.
+	strTail := inStr ^ quote
+
+	s.bits = (m.op | scalar&^follows) &^ strTail
+	if invalid != 0 {
+		// Value can end before invalid string, so stop after structural
+		// characters preceding it.
+		s.bits &= invalid&-invalid - 1
+		s.end = true
+	}
+	s.base = s.pos
+	s.pos += len(pad)
+}
+
+// escape returns bitmap of escaped characters, given bitmap of backslashes.
+func (s *indexScanner) escape(backslash uint64) uint64 {
+	const even = 0x5555555555555555
+	backslash &^= s.escaped
+	follows := backslash<<1 | s.escaped
+	// Sequences of backslashes starting on odd bits.
+	oddStarts := backslash &^ even &^ follows
+	evenSeq, carry := bits.Add64(oddStarts, backslash, 0)
+	s.escaped = carry
+	invert := evenSeq << 1
+	return (even ^ invert) & follows
+}
+
+// validEscape reports whether escape sequence at offset i is valid.
+func validEscape(buf []byte, i int) bool {
+	if i+1 >= len(buf) {
+		return false
+	}
+	switch escapedStrSet[buf[i+1]] {
+	case 0:
+		return false
+	case 'u':
+		if i+5 >= len(buf) {
+			return false
+		}
+		for _, h := range buf[i+2 : i+6] {
+			if hexSet[h] == 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// indexable reports whether skipIndexed can be used.
+func (d *Decoder) indexable() bool {
+	if !indexSupported || d.tail-d.head < indexMinSize || d.reader != nil || d.noIndex {
+		return false
+	}
+	l := d.limits
+	return !d.json5 && !d.noDupKeys && d.utf8 != UTF8Strict &&
+		l.StrLen == 0 && l.NumLen == 0 && l.ObjLen == 0 && l.ArrLen == 0
+}
+
+// skipIndexed skips object or array, which bracket is consumed, using
+// structural index.
+//
+// Returns false if input is invalid, leaving decoder state unchanged and
+// disabling indexing, so scalar path reports error.
+func (d *Decoder) skipIndexed() bool {
+	start := d.head
+	if !d.skipIndexedValue(start - 1) {
+		d.head = start
+		d.noIndex = true
+		return false
+	}
+	return true
+}
+
+func (d *Decoder) skipIndexedValue(start int) bool {
+	var (
+		s     = indexScanner{buf: d.buf[:d.tail], pos: start}
+		buf   = s.buf
+		objs  [indexMaxDepth / 64]uint64 // bit is set if container is object
+		depth int
+		obj   bool
+		p     = s.next()
+	)
+	max := d.limits.Depth
+	if max <= 0 {
+		max = maxDepth
+	}
+	max -= d.depth
+	if p != start {
+		return false
+	}
+
+value:
+	switch buf[p] {
+	case '{', '[':
+		obj = buf[p] == '{'
+		if depth >= max || depth >= indexMaxDepth {
+			return false
+		}
+		if obj {
+			objs[depth/64] |= 1 << (depth % 64)
+		} else {
+			objs[depth/64] &^= 1 << (depth % 64)
+		}
+		depth++
+
+		if p = s.next(); p < 0 {
+			return false
+		}
+		switch c := buf[p]; {
+		case obj && c == '}', !obj && c == ']':
+			goto end
+		case obj && c == '"':
+			goto key
+		case obj:
+			return false
+		default:
+			goto value
+		}
+	case '"':
+		goto next
+	default:
+		if !d.skipIndexedScalar(p) {
+			return false
+		}
+		goto next
+	}
+
+key:
+	if p = s.next(); p < 0 || buf[p] != ':' {
+		return false
+	}
+	if p = s.next(); p < 0 {
+		return false
+	}
+	goto value
+
+next:
+	if p = s.next(); p < 0 {
+		return false
+	}
+	switch c := buf[p]; {
+	case c == ',':
+		if p = s.next(); p < 0 {
+			return false
+		}
+		if !obj {
+			goto value
+		}
+		if buf[p] != '"' {
+			return false
+		}
+		goto key
+	case obj && c == '}', !obj && c == ']':
+		goto end
+	default:
+		return false
+	}
+
+end:
+	depth--
+	if depth == 0 {
+		d.head = p + 1
+		return true
+	}
+	obj = objs[(depth-1)/64]&(1<<((depth-1)%64)) != 0
+	goto next
+}
+
+// skipIndexedScalar validates number or literal at offset p.
+func (d *Decoder) skipIndexedScalar(p int) bool {
+	buf := d.buf[p:d.tail]
+	switch buf[0] {
+	case 't', 'n':
+		if len(buf) < 4 || (string(buf[:4]) != "true" && string(buf[:4]) != "null") {
+			return false
+		}
+		d.head = p + 4
+	case 'f':
+		if len(buf) < 5 || string(buf[:5]) != "false" {
+			return false
+		}
+		d.head = p + 5
+	case '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		d.head = p
+		if err := d.skipNumberLiteral(); err != nil {
+			return false
+		}
+	default:
+		return false
+	}
+	// Scalar must be followed by whitespace or operator.
+	if d.head < d.tail {
+		if c := d.buf[d.head]; classSet[c]&(classSpace|classOp) == 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,26 @@
+//go:build amd64 && !purego
+
+package jx
+
+import (
+	"github.com/segmentio/asm/cpu"
+	"github.com/segmentio/asm/cpu/x86"
+)
+
+// indexSupported reports whether structural index is fast enough to be
+// used by Skip.
+var indexSupported = cpu.X86.Has(x86.AVX2)
+
+// indexBlock returns character class masks of block.
+func indexBlock(b *[64]byte) (m blockMasks) {
+	if !indexSupported {
+		return blockMasksScalar(b)
+	}
+	indexBlockAVX2(b, &m)
+	return m
+}
+
+// indexBlockAVX2 sets character class masks of block.
+//
+//go:noescape
+func indexBlockAVX2(b *[64]byte, m *blockMasks)
@@ -0,0 +1,86 @@
+//go:build amd64 && !purego
+
+#include "textflag.h"
+
+// Tables of VPSHUFB lookup by low nibble, repeated for both lanes.
+// Byte is class member if it is equal to table entry of its low nibble.
+
+// Whitespace: ' ', '\t', '\n' and '\r'.
+DATA indexSpaceTable<>+0(SB)/8, $0x0000000000000020
+DATA indexSpaceTable<>+8(SB)/8, $0x00000d00000a0900
+DATA indexSpaceTable<>+16(SB)/8, $0x0000000000000020
+DATA indexSpaceTable<>+24(SB)/8, $0x00000d00000a0900
+GLOBL indexSpaceTable<>(SB), RODATA|NOPTR, $32
+
+// Operators, lookup by byte with 0x20 bit set: ':', '{' or '[', ',' and
+// '}' or ']'. Control characters 0x1a and 0x0c are excluded after lookup.
+DATA indexOpTable<>+0(SB)/8, $0x0000000000000000
+DATA indexOpTable<>+8(SB)/8, $0x00007d2c7b3a0000
+DATA indexOpTable<>+16(SB)/8, $0x0000000000000000
+DATA indexOpTable<>+24(SB)/8, $0x00007d2c7b3a0000
+GLOBL indexOpTable<>(SB), RODATA|NOPTR, $32
+
+// BROADCAST sets all bytes of y to c.
+#define BROADCAST(c, x, y) \
+	MOVQ         $c, AX; \
+	VMOVQ        AX, x; \
+	VPBROADCASTB x, y
+
+// MASK stores 64-bit mask of high bits of bytes of lo and hi to off(DI).
+#define MASK(lo, hi, off) \
+	VPMOVMSKB lo, AX; \
+	VPMOVMSKB hi, BX; \
+	SHLQ      $32, BX; \
+	ORQ       BX, AX; \
+	MOVQ      AX, off(DI)
+
+// func indexBlockAVX2(b *[64]byte, m *blockMasks)
+TEXT ·indexBlockAVX2(SB), NOSPLIT, $0-16
+	MOVQ b+0(FP), SI
+	MOVQ m+8(FP), DI
+	VMOVDQU 0(SI), Y0
+	VMOVDQU 32(SI), Y1
+
+	// Quotes.
+	BROADCAST(0x22, X2, Y2)
+	VPCMPEQB Y2, Y0, Y3
+	VPCMPEQB Y2, Y1, Y4
+	MASK(Y3, Y4, 0)
+
+	// Backslashes.
+	BROADCAST(0x5c, X2, Y2)
+	VPCMPEQB Y2, Y0, Y3
+	VPCMPEQB Y2, Y1, Y4
+	MASK(Y3, Y4, 8)
+
+	// Control characters, byte is less than 0x20 if min(byte, 0x1f) is byte.
+	BROADCAST(0x1f, X2, Y2)
+	VPMINUB  Y2, Y0, Y3
+	VPMINUB  Y2, Y1, Y4
+	VPCMPEQB Y0, Y3, Y5
+	VPCMPEQB Y1, Y4, Y6
+	MASK(Y5, Y6, 16)
+
+	// Whitespace.
+	VMOVDQU  indexSpaceTable<>(SB), Y2
+	VPSHUFB  Y0, Y2, Y3
+	VPSHUFB  Y1, Y2, Y4
+	VPCMPEQB Y0, Y3, Y3
+	VPCMPEQB Y1, Y4, Y4
+	MASK(Y3, Y4, 24)
+
+	// Operators.
+	BROADCAST(0x20, X2, Y2)
+	VPOR     Y2, Y0, Y0
+	VPOR     Y2, Y1, Y1
+	VMOVDQU  indexOpTable<>(SB), Y2
+	VPSHUFB  Y0, Y2, Y3
+	VPSHUFB  Y1, Y2, Y4
+	VPCMPEQB Y0, Y3, Y3
+	VPCMPEQB Y1, Y4, Y4
+	VPANDN   Y3, Y5, Y3
+	VPANDN   Y4, Y6, Y4
+	MASK(Y3, Y4, 32)
+
+	VZEROUPPER
+	RET
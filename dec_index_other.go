@@ -0,0 +1,12 @@
+//go:build !amd64 || purego
+
+package jx
+
+// indexSupported reports whether structural index is fast enough to be
+// used by Skip.
+const indexSupported = false
+
+// indexBlock returns character class masks of block.
+func indexBlock(b *[64]byte) blockMasks {
+	return blockMasksScalar(b)
+}
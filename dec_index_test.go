@@ -0,0 +1,194 @@
+package jx
+
+import (
+	"math/rand"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndexBlock(t *testing.T) {
+	const special = " \t\n\r\"\\{}[]:,\x00\x0c\x1a\x1f\x7f\x80\xffY_y"
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		var b [64]byte
+		for j := range b {
+			if rnd.Intn(2) == 0 {
+				b[j] = special[rnd.Intn(len(special))]
+			} else {
+				b[j] = byte(rnd.Intn(256))
+			}
+		}
+		require.Equal(t, blockMasksScalar(&b), indexBlock(&b), "%q", b[:])
+	}
+}
+
+func TestStrSpan(t *testing.T) {
+	for _, s := range []string{
+		``,
+		`a`,
+		`"`,
+		`abcdefg`,
+		`abcdefgh`,
+		`abcdefgh"`,
+		`abcdefghijklmnop\`,
+		"abcdefghij\x1f",
+		"abcdefghij\x7f\x80\xff\"",
+		`привет, мир"`,
+	} {
+		require.Equal(t, strSpanScalar([]byte(s)), strSpan([]byte(s)), "%q", s)
+	}
+}
+
+// checkIndexed checks that skipIndexed agrees with scalar Skip on input.
+func checkIndexed(t testing.TB, input []byte) {
+	t.Helper()
+
+	start := 0
+	for start < len(input) && spaceSet[input[start]] != 0 {
+		start++
+	}
+	if start == len(input) || (input[start] != '{' && input[start] != '[') {
+		return
+	}
+
+	scalar := DecodeBytes(input)
+	scalar.noIndex = true
+	scalarErr := scalar.Skip()
+
+	d := DecodeBytes(input)
+	d.head = start + 1
+	if d.skipIndexed() {
+		require.NoError(t, scalarErr, "%q", input)
+		require.Equal(t, scalar.head, d.head, "%q", input)
+		require.Zero(t, d.depth)
+		return
+	}
+	require.True(t, d.noIndex)
+	require.Equal(t, start+1, d.head)
+	if scalarErr == nil {
+		// Only too deep values are skipped by scalar path.
+		require.Greater(t, strings.Count(string(input), "["), indexMaxDepth, "%q", input)
+	}
+}
+
+func TestDecoder_skipIndexed(t *testing.T) {
+	t.Run("Cases", func(t *testing.T) {
+		pad := strings.Repeat(" ", 60)
+		long := strings.Repeat("x", 61)
+		for _, s := range []string{
+			`{}`,
+			`[]`,
+			`[1,2,3]`,
+			`{"a":1,"b":[true,false,null],"c":{"d":"e"}}`,
+			`[` + pad + `"` + long + `\"` + long + `"]`,
+			`[` + pad + `"` + long + `\\` + `"]`,
+			`["` + strings.Repeat(`\\`, 40) + `"]`,
+			`["` + strings.Repeat(`\`, 63) + `"]`,
+			`["` + strings.Repeat(`a`, 60) + `\u0041"]`,
+			`["` + strings.Repeat(`a`, 60) + `\u004"]`,
+			`["` + strings.Repeat(`a`, 61) + `\x"]`,
+			`["` + strings.Repeat(`a`, 61) + "\t" + `"]`,
+			`[` + pad + `123456789` + pad + `]`,
+			`[` + pad + `1234,56789]`,
+			`[` + pad + `true,false]`,
+			`[` + pad + `truefalse]`,
+			`[` + pad + `"a""b"]`,
+			`[` + pad + `"a"1]`,
+			`[1"a"]`,
+			`[1 2]`,
+			`[1,]`,
+			`[,1]`,
+			`{"a" 1}`,
+			`{"a":1,}`,
+			`{1:1}`,
+			`{"a":1]`,
+			`[1}`,
+			`[1`,
+			`{"a":`,
+			`["a`,
+			`[-]`,
+			`[01]`,
+			`[1.]`,
+			`[Y]`,
+			"[\x1a]",
+			"[\x00]",
+			`[` + strings.Repeat(`[`, 300) + strings.Repeat(`]`, 300) + `]`,
+			`[` + strings.Repeat(`{"a":[`, 100) + strings.Repeat(`]}`, 100) + `]`,
+		} {
+			checkIndexed(t, []byte(s))
+		}
+	})
+	t.Run("Suite", func(t *testing.T) {
+		dir := path.Join("testdata", "test_parsing")
+		files, err := testdata.ReadDir(dir)
+		require.NoError(t, err)
+		for _, f := range files {
+			data, err := testdata.ReadFile(path.Join(dir, f.Name()))
+			require.NoError(t, err)
+			checkIndexed(t, data)
+		}
+	})
+	t.Run("Mutations", func(t *testing.T) {
+		const special = " \t\n\r\"\\{}[]:,0-eu\x00"
+		rnd := rand.New(rand.NewSource(1))
+		runTestdata(t.Fatal, func(name string, data []byte) {
+			checkIndexed(t, data)
+			input := make([]byte, len(data))
+			for i := 0; i < 100; i++ {
+				copy(input, data)
+				for j := rnd.Intn(3); j >= 0; j-- {
+					input[rnd.Intn(len(input))] = special[rnd.Intn(len(special))]
+				}
+				checkIndexed(t, input)
+			}
+		})
+	})
+}
+
+func TestDecoder_Validate_indexed(t *testing.T) {
+	pad := strings.Repeat(" ", 64)
+	t.Run("Depth", func(t *testing.T) {
+		d := DecodeStr(`[[[[1]]]]` + pad)
+		d.SetLimits(Limits{Depth: 3})
+		require.ErrorIs(t, d.Validate(), ErrMaxDepth)
+
+		d.ResetBytes([]byte(`[[[1]]]` + pad))
+		require.NoError(t, d.Validate())
+	})
+	t.Run("Bytes", func(t *testing.T) {
+		d := DecodeStr(`{"a":"` + pad + `"}`)
+		d.SetLimits(Limits{Bytes: 32})
+		require.ErrorIs(t, d.Validate(), ErrMaxBytes)
+	})
+	t.Run("Error", func(t *testing.T) {
+		input := `{"a":[1,2,3],` + pad + `"b":tru}`
+		d := DecodeStr(input)
+		err := d.Validate()
+		require.Error(t, err)
+
+		scalar := DecodeStr(input)
+		scalar.noIndex = true
+		require.Equal(t, scalar.Validate().Error(), err.Error())
+	})
+	t.Run("Reset", func(t *testing.T) {
+		if !indexSupported {
+			t.Skip("Structural index is not supported")
+		}
+		d := DecodeStr(`[1,` + pad + `x]`)
+		require.Error(t, d.Validate())
+		require.True(t, d.noIndex)
+		d.ResetBytes([]byte(`[1,` + pad + `2]`))
+		require.False(t, d.noIndex)
+		require.NoError(t, d.Validate())
+	})
+}
+
+func FuzzIndexed(f *testing.F) {
+	addCorpus(f)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		checkIndexed(t, data)
+	})
+}
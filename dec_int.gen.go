@@ -25,7 +25,23 @@ func (d *Decoder) UInt8() (uint8, error) {
 	if err != nil {
 		return 0, err
 	}
-	return d.readUInt8(c)
+	if d.json5 {
+		d.unread()
+		var sd Decoder
+		if err := d.json5Sub(&sd); err != nil {
+			return 0, err
+		}
+		return sd.UInt8()
+	}
+	if t := types[c]; t != Number && t != Invalid {
+		return 0, d.typeError(Number, c, d.offset()-1)
+	}
+	start := d.offset() - 1
+	val, err := d.readUInt8(c)
+	if err != nil {
+		return 0, d.intError(err, start)
+	}
+	return val, nil
 }
 
 func (d *Decoder) readUInt8(c byte) (uint8, error) {
@@ -37,19 +53,19 @@ func (d *Decoder) readUInt8(c byte) (uint8, error) {
 		if err == nil {
 			switch floatDigits[c] {
 			case 0, 1, 2, 3, 4, 5, 6, 7, 8, 9:
-				err := badToken(c, d.offset())
+				err := d.badToken(c, d.offset())
 				return 0, errors.Wrap(err, "digit after leading zero")
 			case dotInNumber, expInNumber, plusInNumber, minusInNumber:
-				err := badToken(c, d.offset())
+				err := d.badToken(c, d.offset())
 				return 0, errors.Wrap(err, "unexpected floating point character")
 			case invalidCharForNumber:
-				return 0, badToken(c, d.offset())
+				return 0, d.badToken(c, d.offset())
 			}
 		}
 		return 0, nil // single zero
 	default:
 		if ind < 0 {
-			return 0, badToken(c, d.offset()-1)
+			return 0, d.badToken(c, d.offset()-1)
 		}
 	}
 	value := uint8(ind)
@@ -59,12 +75,12 @@ func (d *Decoder) readUInt8(c byte) (uint8, error) {
 		ind2 := floatDigits[d.buf[i]]
 		switch ind2 {
 		case invalidCharForNumber:
-			return 0, badToken(d.buf[i], d.offset()+0)
+			return 0, d.badToken(d.buf[i], d.offset()+0)
 		case dotInNumber,
 			expInNumber,
 			plusInNumber,
 			minusInNumber:
-			err := badToken(d.buf[i], d.offset()+0)
+			err := d.badToken(d.buf[i], d.offset()+0)
 			return 0, errors.Wrap(err, "unexpected floating point character")
 		case endOfNumber:
 			d.head = i
@@ -76,12 +92,12 @@ func (d *Decoder) readUInt8(c byte) (uint8, error) {
 		ind3 := floatDigits[d.buf[i]]
 		switch ind3 {
 		case invalidCharForNumber:
-			return 0, badToken(d.buf[i], d.offset()+1)
+			return 0, d.badToken(d.buf[i], d.offset()+1)
 		case dotInNumber,
 			expInNumber,
 			plusInNumber,
 			minusInNumber:
-			err := badToken(d.buf[i], d.offset()+1)
+			err := d.badToken(d.buf[i], d.offset()+1)
 			return 0, errors.Wrap(err, "unexpected floating point character")
 		case endOfNumber:
 			d.head = i
@@ -94,12 +110,12 @@ func (d *Decoder) readUInt8(c byte) (uint8, error) {
 		ind4 := floatDigits[d.buf[i]]
 		switch ind4 {
 		case invalidCharForNumber:
-			return 0, badToken(d.buf[i], d.offset()+2)
+			return 0, d.badToken(d.buf[i], d.offset()+2)
 		case dotInNumber,
 			expInNumber,
 			plusInNumber,
 			minusInNumber:
-			err := badToken(d.buf[i], d.offset()+2)
+			err := d.badToken(d.buf[i], d.offset()+2)
 			return 0, errors.Wrap(err, "unexpected floating point character")
 		case endOfNumber:
 			d.head = i
@@ -119,12 +135,12 @@ func (d *Decoder) readUInt8(c byte) (uint8, error) {
 			ind = floatDigits[c]
 			switch ind {
 			case invalidCharForNumber:
-				return 0, badToken(c, d.offset()+i)
+				return 0, d.badToken(c, d.offset()+i)
 			case dotInNumber,
 				expInNumber,
 				plusInNumber,
 				minusInNumber:
-				err := badToken(c, d.offset()+i)
+				err := d.badToken(c, d.offset()+i)
 				return 0, errors.Wrap(err, "unexpected floating point character")
 			case endOfNumber:
 				d.head += i
@@ -157,6 +173,18 @@ func (d *Decoder) Int8() (int8, error) {
 	if err != nil {
 		return 0, err
 	}
+	if d.json5 {
+		d.unread()
+		var sd Decoder
+		if err := d.json5Sub(&sd); err != nil {
+			return 0, err
+		}
+		return sd.Int8()
+	}
+	if t := types[c]; t != Number && t != Invalid {
+		return 0, d.typeError(Number, c, d.offset()-1)
+	}
+	start := d.offset() - 1
 	if c == '-' {
 		c, err := d.byte()
 		if err != nil {
@@ -164,19 +192,19 @@ func (d *Decoder) Int8() (int8, error) {
 		}
 		val, err := d.readUInt8(c)
 		if err != nil {
-			return 0, err
+			return 0, d.intError(err, start)
 		}
 		if val > math.MaxInt8+1 {
-			return 0, errOverflow
+			return 0, d.errorAt(errOverflow, start)
 		}
 		return -int8(val), nil
 	}
 	val, err := d.readUInt8(c)
 	if err != nil {
-		return 0, err
+		return 0, d.intError(err, start)
 	}
 	if val > math.MaxInt8 {
-		return 0, errOverflow
+		return 0, d.errorAt(errOverflow, start)
 	}
 	return int8(val), nil
 }
@@ -187,7 +215,23 @@ func (d *Decoder) UInt16() (uint16, error) {
 	if err != nil {
 		return 0, err
 	}
-	return d.readUInt16(c)
+	if d.json5 {
+		d.unread()
+		var sd Decoder
+		if err := d.json5Sub(&sd); err != nil {
+			return 0, err
+		}
+		return sd.UInt16()
+	}
+	if t := types[c]; t != Number && t != Invalid {
+		return 0, d.typeError(Number, c, d.offset()-1)
+	}
+	start := d.offset() - 1
+	val, err := d.readUInt16(c)
+	if err != nil {
+		return 0, d.intError(err, start)
+	}
+	return val, nil
 }
 
 func (d *Decoder) readUInt16(c byte) (uint16, error) {
@@ -199,19 +243,19 @@ func (d *Decoder) readUInt16(c byte) (uint16, error) {
 		if err == nil {
 			switch floatDigits[c] {
 			case 0, 1, 2, 3, 4, 5, 6, 7, 8, 9:
-				err := badToken(c, d.offset())
+				err := d.badToken(c, d.offset())
 				return 0, errors.Wrap(err, "digit after leading zero")
 			case dotInNumber, expInNumber, plusInNumber, minusInNumber:
-				err := badToken(c, d.offset())
+				err := d.badToken(c, d.offset())
 				return 0, errors.Wrap(err, "unexpected floating point character")
 			case invalidCharForNumber:
-				return 0, badToken(c, d.offset())
+				return 0, d.badToken(c, d.offset())
 			}
 		}
 		return 0, nil // single zero
 	default:
 		if ind < 0 {
-			return 0, badToken(c, d.offset()-1)
+			return 0, d.badToken(c, d.offset()-1)
 		}
 	}
 	value := uint16(ind)
@@ -221,12 +265,12 @@ func (d *Decoder) readUInt16(c byte) (uint16, error) {
 		ind2 := floatDigits[d.buf[i]]
 		switch ind2 {
 		case invalidCharForNumber:
-			return 0, badToken(d.buf[i], d.offset()+0)
+			return 0, d.badToken(d.buf[i], d.offset()+0)
 		case dotInNumber,
 			expInNumber,
 			plusInNumber,
 			minusInNumber:
-			err := badToken(d.buf[i], d.offset()+0)
+			err := d.badToken(d.buf[i], d.offset()+0)
 			return 0, errors.Wrap(err, "unexpected floating point character")
 		case endOfNumber:
 			d.head = i
@@ -238,12 +282,12 @@ func (d *Decoder) readUInt16(c byte) (uint16, error) {
 		ind3 := floatDigits[d.buf[i]]
 		switch ind3 {
 		case invalidCharForNumber:
-			return 0, badToken(d.buf[i], d.offset()+1)
+			return 0, d.badToken(d.buf[i], d.offset()+1)
 		case dotInNumber,
 			expInNumber,
 			plusInNumber,
 			minusInNumber:
-			err := badToken(d.buf[i], d.offset()+1)
+			err := d.badToken(d.buf[i], d.offset()+1)
 			return 0, errors.Wrap(err, "unexpected floating point character")
 		case endOfNumber:
 			d.head = i
@@ -256,12 +300,12 @@ func (d *Decoder) readUInt16(c byte) (uint16, error) {
 		ind4 := floatDigits[d.buf[i]]
 		switch ind4 {
 		case invalidCharForNumber:
-			return 0, badToken(d.buf[i], d.offset()+2)
+			return 0, d.badToken(d.buf[i], d.offset()+2)
 		case dotInNumber,
 			expInNumber,
 			plusInNumber,
 			minusInNumber:
-			err := badToken(d.buf[i], d.offset()+2)
+			err := d.badToken(d.buf[i], d.offset()+2)
 			return 0, errors.Wrap(err, "unexpected floating point character")
 		case endOfNumber:
 			d.head = i
@@ -275,12 +319,12 @@ func (d *Decoder) readUInt16(c byte) (uint16, error) {
 		ind5 := floatDigits[d.buf[i]]
 		switch ind5 {
 		case invalidCharForNumber:
-			return 0, badToken(d.buf[i], d.offset()+3)
+			return 0, d.badToken(d.buf[i], d.offset()+3)
 		case dotInNumber,
 			expInNumber,
 			plusInNumber,
 			minusInNumber:
-			err := badToken(d.buf[i], d.offset()+3)
+			err := d.badToken(d.buf[i], d.offset()+3)
 			return 0, errors.Wrap(err, "unexpected floating point character")
 		case endOfNumber:
 			d.head = i
@@ -295,12 +339,12 @@ func (d *Decoder) readUInt16(c byte) (uint16, error) {
 		ind6 := floatDigits[d.buf[i]]
 		switch ind6 {
 		case invalidCharForNumber:
-			return 0, badToken(d.buf[i], d.offset()+4)
+			return 0, d.badToken(d.buf[i], d.offset()+4)
 		case dotInNumber,
 			expInNumber,
 			plusInNumber,
 			minusInNumber:
-			err := badToken(d.buf[i], d.offset()+4)
+			err := d.badToken(d.buf[i], d.offset()+4)
 			return 0, errors.Wrap(err, "unexpected floating point character")
 		case endOfNumber:
 			d.head = i
@@ -324,12 +368,12 @@ func (d *Decoder) readUInt16(c byte) (uint16, error) {
 			ind = floatDigits[c]
 			switch ind {
 			case invalidCharForNumber:
-				return 0, badToken(c, d.offset()+i)
+				return 0, d.badToken(c, d.offset()+i)
 			case dotInNumber,
 				expInNumber,
 				plusInNumber,
 				minusInNumber:
-				err := badToken(c, d.offset()+i)
+				err := d.badToken(c, d.offset()+i)
 				return 0, errors.Wrap(err, "unexpected floating point character")
 			case endOfNumber:
 				d.head += i
@@ -362,6 +406,18 @@ func (d *Decoder) Int16() (int16, error) {
 	if err != nil {
 		return 0, err
 	}
+	if d.json5 {
+		d.unread()
+		var sd Decoder
+		if err := d.json5Sub(&sd); err != nil {
+			return 0, err
+		}
+		return sd.Int16()
+	}
+	if t := types[c]; t != Number && t != Invalid {
+		return 0, d.typeError(Number, c, d.offset()-1)
+	}
+	start := d.offset() - 1
 	if c == '-' {
 		c, err := d.byte()
 		if err != nil {
@@ -369,19 +425,19 @@ func (d *Decoder) Int16() (int16, error) {
 		}
 		val, err := d.readUInt16(c)
 		if err != nil {
-			return 0, err
+			return 0, d.intError(err, start)
 		}
 		if val > math.MaxInt16+1 {
-			return 0, errOverflow
+			return 0, d.errorAt(errOverflow, start)
 		}
 		return -int16(val), nil
 	}
 	val, err := d.readUInt16(c)
 	if err != nil {
-		return 0, err
+		return 0, d.intError(err, start)
 	}
 	if val > math.MaxInt16 {
-		return 0, errOverflow
+		return 0, d.errorAt(errOverflow, start)
 	}
 	return int16(val), nil
 }
@@ -392,7 +448,23 @@ func (d *Decoder) UInt32() (uint32, error) {
 	if err != nil {
 		return 0, err
 	}
-	return d.readUInt32(c)
+	if d.json5 {
+		d.unread()
+		var sd Decoder
+		if err := d.json5Sub(&sd); err != nil {
+			return 0, err
+		}
+		return sd.UInt32()
+	}
+	if t := types[c]; t != Number && t != Invalid {
+		return 0, d.typeError(Number, c, d.offset()-1)
+	}
+	start := d.offset() - 1
+	val, err := d.readUInt32(c)
+	if err != nil {
+		return 0, d.intError(err, start)
+	}
+	return val, nil
 }
 
 func (d *Decoder) readUInt32(c byte) (uint32, error) {
@@ -404,19 +476,19 @@ func (d *Decoder) readUInt32(c byte) (uint32, error) {
 		if err == nil {
 			switch floatDigits[c] {
 			case 0, 1, 2, 3, 4, 5, 6, 7, 8, 9:
-				err := badToken(c, d.offset())
+				err := d.badToken(c, d.offset())
 				return 0, errors.Wrap(err, "digit after leading zero")
 			case dotInNumber, expInNumber, plusInNumber, minusInNumber:
-				err := badToken(c, d.offset())
+				err := d.badToken(c, d.offset())
 				return 0, errors.Wrap(err, "unexpected floating point character")
 			case invalidCharForNumber:
-				return 0, badToken(c, d.offset())
+				return 0, d.badToken(c, d.offset())
 			}
 		}
 		return 0, nil // single zero
 	default:
 		if ind < 0 {
-			return 0, badToken(c, d.offset()-1)
+			return 0, d.badToken(c, d.offset()-1)
 		}
 	}
 	value := uint32(ind)
@@ -426,12 +498,12 @@ func (d *Decoder) readUInt32(c byte) (uint32, error) {
 		ind2 := floatDigits[d.buf[i]]
 		switch ind2 {
 		case invalidCharForNumber:
-			return 0, badToken(d.buf[i], d.offset()+0)
+			return 0, d.badToken(d.buf[i], d.offset()+0)
 		case dotInNumber,
 			expInNumber,
 			plusInNumber,
 			minusInNumber:
-			err := badToken(d.buf[i], d.offset()+0)
+			err := d.badToken(d.buf[i], d.offset()+0)
 			return 0, errors.Wrap(err, "unexpected floating point character")
 		case endOfNumber:
 			d.head = i
@@ -443,12 +515,12 @@ func (d *Decoder) readUInt32(c byte) (uint32, error) {
 		ind3 := floatDigits[d.buf[i]]
 		switch ind3 {
 		case invalidCharForNumber:
-			return 0, badToken(d.buf[i], d.offset()+1)
+			return 0, d.badToken(d.buf[i], d.offset()+1)
 		case dotInNumber,
 			expInNumber,
 			plusInNumber,
 			minusInNumber:
-			err := badToken(d.buf[i], d.offset()+1)
+			err := d.badToken(d.buf[i], d.offset()+1)
 			return 0, errors.Wrap(err, "unexpected floating point character")
 		case endOfNumber:
 			d.head = i
@@ -461,12 +533,12 @@ func (d *Decoder) readUInt32(c byte) (uint32, error) {
 		ind4 := floatDigits[d.buf[i]]
 		switch ind4 {
 		case invalidCharForNumber:
-			return 0, badToken(d.buf[i], d.offset()+2)
+			return 0, d.badToken(d.buf[i], d.offset()+2)
 		case dotInNumber,
 			expInNumber,
 			plusInNumber,
 			minusInNumber:
-			err := badToken(d.buf[i], d.offset()+2)
+			err := d.badToken(d.buf[i], d.offset()+2)
 			return 0, errors.Wrap(err, "unexpected floating point character")
 		case endOfNumber:
 			d.head = i
@@ -480,12 +552,12 @@ func (d *Decoder) readUInt32(c byte) (uint32, error) {
 		ind5 := floatDigits[d.buf[i]]
 		switch ind5 {
 		case invalidCharForNumber:
-			return 0, badToken(d.buf[i], d.offset()+3)
+			return 0, d.badToken(d.buf[i], d.offset()+3)
 		case dotInNumber,
 			expInNumber,
 			plusInNumber,
 			minusInNumber:
-			err := badToken(d.buf[i], d.offset()+3)
+			err := d.badToken(d.buf[i], d.offset()+3)
 			return 0, errors.Wrap(err, "unexpected floating point character")
 		case endOfNumber:
 			d.head = i
@@ -500,12 +572,12 @@ func (d *Decoder) readUInt32(c byte) (uint32, error) {
 		ind6 := floatDigits[d.buf[i]]
 		switch ind6 {
 		case invalidCharForNumber:
-			return 0, badToken(d.buf[i], d.offset()+4)
+			return 0, d.badToken(d.buf[i], d.offset()+4)
 		case dotInNumber,
 			expInNumber,
 			plusInNumber,
 			minusInNumber:
-			err := badToken(d.buf[i], d.offset()+4)
+			err := d.badToken(d.buf[i], d.offset()+4)
 			return 0, errors.Wrap(err, "unexpected floating point character")
 		case endOfNumber:
 			d.head = i
@@ -521,12 +593,12 @@ func (d *Decoder) readUInt32(c byte) (uint32, error) {
 		ind7 := floatDigits[d.buf[i]]
 		switch ind7 {
 		case invalidCharForNumber:
-			return 0, badToken(d.buf[i], d.offset()+5)
+			return 0, d.badToken(d.buf[i], d.offset()+5)
 		case dotInNumber,
 			expInNumber,
 			plusInNumber,
 			minusInNumber:
-			err := badToken(d.buf[i], d.offset()+5)
+			err := d.badToken(d.buf[i], d.offset()+5)
 			return 0, errors.Wrap(err, "unexpected floating point character")
 		case endOfNumber:
 			d.head = i
@@ -543,12 +615,12 @@ func (d *Decoder) readUInt32(c byte) (uint32, error) {
 		ind8 := floatDigits[d.buf[i]]
 		switch ind8 {
 		case invalidCharForNumber:
-			return 0, badToken(d.buf[i], d.offset()+6)
+			return 0, d.badToken(d.buf[i], d.offset()+6)
 		case dotInNumber,
 			expInNumber,
 			plusInNumber,
 			minusInNumber:
-			err := badToken(d.buf[i], d.offset()+6)
+			err := d.badToken(d.buf[i], d.offset()+6)
 			return 0, errors.Wrap(err, "unexpected floating point character")
 		case endOfNumber:
 			d.head = i
@@ -566,12 +638,12 @@ func (d *Decoder) readUInt32(c byte) (uint32, error) {
 		ind9 := floatDigits[d.buf[i]]
 		switch ind9 {
 		case invalidCharForNumber:
-			return 0, badToken(d.buf[i], d.offset()+7)
+			return 0, d.badToken(d.buf[i], d.offset()+7)
 		case dotInNumber,
 			expInNumber,
 			plusInNumber,
 			minusInNumber:
-			err := badToken(d.buf[i], d.offset()+7)
+			err := d.badToken(d.buf[i], d.offset()+7)
 			return 0, errors.Wrap(err, "unexpected floating point character")
 		case endOfNumber:
 			d.head = i
@@ -590,12 +662,12 @@ func (d *Decoder) readUInt32(c byte) (uint32, error) {
 		ind10 := floatDigits[d.buf[i]]
 		switch ind10 {
 		case invalidCharForNumber:
-			return 0, badToken(d.buf[i], d.offset()+8)
+			return 0, d.badToken(d.buf[i], d.offset()+8)
 		case dotInNumber,
 			expInNumber,
 			plusInNumber,
 			minusInNumber:
-			err := badToken(d.buf[i], d.offset()+8)
+			err := d.badToken(d.buf[i], d.offset()+8)
 			return 0, errors.Wrap(err, "unexpected floating point character")
 		case endOfNumber:
 			d.head = i
@@ -627,12 +699,12 @@ func (d *Decoder) readUInt32(c byte) (uint32, error) {
 			ind = floatDigits[c]
 			switch ind {
 			case invalidCharForNumber:
-				return 0, badToken(c, d.offset()+i)
+				return 0, d.badToken(c, d.offset()+i)
 			case dotInNumber,
 				expInNumber,
 				plusInNumber,
 				minusInNumber:
-				err := badToken(c, d.offset()+i)
+				err := d.badToken(c, d.offset()+i)
 				return 0, errors.Wrap(err, "unexpected floating point character")
 			case endOfNumber:
 				d.head += i
@@ -665,6 +737,18 @@ func (d *Decoder) Int32() (int32, error) {
 	if err != nil {
 		return 0, err
 	}
+	if d.json5 {
+		d.unread()
+		var sd Decoder
+		if err := d.json5Sub(&sd); err != nil {
+			return 0, err
+		}
+		return sd.Int32()
+	}
+	if t := types[c]; t != Number && t != Invalid {
+		return 0, d.typeError(Number, c, d.offset()-1)
+	}
+	start := d.offset() - 1
 	if c == '-' {
 		c, err := d.byte()
 		if err != nil {
@@ -672,19 +756,19 @@ func (d *Decoder) Int32() (int32, error) {
 		}
 		val, err := d.readUInt32(c)
 		if err != nil {
-			return 0, err
+			return 0, d.intError(err, start)
 		}
 		if val > math.MaxInt32+1 {
-			return 0, errOverflow
+			return 0, d.errorAt(errOverflow, start)
 		}
 		return -int32(val), nil
 	}
 	val, err := d.readUInt32(c)
 	if err != nil {
-		return 0, err
+		return 0, d.intError(err, start)
 	}
 	if val > math.MaxInt32 {
-		return 0, errOverflow
+		return 0, d.errorAt(errOverflow, start)
 	}
 	return int32(val), nil
 }
@@ -695,7 +779,23 @@ func (d *Decoder) UInt64() (uint64, error) {
 	if err != nil {
 		return 0, err
 	}
-	return d.readUInt64(c)
+	if d.json5 {
+		d.unread()
+		var sd Decoder
+		if err := d.json5Sub(&sd); err != nil {
+			return 0, err
+		}
+		return sd.UInt64()
+	}
+	if t := types[c]; t != Number && t != Invalid {
+		return 0, d.typeError(Number, c, d.offset()-1)
+	}
+	start := d.offset() - 1
+	val, err := d.readUInt64(c)
+	if err != nil {
+		return 0, d.intError(err, start)
+	}
+	return val, nil
 }
 
 func (d *Decoder) readUInt64(c byte) (uint64, error) {
@@ -707,19 +807,19 @@ func (d *Decoder) readUInt64(c byte) (uint64, error) {
 		if err == nil {
 			switch floatDigits[c] {
 			case 0, 1, 2, 3, 4, 5, 6, 7, 8, 9:
-				err := badToken(c, d.offset())
+				err := d.badToken(c, d.offset())
 				return 0, errors.Wrap(err, "digit after leading zero")
 			case dotInNumber, expInNumber, plusInNumber, minusInNumber:
-				err := badToken(c, d.offset())
+				err := d.badToken(c, d.offset())
 				return 0, errors.Wrap(err, "unexpected floating point character")
 			case invalidCharForNumber:
-				return 0, badToken(c, d.offset())
+				return 0, d.badToken(c, d.offset())
 			}
 		}
 		return 0, nil // single zero
 	default:
 		if ind < 0 {
-			return 0, badToken(c, d.offset()-1)
+			return 0, d.badToken(c, d.offset()-1)
 		}
 	}
 	value := uint64(ind)
@@ -729,12 +829,12 @@ func (d *Decoder) readUInt64(c byte) (uint64, error) {
 		ind2 := floatDigits[d.buf[i]]
 		switch ind2 {
 		case invalidCharForNumber:
-			return 0, badToken(d.buf[i], d.offset()+0)
+			return 0, d.badToken(d.buf[i], d.offset()+0)
 		case dotInNumber,
 			expInNumber,
 			plusInNumber,
 			minusInNumber:
-			err := badToken(d.buf[i], d.offset()+0)
+			err := d.badToken(d.buf[i], d.offset()+0)
 			return 0, errors.Wrap(err, "unexpected floating point character")
 		case endOfNumber:
 			d.head = i
@@ -746,12 +846,12 @@ func (d *Decoder) readUInt64(c byte) (uint64, error) {
 		ind3 := floatDigits[d.buf[i]]
 		switch ind3 {
 		case invalidCharForNumber:
-			return 0, badToken(d.buf[i], d.offset()+1)
+			return 0, d.badToken(d.buf[i], d.offset()+1)
 		case dotInNumber,
 			expInNumber,
 			plusInNumber,
 			minusInNumber:
-			err := badToken(d.buf[i], d.offset()+1)
+			err := d.badToken(d.buf[i], d.offset()+1)
 			return 0, errors.Wrap(err, "unexpected floating point character")
 		case endOfNumber:
 			d.head = i
@@ -764,12 +864,12 @@ func (d *Decoder) readUInt64(c byte) (uint64, error) {
 		ind4 := floatDigits[d.buf[i]]
 		switch ind4 {
 		case invalidCharForNumber:
-			return 0, badToken(d.buf[i], d.offset()+2)
+			return 0, d.badToken(d.buf[i], d.offset()+2)
 		case dotInNumber,
 			expInNumber,
 			plusInNumber,
 			minusInNumber:
-			err := badToken(d.buf[i], d.offset()+2)
+			err := d.badToken(d.buf[i], d.offset()+2)
 			return 0, errors.Wrap(err, "unexpected floating point character")
 		case endOfNumber:
 			d.head = i
@@ -783,12 +883,12 @@ func (d *Decoder) readUInt64(c byte) (uint64, error) {
 		ind5 := floatDigits[d.buf[i]]
 		switch ind5 {
 		case invalidCharForNumber:
-			return 0, badToken(d.buf[i], d.offset()+3)
+			return 0, d.badToken(d.buf[i], d.offset()+3)
 		case dotInNumber,
 			expInNumber,
 			plusInNumber,
 			minusInNumber:
-			err := badToken(d.buf[i], d.offset()+3)
+			err := d.badToken(d.buf[i], d.offset()+3)
 			return 0, errors.Wrap(err, "unexpected floating point character")
 		case endOfNumber:
 			d.head = i
@@ -803,12 +903,12 @@ func (d *Decoder) readUInt64(c byte) (uint64, error) {
 		ind6 := floatDigits[d.buf[i]]
 		switch ind6 {
 		case invalidCharForNumber:
-			return 0, badToken(d.buf[i], d.offset()+4)
+			return 0, d.badToken(d.buf[i], d.offset()+4)
 		case dotInNumber,
 			expInNumber,
 			plusInNumber,
 			minusInNumber:
-			err := badToken(d.buf[i], d.offset()+4)
+			err := d.badToken(d.buf[i], d.offset()+4)
 			return 0, errors.Wrap(err, "unexpected floating point character")
 		case endOfNumber:
 			d.head = i
@@ -824,12 +924,12 @@ func (d *Decoder) readUInt64(c byte) (uint64, error) {
 		ind7 := floatDigits[d.buf[i]]
 		switch ind7 {
 		case invalidCharForNumber:
-			return 0, badToken(d.buf[i], d.offset()+5)
+			return 0, d.badToken(d.buf[i], d.offset()+5)
 		case dotInNumber,
 			expInNumber,
 			plusInNumber,
 			minusInNumber:
-			err := badToken(d.buf[i], d.offset()+5)
+			err := d.badToken(d.buf[i], d.offset()+5)
 			return 0, errors.Wrap(err, "unexpected floating point character")
 		case endOfNumber:
 			d.head = i
@@ -846,12 +946,12 @@ func (d *Decoder) readUInt64(c byte) (uint64, error) {
 		ind8 := floatDigits[d.buf[i]]
 		switch ind8 {
 		case invalidCharForNumber:
-			return 0, badToken(d.buf[i], d.offset()+6)
+			return 0, d.badToken(d.buf[i], d.offset()+6)
 		case dotInNumber,
 			expInNumber,
 			plusInNumber,
 			minusInNumber:
-			err := badToken(d.buf[i], d.offset()+6)
+			err := d.badToken(d.buf[i], d.offset()+6)
 			return 0, errors.Wrap(err, "unexpected floating point character")
 		case endOfNumber:
 			d.head = i
@@ -869,12 +969,12 @@ func (d *Decoder) readUInt64(c byte) (uint64, error) {
 		ind9 := floatDigits[d.buf[i]]
 		switch ind9 {
 		case invalidCharForNumber:
-			return 0, badToken(d.buf[i], d.offset()+7)
+			return 0, d.badToken(d.buf[i], d.offset()+7)
 		case dotInNumber,
 			expInNumber,
 			plusInNumber,
 			minusInNumber:
-			err := badToken(d.buf[i], d.offset()+7)
+			err := d.badToken(d.buf[i], d.offset()+7)
 			return 0, errors.Wrap(err, "unexpected floating point character")
 		case endOfNumber:
 			d.head = i
@@ -893,12 +993,12 @@ func (d *Decoder) readUInt64(c byte) (uint64, error) {
 		ind10 := floatDigits[d.buf[i]]
 		switch ind10 {
 		case invalidCharForNumber:
-			return 0, badToken(d.buf[i], d.offset()+8)
+			return 0, d.badToken(d.buf[i], d.offset()+8)
 		case dotInNumber,
 			expInNumber,
 			plusInNumber,
 			minusInNumber:
-			err := badToken(d.buf[i], d.offset()+8)
+			err := d.badToken(d.buf[i], d.offset()+8)
 			return 0, errors.Wrap(err, "unexpected floating point character")
 		case endOfNumber:
 			d.head = i
@@ -930,12 +1030,12 @@ func (d *Decoder) readUInt64(c byte) (uint64, error) {
 			ind = floatDigits[c]
 			switch ind {
 			case invalidCharForNumber:
-				return 0, badToken(c, d.offset()+i)
+				return 0, d.badToken(c, d.offset()+i)
 			case dotInNumber,
 				expInNumber,
 				plusInNumber,
 				minusInNumber:
-				err := badToken(c, d.offset()+i)
+				err := d.badToken(c, d.offset()+i)
 				return 0, errors.Wrap(err, "unexpected floating point character")
 			case endOfNumber:
 				d.head += i
@@ -968,6 +1068,18 @@ func (d *Decoder) Int64() (int64, error) {
 	if err != nil {
 		return 0, err
 	}
+	if d.json5 {
+		d.unread()
+		var sd Decoder
+		if err := d.json5Sub(&sd); err != nil {
+			return 0, err
+		}
+		return sd.Int64()
+	}
+	if t := types[c]; t != Number && t != Invalid {
+		return 0, d.typeError(Number, c, d.offset()-1)
+	}
+	start := d.offset() - 1
 	if c == '-' {
 		c, err := d.byte()
 		if err != nil {
@@ -975,19 +1087,19 @@ func (d *Decoder) Int64() (int64, error) {
 		}
 		val, err := d.readUInt64(c)
 		if err != nil {
-			return 0, err
+			return 0, d.intError(err, start)
 		}
 		if val > math.MaxInt64+1 {
-			return 0, errOverflow
+			return 0, d.errorAt(errOverflow, start)
 		}
 		return -int64(val), nil
 	}
 	val, err := d.readUInt64(c)
 	if err != nil {
-		return 0, err
+		return 0, d.intError(err, start)
 	}
 	if val > math.MaxInt64 {
-		return 0, errOverflow
+		return 0, d.errorAt(errOverflow, start)
 	}
 	return int64(val), nil
 }
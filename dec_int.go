@@ -21,6 +21,15 @@ func (d *Decoder) int(size int) (int, error) {
 	}
 }
 
+// intError returns error of reading integer started at offset, reporting
+// overflow at its position.
+func (d *Decoder) intError(err error, offset int) error {
+	if err == errOverflow {
+		return d.errorAt(err, offset)
+	}
+	return err
+}
+
 // Int reads int.
 func (d *Decoder) Int() (int, error) {
 	return d.int(strconv.IntSize)
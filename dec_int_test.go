@@ -141,58 +141,58 @@ func TestDecoderIntUnexpectedChar(t *testing.T) {
 		{" -10", false, 0, ""},
 
 		// Space in the middle.
-		{"- 10", false, 0, "unexpected byte 32 ' ' at 1"},
+		{"- 10", false, 0, "unexpected byte 32 ' ' at 1 (line 1, column 2)"},
 
 		// Digit after leading zero.
-		{"00", true, 0, "digit after leading zero: unexpected byte 48 '0' at 1"},
-		{"01", true, 0, "digit after leading zero: unexpected byte 49 '1' at 1"},
+		{"00", true, 0, "digit after leading zero: unexpected byte 48 '0' at 1 (line 1, column 2)"},
+		{"01", true, 0, "digit after leading zero: unexpected byte 49 '1' at 1 (line 1, column 2)"},
 
 		// Unexpected character.
 		// 8 bits.
-		{"0a0", true, 0, "unexpected byte 97 'a' at 1"},
-		{"1a00000000000", true, 0, "unexpected byte 97 'a' at 1"},
-		{"10a0000000000", true, 0, "unexpected byte 97 'a' at 2"},
-		{"100a000000000", true, 0, "unexpected byte 97 'a' at 3"},
+		{"0a0", true, 0, "unexpected byte 97 'a' at 1 (line 1, column 2)"},
+		{"1a00000000000", true, 0, "unexpected byte 97 'a' at 1 (line 1, column 2)"},
+		{"10a0000000000", true, 0, "unexpected byte 97 'a' at 2 (line 1, column 3)"},
+		{"100a000000000", true, 0, "unexpected byte 97 'a' at 3 (line 1, column 4)"},
 		// 16 bits.
-		{"1000a00000000", true, 16, "unexpected byte 97 'a' at 4"},
-		{"10000a0000000", true, 16, "unexpected byte 97 'a' at 5"},
+		{"1000a00000000", true, 16, "unexpected byte 97 'a' at 4 (line 1, column 5)"},
+		{"10000a0000000", true, 16, "unexpected byte 97 'a' at 5 (line 1, column 6)"},
 		// 32 bits.
-		{"100000a000000", true, 32, "unexpected byte 97 'a' at 6"},
-		{"1000000a00000", true, 32, "unexpected byte 97 'a' at 7"},
-		{"10000000a0000", true, 32, "unexpected byte 97 'a' at 8"},
-		{"100000000a000", true, 32, "unexpected byte 97 'a' at 9"},
-		{"1000000000a00", true, 32, "unexpected byte 97 'a' at 10"},
+		{"100000a000000", true, 32, "unexpected byte 97 'a' at 6 (line 1, column 7)"},
+		{"1000000a00000", true, 32, "unexpected byte 97 'a' at 7 (line 1, column 8)"},
+		{"10000000a0000", true, 32, "unexpected byte 97 'a' at 8 (line 1, column 9)"},
+		{"100000000a000", true, 32, "unexpected byte 97 'a' at 9 (line 1, column 10)"},
+		{"1000000000a00", true, 32, "unexpected byte 97 'a' at 10 (line 1, column 11)"},
 		// 64 bits.
-		{"10000000000a0", true, 64, "unexpected byte 97 'a' at 11"},
+		{"10000000000a0", true, 64, "unexpected byte 97 'a' at 11 (line 1, column 12)"},
 
 		// Dot in integer.
 		// 8 bits.
-		{"0.0", true, 0, "unexpected floating point character: unexpected byte 46 '.' at 1"},
-		{"1.00000000000", true, 0, "unexpected floating point character: unexpected byte 46 '.' at 1"},
-		{"10.0000000000", true, 0, "unexpected floating point character: unexpected byte 46 '.' at 2"},
-		{"100.000000000", true, 0, "unexpected floating point character: unexpected byte 46 '.' at 3"},
+		{"0.0", true, 0, "unexpected floating point character: unexpected byte 46 '.' at 1 (line 1, column 2)"},
+		{"1.00000000000", true, 0, "unexpected floating point character: unexpected byte 46 '.' at 1 (line 1, column 2)"},
+		{"10.0000000000", true, 0, "unexpected floating point character: unexpected byte 46 '.' at 2 (line 1, column 3)"},
+		{"100.000000000", true, 0, "unexpected floating point character: unexpected byte 46 '.' at 3 (line 1, column 4)"},
 		// 16 bits.
-		{"1000.00000000", true, 16, "unexpected floating point character: unexpected byte 46 '.' at 4"},
-		{"10000.0000000", true, 16, "unexpected floating point character: unexpected byte 46 '.' at 5"},
+		{"1000.00000000", true, 16, "unexpected floating point character: unexpected byte 46 '.' at 4 (line 1, column 5)"},
+		{"10000.0000000", true, 16, "unexpected floating point character: unexpected byte 46 '.' at 5 (line 1, column 6)"},
 		// 32 bits.
-		{"100000.000000", true, 32, "unexpected floating point character: unexpected byte 46 '.' at 6"},
-		{"1000000.00000", true, 32, "unexpected floating point character: unexpected byte 46 '.' at 7"},
-		{"10000000.0000", true, 32, "unexpected floating point character: unexpected byte 46 '.' at 8"},
-		{"100000000.000", true, 32, "unexpected floating point character: unexpected byte 46 '.' at 9"},
-		{"1000000000.00", true, 32, "unexpected floating point character: unexpected byte 46 '.' at 10"},
+		{"100000.000000", true, 32, "unexpected floating point character: unexpected byte 46 '.' at 6 (line 1, column 7)"},
+		{"1000000.00000", true, 32, "unexpected floating point character: unexpected byte 46 '.' at 7 (line 1, column 8)"},
+		{"10000000.0000", true, 32, "unexpected floating point character: unexpected byte 46 '.' at 8 (line 1, column 9)"},
+		{"100000000.000", true, 32, "unexpected floating point character: unexpected byte 46 '.' at 9 (line 1, column 10)"},
+		{"1000000000.00", true, 32, "unexpected floating point character: unexpected byte 46 '.' at 10 (line 1, column 11)"},
 		// 64 bits.
-		{"10000000000.0", true, 64, "unexpected floating point character: unexpected byte 46 '.' at 11"},
+		{"10000000000.0", true, 64, "unexpected floating point character: unexpected byte 46 '.' at 11 (line 1, column 12)"},
 
 		// Exp in integer.
-		{"0e0", true, 0, "unexpected floating point character: unexpected byte 101 'e' at 1"},
-		{"0E0", true, 0, "unexpected floating point character: unexpected byte 69 'E' at 1"},
-		{"0e-0", true, 0, "unexpected floating point character: unexpected byte 101 'e' at 1"},
-		{"0e+0", true, 0, "unexpected floating point character: unexpected byte 101 'e' at 1"},
-
-		{"1e0", true, 0, "unexpected floating point character: unexpected byte 101 'e' at 1"},
-		{"1E0", true, 0, "unexpected floating point character: unexpected byte 69 'E' at 1"},
-		{"1e-0", true, 0, "unexpected floating point character: unexpected byte 101 'e' at 1"},
-		{"1e+0", true, 0, "unexpected floating point character: unexpected byte 101 'e' at 1"},
+		{"0e0", true, 0, "unexpected floating point character: unexpected byte 101 'e' at 1 (line 1, column 2)"},
+		{"0E0", true, 0, "unexpected floating point character: unexpected byte 69 'E' at 1 (line 1, column 2)"},
+		{"0e-0", true, 0, "unexpected floating point character: unexpected byte 101 'e' at 1 (line 1, column 2)"},
+		{"0e+0", true, 0, "unexpected floating point character: unexpected byte 101 'e' at 1 (line 1, column 2)"},
+
+		{"1e0", true, 0, "unexpected floating point character: unexpected byte 101 'e' at 1 (line 1, column 2)"},
+		{"1E0", true, 0, "unexpected floating point character: unexpected byte 69 'E' at 1 (line 1, column 2)"},
+		{"1e-0", true, 0, "unexpected floating point character: unexpected byte 101 'e' at 1 (line 1, column 2)"},
+		{"1e+0", true, 0, "unexpected floating point character: unexpected byte 101 'e' at 1 (line 1, column 2)"},
 	}
 
 	for i, tt := range tests {
@@ -211,7 +211,7 @@ func TestDecoderIntUnexpectedChar(t *testing.T) {
 							err := intFn.fn(d)
 							if e := tt.errString; e != "" {
 								a.EqualError(err, e)
-								v, ok := errors.Into[*badTokenErr](err)
+								v, ok := errors.Into[*SyntaxError](err)
 								if !ok {
 									return
 								}
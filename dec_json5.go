@@ -0,0 +1,491 @@
+package jx
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"strconv"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/go-faster/errors"
+)
+
+// SetJSON5 enables or disables relaxed JSON5 syntax.
+//
+// In JSON5 mode Decoder accepts:
+//   - single line and block comments
+//   - additional whitespace, like \v, \f, U+00A0, U+FEFF, U+2028, U+2029
+//     and other Unicode space separators
+//   - trailing commas in objects and arrays
+//   - single-quoted strings and additional escapes, like \x41 and \'
+//   - unquoted identifier keys, like {key: 1}
+//   - hexadecimal numbers, like 0x1F
+//   - leading and trailing decimal point, like .5 and 5.
+//   - explicit plus sign, like +1
+//   - NaN, Infinity and -Infinity
+//
+// Numbers are normalized to JSON syntax by Num, so NaN and Infinity can be
+// decoded only by Float32 and Float64.
+// Mode is kept on Reset and ResetBytes.
+//
+// See https://spec.json5.org.
+func (d *Decoder) SetJSON5(enabled bool) {
+	d.json5 = enabled
+}
+
+var json5Types [256]Type
+
+func init() {
+	copy(json5Types[:], types)
+	json5Types['\''] = String
+	for _, c := range []byte("+.IN") {
+		json5Types[c] = Number
+	}
+}
+
+var json5NumSet = [256]bool{
+	'+': true, '-': true, '.': true,
+}
+
+func init() {
+	for c := '0'; c <= '9'; c++ {
+		json5NumSet[c] = true
+	}
+	for c := 'a'; c <= 'z'; c++ {
+		json5NumSet[c] = true
+		json5NumSet[c-'a'+'A'] = true
+	}
+}
+
+// skipJSON5Space skips JSON5 whitespace or comment that starts with c at
+// head, reporting whether anything was skipped.
+func (d *Decoder) skipJSON5Space(c byte) (bool, error) {
+	switch c {
+	case '\v', '\f':
+		d.head++
+		return true, nil
+	case '/':
+		return true, d.skipComment()
+	case 0xC2, 0xE1, 0xE2, 0xE3, 0xEF:
+		// Lead bytes of non-ASCII whitespace.
+		n, err := d.spaceRune(c)
+		d.head += n
+		return n > 0, err
+	default:
+		return false, nil
+	}
+}
+
+// spaceRune returns length of JSON5 whitespace rune at head, starting with
+// lead byte c, or zero if rune is not whitespace.
+func (d *Decoder) spaceRune(c byte) (int, error) {
+	size := 2
+	if c >= 0xE0 {
+		size = 3
+	}
+	if err := d.fill(size); err != nil {
+		if err == io.EOF {
+			return 0, d.unexpectedEOF()
+		}
+		return 0, err
+	}
+	r, size := utf8.DecodeRune(d.buf[d.head:d.tail])
+	if !isJSON5Space(r) {
+		return 0, nil
+	}
+	return size, nil
+}
+
+// isJSON5Space reports whether non-ASCII r is JSON5 whitespace or line
+// terminator.
+func isJSON5Space(r rune) bool {
+	return r == '\u2028' || r == '\u2029' || r == '\uFEFF' || unicode.Is(unicode.Zs, r)
+}
+
+// skipComment skips comment, starting with '/' at head.
+//
+// Returns SyntaxError if '/' does not start a comment.
+func (d *Decoder) skipComment() error {
+	offset := d.offset()
+	if err := d.fill(2); err != nil {
+		if err == io.EOF {
+			return d.unexpectedEOF()
+		}
+		return err
+	}
+	switch d.buf[d.head+1] {
+	case '/':
+		d.head += 2
+		for {
+			if i := bytes.IndexByte(d.buf[d.head:d.tail], '\n'); i >= 0 {
+				d.head += i + 1
+				return nil
+			}
+			if err := d.read(); err != nil {
+				if err == io.EOF {
+					return nil
+				}
+				return err
+			}
+		}
+	case '*':
+		d.head += 2
+		var prev byte
+		for {
+			for i, c := range d.buf[d.head:d.tail] {
+				if prev == '*' && c == '/' {
+					d.head += i + 1
+					return nil
+				}
+				prev = c
+			}
+			if err := d.read(); err != nil {
+				if err == io.EOF {
+					err = d.unexpectedEOF()
+				}
+				return errors.Wrap(err, "unterminated comment")
+			}
+		}
+	default:
+		return d.badToken('/', offset)
+	}
+}
+
+// trailingComma reports whether comma is followed by end in JSON5 mode,
+// consuming end.
+func (d *Decoder) trailingComma(end byte) (bool, error) {
+	if !d.json5 {
+		return false, nil
+	}
+	c, err := d.more()
+	if err != nil {
+		return false, err
+	}
+	if c == end {
+		return true, nil
+	}
+	d.unread()
+	return false, nil
+}
+
+// skipJSON5 skips JSON5 string or number, starting with c.
+//
+// Returns false if c does not start JSON5 string or number.
+func (d *Decoder) skipJSON5(c byte) (bool, error) {
+	switch json5Types[c] {
+	case String:
+		d.unread()
+		_, err := d.strJSON5(value{})
+		return true, err
+	case Number:
+		d.unread()
+		_, _, err := d.json5Num()
+		return true, err
+	default:
+		return false, nil
+	}
+}
+
+// strJSON5 reads double or single quoted JSON5 string.
+func (d *Decoder) strJSON5(v value) (value, error) {
+	quote, err := d.more()
+	if err != nil {
+		return value{}, err
+	}
+	if quote != '"' && quote != '\'' {
+		return value{}, d.typeError(String, quote, d.offset()-1)
+	}
+	v = value{buf: v.buf}
+	start := d.offset()
+	for {
+		offset := d.offset()
+		c, err := d.byte()
+		if err != nil {
+			return value{}, err
+		}
+		switch {
+		case c == quote:
+			if err := d.checkStrLen(start, offset); err != nil {
+				return value{}, err
+			}
+			return v, nil
+		case c == '\\':
+			c, err := d.byte()
+			if err != nil {
+				return value{}, err
+			}
+			if v, err = d.escapedCharJSON5(v, c); err != nil {
+				return v, errors.Wrap(err, "escape")
+			}
+		case c < ' ':
+			return v, d.badToken(c, offset)
+		case c >= utf8.RuneSelf && d.utf8 != UTF8Unchecked:
+			r, ok, err := d.readRune(c)
+			if err != nil {
+				return value{}, err
+			}
+			if !ok && d.utf8 == UTF8Strict {
+				return v, d.errorAt(errors.Wrapf(ErrInvalidUTF8, "byte %#x", c), offset)
+			}
+			v = v.rune(r)
+		default:
+			v.buf = append(v.buf, c)
+		}
+	}
+}
+
+func (d *Decoder) escapedCharJSON5(v value, c byte) (value, error) {
+	switch c {
+	case '\'':
+		v.buf = append(v.buf, '\'')
+	case 'v':
+		v.buf = append(v.buf, '\v')
+	case '0':
+		if next, err := d.peek(); err == nil && next >= '0' && next <= '9' {
+			return v, d.badToken(next, d.offset())
+		}
+		v.buf = append(v.buf, 0)
+	case 'x':
+		var r rune
+		for i := 0; i < 2; i++ {
+			h, err := d.byte()
+			if err != nil {
+				return v, err
+			}
+			val := hexSet[h]
+			if val == 0 {
+				return v, d.badToken(h, d.offset()-1)
+			}
+			r = r*16 + rune(val-1)
+		}
+		v = v.rune(r)
+	case '\r':
+		// Line continuation.
+		if next, err := d.peek(); err == nil && next == '\n' {
+			d.head++
+		}
+	case '\n':
+		// Line continuation.
+	case '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		return v, d.badToken(c, d.offset()-1)
+	default:
+		if escapedStrSet[c] != 0 {
+			return d.escapedChar(v, c)
+		}
+		// Any other character is escaped as itself.
+		v.buf = append(v.buf, c)
+	}
+	return v, nil
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || c == '$' || c >= utf8.RuneSelf ||
+		(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// keyJSON5 reads JSON5 object key, which can be identifier.
+func (d *Decoder) keyJSON5(v value) (value, error) {
+	c, err := d.more()
+	if err != nil {
+		return value{}, err
+	}
+	d.unread()
+	if !isIdentStart(c) {
+		return d.strJSON5(v)
+	}
+	v = value{buf: v.buf}
+	for {
+		buf := d.buf[d.head:d.tail]
+		i := 0
+		for i < len(buf) {
+			c := buf[i]
+			if c < utf8.RuneSelf {
+				if !isIdentStart(c) && !(c >= '0' && c <= '9') {
+					break
+				}
+				i++
+				continue
+			}
+			if !utf8.FullRune(buf[i:]) {
+				break
+			}
+			r, size := utf8.DecodeRune(buf[i:])
+			if isJSON5Space(r) {
+				break
+			}
+			i += size
+		}
+		v.buf = append(v.buf, buf[:i]...)
+		d.head += i
+		rest := buf[i:]
+		if len(rest) > 0 && utf8.FullRune(rest) {
+			return v, nil
+		}
+		var err error
+		if len(rest) > 0 {
+			// Rune is split between reads.
+			err = d.fill(len(rest) + 1)
+		} else {
+			err = d.read()
+		}
+		if err != nil {
+			if err == io.EOF {
+				err = d.unexpectedEOF()
+			}
+			return value{}, err
+		}
+	}
+}
+
+// json5Num reads JSON5 number and returns it in JSON syntax.
+//
+// NaN and Infinity are returned as f with nil num.
+func (d *Decoder) json5Num() (num []byte, f float64, _ error) {
+	if err := d.skipSpace(); err != nil {
+		return nil, 0, err
+	}
+	var (
+		offset = d.offset()
+		tok    []byte
+	)
+	for {
+		buf := d.buf[d.head:d.tail]
+		i := 0
+		for i < len(buf) && json5NumSet[buf[i]] {
+			i++
+		}
+		tok = append(tok, buf[:i]...)
+		d.head += i
+		if err := d.checkNumLen(len(tok)); err != nil {
+			return nil, 0, err
+		}
+		if i < len(buf) {
+			break
+		}
+		if err := d.read(); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, 0, err
+		}
+	}
+	if len(tok) == 0 {
+		c, err := d.peek()
+		if err != nil {
+			return nil, 0, err
+		}
+		return nil, 0, d.badToken(c, offset)
+	}
+
+	var (
+		k   int
+		neg bool
+	)
+	if c := tok[0]; c == '+' || c == '-' {
+		neg = c == '-'
+		k++
+	}
+	switch rest := tok[k:]; {
+	case string(rest) == "Infinity":
+		if neg {
+			return nil, math.Inf(-1), nil
+		}
+		return nil, math.Inf(1), nil
+	case string(rest) == "NaN":
+		return nil, math.NaN(), nil
+	case len(rest) > 2 && rest[0] == '0' && (rest[1] == 'x' || rest[1] == 'X'):
+		for i, c := range rest[2:] {
+			if hexSet[c] == 0 {
+				return nil, 0, d.badToken(c, offset+k+2+i)
+			}
+		}
+		v, err := strconv.ParseUint(string(rest[2:]), 16, 64)
+		if err != nil {
+			return nil, 0, d.errorAt(errOverflow, offset)
+		}
+		if neg {
+			num = append(num, '-')
+		}
+		return strconv.AppendUint(num, v, 10), 0, nil
+	}
+
+	digits := func() []byte {
+		start := k
+		for k < len(tok) && tok[k] >= '0' && tok[k] <= '9' {
+			k++
+		}
+		return tok[start:k]
+	}
+	intPart := digits()
+	if len(intPart) > 1 && intPart[0] == '0' {
+		err := d.badToken(intPart[1], offset+k-len(intPart)+1)
+		return nil, 0, errors.Wrap(err, "digit after leading zero")
+	}
+	var frac, exp []byte
+	if k < len(tok) && tok[k] == '.' {
+		k++
+		frac = digits()
+	}
+	if len(intPart) == 0 && len(frac) == 0 {
+		i := k
+		if i >= len(tok) {
+			i = len(tok) - 1
+		}
+		return nil, 0, d.badToken(tok[i], offset+i)
+	}
+	if k < len(tok) && (tok[k] == 'e' || tok[k] == 'E') {
+		start := k
+		k++
+		if k < len(tok) && (tok[k] == '+' || tok[k] == '-') {
+			k++
+		}
+		if len(digits()) == 0 {
+			if k == len(tok) {
+				return nil, 0, d.errorAt(io.ErrUnexpectedEOF, offset+k)
+			}
+			return nil, 0, d.badToken(tok[k], offset+k)
+		}
+		exp = tok[start:k]
+	}
+	if k < len(tok) {
+		return nil, 0, d.badToken(tok[k], offset+k)
+	}
+
+	if neg {
+		num = append(num, '-')
+	}
+	if len(intPart) == 0 {
+		num = append(num, '0')
+	}
+	num = append(num, intPart...)
+	if len(frac) > 0 {
+		num = append(num, '.')
+		num = append(num, frac...)
+	}
+	return append(num, exp...), 0, nil
+}
+
+// json5Sub resets sd to normalized JSON5 number.
+func (d *Decoder) json5Sub(sd *Decoder) error {
+	offset := d.offset()
+	num, f, err := d.json5Num()
+	if err != nil {
+		return err
+	}
+	if num == nil {
+		return d.errorAt(errors.Errorf("%v is not representable", f), offset)
+	}
+	d.subDecoder(sd, num, offset)
+	return nil
+}
+
+// subDecoder resets sd to read num, which replaces input of d at offset,
+// so errors of sd have position in input of d.
+func (d *Decoder) subDecoder(sd *Decoder, num []byte, offset int) {
+	sd.ResetBytes(num)
+	sd.owner = d.errOwner()
+	line, column := d.position(offset)
+	sd.streamOffset = offset
+	sd.lineBase = linePos{offset: offset, line: line - 1, start: offset - column + 1}
+}
@@ -0,0 +1,269 @@
+package jx
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/go-faster/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// json5Example is example document from https://json5.org.
+const json5Example = `// comments
+{
+  unquoted: 'and you can quote me on that',
+  singleQuotes: 'I can use "double quotes" here',
+  lineBreaks: "Look, Mom! \
+No \\n's!",
+  hexadecimal: 0xdecaf,
+  leadingDecimalPoint: .8675309, andTrailing: 8675309.,
+  positiveSign: +1,
+  trailingComma: 'in objects', andIn: ['arrays',],
+  "backwardsCompatible": "with JSON",
+}
+`
+
+func TestDecoder_SetJSON5(t *testing.T) {
+	const expect = `{"unquoted":"and you can quote me on that",` +
+		`"singleQuotes":"I can use \"double quotes\" here",` +
+		`"lineBreaks":"Look, Mom! No \\n's!",` +
+		`"hexadecimal":912559,` +
+		`"leadingDecimalPoint":0.8675309,"andTrailing":8675309,` +
+		`"positiveSign":1,` +
+		`"trailingComma":"in objects","andIn":["arrays"],` +
+		`"backwardsCompatible":"with JSON"}`
+	t.Run("Any", testBufferReader(json5Example, func(t *testing.T, d *Decoder) {
+		a := require.New(t)
+		d.SetJSON5(true)
+		v, err := d.Any()
+		a.NoError(err)
+		var e Encoder
+		e.Any(v)
+		a.Equal(expect, e.String())
+	}))
+	t.Run("Validate", testBufferReader(json5Example, func(t *testing.T, d *Decoder) {
+		d.SetJSON5(true)
+		require.NoError(t, d.Validate())
+	}))
+	t.Run("Strict", func(t *testing.T) {
+		require.Error(t, DecodeStr(json5Example).Validate())
+	})
+	t.Run("Iter", testBufferReader(`{a: [1, 2, ], b: [/**/ ], }`, func(t *testing.T, d *Decoder) {
+		a := require.New(t)
+		d.SetJSON5(true)
+		var values []int
+		obj, err := d.ObjIter()
+		a.NoError(err)
+		for obj.Next() {
+			arr, err := d.ArrIter()
+			a.NoError(err)
+			for arr.Next() {
+				v, err := d.Int()
+				a.NoError(err)
+				values = append(values, v)
+			}
+			a.NoError(arr.Err())
+		}
+		a.NoError(obj.Err())
+		a.Equal([]int{1, 2}, values)
+	}))
+}
+
+func TestDecoder_JSON5(t *testing.T) {
+	for i, tt := range []struct {
+		input  string
+		expect string
+	}{
+		// Comments.
+		{"/* a */ 1 // b", `1`},
+		{"[1, /* a */ 2 /**/, // b\n 3]", `[1,2,3]`},
+		{"{/**/a/**/:/**/1/**/}", `{"a":1}`},
+		{"/* * / */ true", `true`},
+		{"\v\f[\f1\v]", `[1]`},
+		// Unicode whitespace.
+		{"\u00a0[\ufeff1\u2028,\u3000 2\u2029]\u202f", `[1,2]`},
+		{"{a\u00a0:1,\u1680ключ\u2000:2}", `{"a":1,"ключ":2}`},
+		// Trailing commas.
+		{`[1,]`, `[1]`},
+		{`[[],[],]`, `[[],[]]`},
+		{`{"a":1,}`, `{"a":1}`},
+		{`{a:{b:[]},}`, `{"a":{"b":[]}}`},
+		// Strings.
+		{`'a"b'`, `"a\"b"`},
+		{`'a\'b'`, `"a'b"`},
+		{`"\x41\v\0B\/"`, `"A\u000b\u0000B/"`},
+		{"'a\\\r\nb'", `"ab"`},
+		{`'\d'`, `"d"`},
+		{`'😀'`, `"😀"`},
+		// Keys.
+		{`{$_a1:1,ключ:2,'c':3}`, `{"$_a1":1,"ключ":2,"c":3}`},
+		// Numbers.
+		{`0x1F`, `31`},
+		{`-0XfF`, `-255`},
+		{`+1`, `1`},
+		{`.5`, `0.5`},
+		{`-.5e1`, `-0.5e1`},
+		{`5.`, `5`},
+		{`+5.e-1`, `5e-1`},
+		{`[1,-2.5,1E3]`, `[1,-2.5,1E3]`},
+	} {
+		tt := tt
+		t.Run(fmt.Sprintf("Test%d", i+1), testBufferReader(tt.input, func(t *testing.T, d *Decoder) {
+			a := require.New(t)
+			d.SetJSON5(true)
+			v, err := d.Any()
+			a.NoError(err)
+			var e Encoder
+			e.Any(v)
+			a.Equal(tt.expect, e.String())
+		}))
+	}
+}
+
+func TestDecoder_JSON5Number(t *testing.T) {
+	for _, tt := range []struct {
+		input string
+		value float64
+	}{
+		{`NaN`, math.NaN()},
+		{`Infinity`, math.Inf(1)},
+		{`+Infinity`, math.Inf(1)},
+		{`-Infinity`, math.Inf(-1)},
+		{`0x10`, 16},
+		{`.25`, 0.25},
+		{`-1.`, -1},
+	} {
+		tt := tt
+		t.Run(tt.input, testBufferReader(tt.input, func(t *testing.T, d *Decoder) {
+			a := require.New(t)
+			d.SetJSON5(true)
+			a.Equal(Number, d.Next())
+			f, err := d.Float64()
+			a.NoError(err)
+			if math.IsNaN(tt.value) {
+				a.True(math.IsNaN(f))
+				return
+			}
+			a.Equal(tt.value, f)
+		}))
+	}
+	t.Run("Int", func(t *testing.T) {
+		a := require.New(t)
+		for input, expect := range map[string]int64{
+			`0x7fffffffffffffff`: math.MaxInt64,
+			`-0x10`:              -16,
+			`+7`:                 7,
+			`7.`:                 7,
+		} {
+			d := DecodeStr(input)
+			d.SetJSON5(true)
+			v, err := d.Int64()
+			a.NoError(err, input)
+			a.Equal(expect, v, input)
+		}
+		for _, input := range []string{
+			`0x8000000000000000`,
+			`0x`,
+			`0xG`,
+			`NaN`,
+			`.5`,
+			`01`,
+		} {
+			d := DecodeStr(input)
+			d.SetJSON5(true)
+			_, err := d.Int64()
+			a.Error(err, input)
+		}
+		d := DecodeStr(`0x80000000`)
+		d.SetJSON5(true)
+		_, err := d.Int32()
+		a.Error(err)
+	})
+}
+
+func TestDecoder_JSON5Error(t *testing.T) {
+	for _, input := range []string{
+		`/`,
+		`/* a`,
+		`[1,,]`,
+		`[,]`,
+		`{,}`,
+		`{a:1,,}`,
+		`{1a:1}`,
+		`'a`,
+		`'\1'`,
+		`'\xZZ'`,
+		`"\0" 1`,
+		`.`,
+		`+`,
+		`1e`,
+		`1e+`,
+		`1.2.3`,
+		`Infinit`,
+		`-NaNa`,
+		`0x1.5`,
+		`undefined`,
+		"'a\nb'",
+		`[1,/x]`,
+		`[1/]`,
+		"[1,\u00a1]",
+		"1 \xe2\x80",
+	} {
+		input := input
+		t.Run(input, testBufferReader(input, func(t *testing.T, d *Decoder) {
+			d.SetJSON5(true)
+			require.Error(t, d.Validate())
+		}))
+	}
+	t.Run("Slash", testBufferReader(`[1, /x]`, func(t *testing.T, d *Decoder) {
+		a := require.New(t)
+		d.SetJSON5(true)
+		err := d.Validate()
+		e, ok := errors.Into[*SyntaxError](err)
+		a.True(ok, "%+v", err)
+		a.Equal(byte('/'), e.Token)
+		a.Equal(4, e.Offset)
+	}))
+}
+
+func TestDecoder_JSON5ArrLen(t *testing.T) {
+	for i, tt := range []struct {
+		input string
+		fn    func(d *Decoder) error
+		err   bool
+	}{
+		{`[1,]`, (*Decoder).Validate, false},
+		{`[1,2,]`, (*Decoder).Validate, true},
+		{`[1,]`, func(d *Decoder) error {
+			return d.Arr(func(d *Decoder) error { return d.Skip() })
+		}, false},
+		{`[1,2,]`, func(d *Decoder) error {
+			return d.Arr(func(d *Decoder) error { return d.Skip() })
+		}, true},
+		{`[1,]`, func(d *Decoder) error {
+			iter, err := d.ArrIter()
+			if err != nil {
+				return err
+			}
+			for iter.Next() {
+				if err := d.Skip(); err != nil {
+					return err
+				}
+			}
+			return iter.Err()
+		}, false},
+	} {
+		tt := tt
+		t.Run(fmt.Sprintf("Test%d", i+1), testBufferReader(tt.input, func(t *testing.T, d *Decoder) {
+			d.SetJSON5(true)
+			d.SetLimits(Limits{ArrLen: 1})
+			err := tt.fn(d)
+			if tt.err {
+				require.ErrorIs(t, err, ErrMaxArrLen)
+				return
+			}
+			require.NoError(t, err)
+		}))
+	}
+}
@@ -0,0 +1,66 @@
+package jx
+
+import "fmt"
+
+// DuplicateKeyError means that object has multiple members with same key.
+//
+// Keys are compared after unescaping, so "a" and "\u0061" are same key.
+type DuplicateKeyError struct {
+	Key    string // unescaped key
+	Offset int    // offset of duplicate key in input, starting from 0
+}
+
+func (e *DuplicateKeyError) Error() string {
+	return fmt.Sprintf("duplicate key %q at %d", e.Key, e.Offset)
+}
+
+// DisallowDuplicateKeys makes Decoder to return DuplicateKeyError
+// if object has duplicate keys.
+//
+// Applies to Obj, ObjBytes, ObjIter, Skip and Validate.
+// Setting is kept on Reset and ResetBytes.
+func (d *Decoder) DisallowDuplicateKeys(disallow bool) {
+	d.noDupKeys = disallow
+}
+
+// objStart starts tracking keys of object at current depth.
+func (d *Decoder) objStart() {
+	if !d.noDupKeys {
+		return
+	}
+	for len(d.keys) <= d.depth {
+		d.keys = append(d.keys, map[string]struct{}{})
+	}
+	keys := d.keys[d.depth]
+	for k := range keys {
+		delete(keys, k)
+	}
+}
+
+// objKey reads key of object member.
+func (d *Decoder) objKey(v value) (value, error) {
+	if !d.noDupKeys && !d.json5 {
+		return d.str(v)
+	}
+	if err := d.skipSpace(); err != nil {
+		return value{}, err
+	}
+	offset := d.offset()
+	read := d.str
+	if d.json5 {
+		read = d.keyJSON5
+	}
+	k, err := read(v)
+	if err != nil || !d.noDupKeys {
+		return k, err
+	}
+	keys := d.keys[d.depth]
+	if _, ok := keys[string(k.buf)]; ok {
+		return k, &DuplicateKeyError{
+			Key:    string(k.buf),
+			Offset: offset,
+		}
+	}
+	keys[string(k.buf)] = struct{}{}
+	return k, nil
+}
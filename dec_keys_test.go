@@ -0,0 +1,84 @@
+package jx
+
+import (
+	"testing"
+
+	"github.com/go-faster/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecoder_DisallowDuplicateKeys(t *testing.T) {
+	readObj := func(d *Decoder) error {
+		return d.Obj(func(d *Decoder, key string) error {
+			return d.Skip()
+		})
+	}
+	readObjIter := func(d *Decoder) error {
+		iter, err := d.ObjIter()
+		if err != nil {
+			return err
+		}
+		for iter.Next() {
+			if err := d.Skip(); err != nil {
+				return err
+			}
+		}
+		return iter.Err()
+	}
+	fns := map[string]func(d *Decoder) error{
+		"Obj":      readObj,
+		"ObjIter":  readObjIter,
+		"Validate": (*Decoder).Validate,
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		for _, input := range []string{
+			`{}`,
+			`{"a":1,"b":2}`,
+			`{"a":{"a":1},"b":{"a":2}}`,
+			`{"a":[{"a":1},{"a":1}]}`,
+			`{"a":1,"A":2,"a ":3}`,
+		} {
+			input := input
+			for name, fn := range fns {
+				fn := fn
+				t.Run(name, testBufferReader(input, func(t *testing.T, d *Decoder) {
+					d.DisallowDuplicateKeys(true)
+					require.NoError(t, fn(d))
+				}))
+			}
+		}
+	})
+	t.Run("Duplicate", func(t *testing.T) {
+		for _, tt := range []struct {
+			input  string
+			key    string
+			offset int
+		}{
+			{`{"a":1,"a":2}`, "a", 7},
+			{`{"a":1, "b":2,  "a":3}`, "a", 16},
+			{`{"a":1,"\u0061":2}`, "a", 7},
+			{`{"\n":1,"\u000a":2}`, "\n", 8},
+			{`{"a":{"b":1,"c":2,"b":3}}`, "b", 18},
+			{`{"😀":1,"😀":2}`, "😀", 10},
+		} {
+			tt := tt
+			for name, fn := range fns {
+				fn := fn
+				t.Run(name, testBufferReader(tt.input, func(t *testing.T, d *Decoder) {
+					a := require.New(t)
+					d.DisallowDuplicateKeys(true)
+					err := fn(d)
+
+					e, ok := errors.Into[*DuplicateKeyError](err)
+					a.True(ok, "%+v", err)
+					a.Equal(tt.key, e.Key)
+					a.Equal(tt.offset, e.Offset)
+				}))
+			}
+			t.Run("Allowed", func(t *testing.T) {
+				require.NoError(t, DecodeStr(tt.input).Validate())
+			})
+		}
+	})
+}
@@ -0,0 +1,130 @@
+package jx
+
+import (
+	"io"
+
+	"github.com/go-faster/errors"
+)
+
+// Limits of decoded input.
+//
+// Zero value of any field means no limit, except Depth, which defaults
+// to 10000.
+type Limits struct {
+	// Depth is maximum nesting depth of objects and arrays.
+	Depth int
+	// StrLen is maximum length of string in bytes of input,
+	// excluding quotes.
+	StrLen int
+	// NumLen is maximum length of number literal in bytes of input.
+	NumLen int
+	// ObjLen is maximum count of object members.
+	ObjLen int
+	// ArrLen is maximum count of array elements.
+	ArrLen int
+	// Bytes is maximum total count of input bytes.
+	Bytes int
+}
+
+var (
+	// ErrMaxDepth means that Limits.Depth is exceeded.
+	ErrMaxDepth = errors.New("depth: maximum")
+	// ErrMaxStrLen means that Limits.StrLen is exceeded.
+	ErrMaxStrLen = errors.New("string: maximum length")
+	// ErrMaxNumLen means that Limits.NumLen is exceeded.
+	ErrMaxNumLen = errors.New("number: maximum length")
+	// ErrMaxObjLen means that Limits.ObjLen is exceeded.
+	ErrMaxObjLen = errors.New("object: maximum members")
+	// ErrMaxArrLen means that Limits.ArrLen is exceeded.
+	ErrMaxArrLen = errors.New("array: maximum length")
+	// ErrMaxBytes means that Limits.Bytes is exceeded.
+	ErrMaxBytes = errors.New("input: maximum size")
+)
+
+// Limits returns current limits of Decoder.
+func (d *Decoder) Limits() Limits {
+	return d.limits
+}
+
+// SetLimits sets limits of Decoder.
+//
+// Limits are kept on Reset and ResetBytes.
+func (d *Decoder) SetLimits(l Limits) {
+	d.limits = l
+	if d.reader == nil {
+		d.limitInput()
+	}
+}
+
+// limitInput truncates buffer of byte slice decoder to Limits.Bytes.
+func (d *Decoder) limitInput() {
+	d.tail = len(d.buf)
+	if max := d.limits.Bytes; max > 0 && d.tail > max {
+		d.tail = max
+	}
+	if d.head > d.tail {
+		d.head = d.tail
+	}
+}
+
+// endOfInput returns error for end of byte slice decoder input.
+func (d *Decoder) endOfInput(err error) error {
+	if d.limits.Bytes > 0 && d.tail < len(d.buf) {
+		return d.errorAt(ErrMaxBytes, d.limits.Bytes)
+	}
+	return err
+}
+
+// readBuf returns part of buffer that can be read without exceeding
+// Limits.Bytes.
+func (d *Decoder) readBuf(atLeast int) ([]byte, error) {
+	buf := d.buf
+	max := d.limits.Bytes
+	if max <= 0 {
+		return buf, nil
+	}
+	left := max - (d.streamOffset + d.tail)
+	if left <= 0 {
+		// Limit is reached, check that there is actually more input.
+		var b [1]byte
+		if n, err := io.ReadFull(d.reader, b[:]); n == 0 {
+			return nil, err
+		}
+		return nil, d.errorAt(ErrMaxBytes, d.limits.Bytes)
+	}
+	if left < atLeast {
+		return nil, d.errorAt(ErrMaxBytes, d.limits.Bytes)
+	}
+	if left < len(buf) {
+		buf = buf[:left]
+	}
+	return buf, nil
+}
+
+func (d *Decoder) checkStrLen(start, end int) error {
+	if max := d.limits.StrLen; max > 0 && end-start > max {
+		return d.errorAt(ErrMaxStrLen, start)
+	}
+	return nil
+}
+
+func (d *Decoder) checkNumLen(n int) error {
+	if max := d.limits.NumLen; max > 0 && n > max {
+		return d.errorAt(ErrMaxNumLen, d.offset())
+	}
+	return nil
+}
+
+func (d *Decoder) checkObjLen(n int) error {
+	if max := d.limits.ObjLen; max > 0 && n > max {
+		return d.errorAt(ErrMaxObjLen, d.offset())
+	}
+	return nil
+}
+
+func (d *Decoder) checkArrLen(n int) error {
+	if max := d.limits.ArrLen; max > 0 && n > max {
+		return d.errorAt(ErrMaxArrLen, d.offset())
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package jx
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecoder_Limits(t *testing.T) {
+	var (
+		longStr = `"` + strings.Repeat("a", 1000) + `"`
+		longNum = "1" + strings.Repeat("0", 1000)
+		longFlt = "0." + strings.Repeat("0", 998) + "1"
+		nested  = strings.Repeat("[", 10) + strings.Repeat("]", 10)
+	)
+	readStr := func(d *Decoder) error {
+		_, err := d.Str()
+		return err
+	}
+	readNum := func(d *Decoder) error {
+		_, err := d.Num()
+		return err
+	}
+	readFloat := func(d *Decoder) error {
+		_, err := d.Float64()
+		return err
+	}
+	readObj := func(d *Decoder) error {
+		return d.Obj(func(d *Decoder, key string) error {
+			return d.Skip()
+		})
+	}
+	readArr := func(d *Decoder) error {
+		return d.Arr(func(d *Decoder) error {
+			return d.Skip()
+		})
+	}
+	readObjIter := func(d *Decoder) error {
+		iter, err := d.ObjIter()
+		if err != nil {
+			return err
+		}
+		for iter.Next() {
+			if err := d.Skip(); err != nil {
+				return err
+			}
+		}
+		return iter.Err()
+	}
+	readArrIter := func(d *Decoder) error {
+		iter, err := d.ArrIter()
+		if err != nil {
+			return err
+		}
+		for iter.Next() {
+			if err := d.Skip(); err != nil {
+				return err
+			}
+		}
+		return iter.Err()
+	}
+
+	for i, tt := range []struct {
+		input  string
+		limits Limits
+		fn     func(d *Decoder) error
+		err    error
+	}{
+		{nested, Limits{Depth: 10}, (*Decoder).Validate, nil},
+		{nested, Limits{Depth: 9}, (*Decoder).Validate, ErrMaxDepth},
+		{nested, Limits{Depth: 9}, readArr, ErrMaxDepth},
+
+		{longStr, Limits{StrLen: 1000}, readStr, nil},
+		{longStr, Limits{StrLen: 999}, readStr, ErrMaxStrLen},
+		{longStr, Limits{StrLen: 999}, (*Decoder).Validate, ErrMaxStrLen},
+		{`"` + strings.Repeat(`\n`, 500) + `"`, Limits{StrLen: 1000}, readStr, nil},
+		{`"` + strings.Repeat(`\n`, 500) + `"`, Limits{StrLen: 999}, readStr, ErrMaxStrLen},
+		{`{"abc":1}`, Limits{StrLen: 2}, readObj, ErrMaxStrLen},
+		{`{"abc":1}`, Limits{StrLen: 2}, (*Decoder).Validate, ErrMaxStrLen},
+
+		{longNum, Limits{NumLen: 1001}, readNum, nil},
+		{longNum, Limits{NumLen: 1000}, readNum, ErrMaxNumLen},
+		{longNum, Limits{NumLen: 1000}, (*Decoder).Validate, ErrMaxNumLen},
+		{longFlt, Limits{NumLen: 1001}, readFloat, nil},
+		{longFlt, Limits{NumLen: 1000}, readFloat, ErrMaxNumLen},
+		{`[1.5,-10e5]`, Limits{NumLen: 5}, (*Decoder).Validate, nil},
+		{`[1.5,-10e5]`, Limits{NumLen: 4}, (*Decoder).Validate, ErrMaxNumLen},
+
+		{`{"a":1,"b":2,"c":3}`, Limits{ObjLen: 3}, readObj, nil},
+		{`{"a":1,"b":2,"c":3}`, Limits{ObjLen: 2}, readObj, ErrMaxObjLen},
+		{`{"a":1,"b":2,"c":3}`, Limits{ObjLen: 2}, readObjIter, ErrMaxObjLen},
+		{`{"a":1,"b":2,"c":3}`, Limits{ObjLen: 2}, (*Decoder).Validate, ErrMaxObjLen},
+		{`{"a":1}`, Limits{ObjLen: 1}, readObjIter, nil},
+
+		{`[1,2,3]`, Limits{ArrLen: 3}, readArr, nil},
+		{`[1,2,3]`, Limits{ArrLen: 2}, readArr, ErrMaxArrLen},
+		{`[1,2,3]`, Limits{ArrLen: 2}, readArrIter, ErrMaxArrLen},
+		{`[1,2,3]`, Limits{ArrLen: 2}, (*Decoder).Validate, ErrMaxArrLen},
+		{`[1]`, Limits{ArrLen: 1}, readArrIter, nil},
+
+		{longStr, Limits{Bytes: len(longStr)}, (*Decoder).Validate, nil},
+		{longStr, Limits{Bytes: len(longStr) - 1}, (*Decoder).Validate, ErrMaxBytes},
+		{longNum, Limits{Bytes: len(longNum)}, (*Decoder).Validate, nil},
+		{longNum, Limits{Bytes: len(longNum) - 1}, (*Decoder).Validate, ErrMaxBytes},
+		{`true `, Limits{Bytes: 4}, (*Decoder).Validate, ErrMaxBytes},
+		{`true`, Limits{Bytes: 3}, (*Decoder).Validate, ErrMaxBytes},
+	} {
+		tt := tt
+		t.Run(fmt.Sprintf("Test%d", i+1), testBufferReader(tt.input, func(t *testing.T, d *Decoder) {
+			d.SetLimits(tt.limits)
+			require.Equal(t, tt.limits, d.Limits())
+
+			err := tt.fn(d)
+			if tt.err == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, tt.err)
+		}))
+	}
+}
+
+func TestDecoder_SetLimits(t *testing.T) {
+	a := require.New(t)
+	d := DecodeStr(`[1,2]`)
+	d.SetLimits(Limits{Bytes: 2})
+	a.ErrorIs(d.Validate(), ErrMaxBytes)
+
+	// Limits are kept on reset.
+	d.ResetBytes([]byte(`[1,2]`))
+	a.ErrorIs(d.Validate(), ErrMaxBytes)
+	d.Reset(strings.NewReader(`[1,2]`))
+	a.ErrorIs(d.Validate(), ErrMaxBytes)
+
+	d.SetLimits(Limits{})
+	d.ResetBytes([]byte(`[1,2]`))
+	a.NoError(d.Validate())
+}
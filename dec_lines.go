@@ -0,0 +1,193 @@
+package jx
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+
+	"github.com/go-faster/errors"
+)
+
+// LineError is error of single record of LineReader.
+type LineError struct {
+	Line int // line number of record, starting from 1
+	Err  error
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
+
+// LineReader reads newline-delimited json records, also known as
+// NDJSON or JSON Lines.
+//
+// Each record is decoded independently, so malformed record does not
+// prevent reading of next ones:
+//
+//	r := jx.NewLineReader(f)
+//	for r.Next() {
+//		if err := r.Decode(decodeRecord); err != nil {
+//			log.Println(err) // line 3: ...
+//			continue
+//		}
+//	}
+//	if err := r.Err(); err != nil {
+//		return err // I/O error
+//	}
+//
+// Blank lines are skipped.
+type LineReader struct {
+	r    *bufio.Reader
+	d    Decoder
+	buf  []byte
+	line int // line number of current record
+	next int // line number of next line
+	err  error
+}
+
+// NewLineReader creates new LineReader from io.Reader.
+func NewLineReader(r io.Reader) *LineReader {
+	lr := &LineReader{}
+	lr.Reset(r)
+	return lr
+}
+
+// Reset resets LineReader to read from r.
+//
+// Options of Decoder are kept.
+func (r *LineReader) Reset(rd io.Reader) {
+	if r.r == nil {
+		r.r = bufio.NewReader(rd)
+	} else {
+		r.r.Reset(rd)
+	}
+	r.buf = r.buf[:0]
+	r.line = 0
+	r.next = 1
+	r.err = nil
+	r.d.ResetBytes(nil)
+}
+
+// Next reads next record, returning false on end of input or I/O error.
+func (r *LineReader) Next() bool {
+	for r.err == nil {
+		ok, err := r.readLine()
+		if err != nil {
+			r.err = err
+			return false
+		}
+		if !ok {
+			return false
+		}
+		if !isBlank(r.buf) {
+			r.d.ResetBytes(r.buf)
+			return true
+		}
+	}
+	return false
+}
+
+// readLine reads next line to buf, without line terminator.
+func (r *LineReader) readLine() (bool, error) {
+	buf, ok, err := readRecord(r.r, r.buf[:0], '\n', r.d.limits.Bytes)
+	r.buf = buf
+	if ok {
+		r.line = r.next
+		r.next++
+	}
+	return ok, err
+}
+
+// readRecord appends bytes until delim to buf, without delim.
+//
+// Record is truncated to max+1 bytes if max is positive, so decoding of too
+// long record fails with ErrMaxBytes.
+// Returns false if input is ended and nothing is read.
+func readRecord(r *bufio.Reader, buf []byte, delim byte, max int) (_ []byte, ok bool, _ error) {
+	start := len(buf)
+	for {
+		chunk, err := r.ReadSlice(delim)
+		ok = ok || len(chunk) > 0
+		if n := len(buf) - start + len(chunk); max > 0 && n > max+1 {
+			chunk = chunk[:len(chunk)-(n-max-1)]
+		}
+		buf = append(buf, chunk...)
+		switch err {
+		case nil:
+			if n := len(buf); n > start && buf[n-1] == delim {
+				buf = buf[:n-1]
+			}
+			return buf, true, nil
+		case bufio.ErrBufferFull:
+			continue
+		case io.EOF:
+			return buf, ok, nil
+		default:
+			return buf, false, err
+		}
+	}
+}
+
+func isBlank(buf []byte) bool {
+	for _, c := range buf {
+		if spaceSet[c] == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Line returns line number of current record, starting from 1.
+func (r *LineReader) Line() int {
+	return r.line
+}
+
+// Decoder returns Decoder of current record.
+//
+// Options, like limits, set on returned Decoder are kept for next records.
+// Limits.Bytes applies to each record.
+func (r *LineReader) Decoder() *Decoder {
+	return &r.d
+}
+
+// Raw returns current record without line terminator.
+//
+// Do not retain returned value, it references underlying buffer.
+func (r *LineReader) Raw() Raw {
+	return r.buf
+}
+
+// Decode calls f with Decoder of current record, checking that record
+// has no trailing data.
+//
+// Returned error is *LineError.
+func (r *LineReader) Decode(f func(d *Decoder) error) error {
+	r.d.ResetBytes(r.buf)
+	if err := f(&r.d); err != nil {
+		return &LineError{Line: r.line, Err: err}
+	}
+	if err := r.d.Skip(); err != io.EOF {
+		return &LineError{Line: r.line, Err: errors.Wrap(err, "unexpected trialing data")}
+	}
+	return nil
+}
+
+// Validate validates current record.
+//
+// Returned error is *LineError.
+func (r *LineReader) Validate() error {
+	return r.Decode(func(d *Decoder) error {
+		return d.Skip()
+	})
+}
+
+// Err returns I/O error, if any.
+//
+// Errors of records are returned by Decode and Validate.
+func (r *LineReader) Err() error {
+	return r.err
+}
@@ -0,0 +1,117 @@
+package jx
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLineReader(t *testing.T) {
+	const input = "{\"id\":1}\n" +
+		"\n" +
+		"{\"id\":2,}\r\n" +
+		"  {\"id\":3}  \n" +
+		"{\"id\":4} {}\n" +
+		"[1,2\n" +
+		"{\"id\":5}"
+	type record struct {
+		line int
+		id   int
+		err  bool
+	}
+	expect := []record{
+		{line: 1, id: 1},
+		{line: 3, err: true},
+		{line: 4, id: 3},
+		{line: 5, err: true},
+		{line: 6, err: true},
+		{line: 7, id: 5},
+	}
+	for _, r := range []struct {
+		name string
+		r    func() io.Reader
+	}{
+		{"Reader", func() io.Reader { return strings.NewReader(input) }},
+		{"OneByteReader", func() io.Reader { return iotest.OneByteReader(strings.NewReader(input)) }},
+		{"DataErrReader", func() io.Reader { return iotest.DataErrReader(strings.NewReader(input)) }},
+	} {
+		r := r
+		t.Run(r.name, func(t *testing.T) {
+			a := require.New(t)
+			var got []record
+			lr := NewLineReader(r.r())
+			for lr.Next() {
+				rec := record{line: lr.Line()}
+				err := lr.Decode(func(d *Decoder) error {
+					return d.Obj(func(d *Decoder, key string) error {
+						v, err := d.Int()
+						rec.id = v
+						return err
+					})
+				})
+				if err != nil {
+					var lineErr *LineError
+					a.ErrorAs(err, &lineErr)
+					a.Equal(rec.line, lineErr.Line)
+					rec = record{line: rec.line, err: true}
+				}
+				got = append(got, rec)
+			}
+			a.NoError(lr.Err())
+			a.Equal(expect, got)
+		})
+	}
+}
+
+func TestLineReader_Raw(t *testing.T) {
+	a := require.New(t)
+	lr := NewLineReader(strings.NewReader("1\r\n\"a\"\n\n  \n[]\n"))
+	var raws []string
+	for lr.Next() {
+		raws = append(raws, lr.Raw().String())
+		a.NoError(lr.Validate())
+	}
+	a.NoError(lr.Err())
+	a.Equal([]string{"1\r", `"a"`, "[]"}, raws)
+	a.Equal(5, lr.Line())
+
+	lr.Reset(strings.NewReader("true"))
+	a.True(lr.Next())
+	a.Equal(1, lr.Line())
+	a.Equal(Bool, lr.Decoder().Next())
+	a.False(lr.Next())
+}
+
+func TestLineReader_Limits(t *testing.T) {
+	a := require.New(t)
+	long := `"` + strings.Repeat("a", bufio.MaxScanTokenSize) + `"`
+	lr := NewLineReader(strings.NewReader("1\n" + long + "\n\"abc\"\n"))
+	lr.Decoder().SetLimits(Limits{Bytes: 5})
+
+	a.True(lr.Next())
+	a.NoError(lr.Validate())
+
+	a.True(lr.Next())
+	a.Equal(2, lr.Line())
+	a.Len(lr.Raw(), 6)
+	a.ErrorIs(lr.Validate(), ErrMaxBytes)
+
+	a.True(lr.Next())
+	a.Equal(3, lr.Line())
+	a.NoError(lr.Validate())
+	a.False(lr.Next())
+	a.NoError(lr.Err())
+}
+
+func TestLineReader_Err(t *testing.T) {
+	a := require.New(t)
+	lr := NewLineReader(io.MultiReader(strings.NewReader("1\n"), errReader{}))
+	a.True(lr.Next())
+	a.NoError(lr.Validate())
+	a.False(lr.Next())
+	a.ErrorIs(lr.Err(), errReader{}.Err())
+}
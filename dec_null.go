@@ -11,13 +11,16 @@ func (d *Decoder) Null() error {
 		offset = d.offset()
 		buf    [4]byte
 	)
+	if c := d.buf[d.head]; types[c] != Null {
+		return d.typeError(Null, c, offset)
+	}
 	if err := d.readExact4(&buf); err != nil {
 		return err
 	}
 
 	if string(buf[:]) != "null" {
 		const encodedNull = 'n' | 'u'<<8 | 'l'<<16 | 'l'<<24
-		return findInvalidToken4(buf, encodedNull, offset)
+		return d.findInvalidToken4(buf, encodedNull, offset)
 	}
 	return nil
 }
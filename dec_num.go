@@ -30,22 +30,22 @@ func (d *Decoder) num(v Num, forceAppend bool) (Num, error) {
 
 		// Validate number.
 		{
-			d := Decoder{}
-			d.ResetBytes(str.buf)
+			sd := Decoder{}
+			d.subDecoder(&sd, str.buf, offset+1)
 
-			c, err := d.next()
+			c, err := sd.next()
 			if err != nil {
 				return Num{}, err
 			}
 			switch c {
 			case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9', '-':
-				d.unread()
+				sd.unread()
 
-				if err := d.skipNumber(); err != nil {
+				if err := sd.skipNumber(); err != nil {
 					return Num{}, errors.Wrap(err, "skip number")
 				}
 			default:
-				return nil, badToken(c, offset)
+				return nil, d.badToken(c, offset)
 			}
 		}
 
@@ -58,6 +58,20 @@ func (d *Decoder) num(v Num, forceAppend bool) (Num, error) {
 		}
 		return d.buf[start:d.head], nil
 	case Number: // float or integer
+		if d.json5 {
+			offset := d.offset()
+			num, _, err := d.json5Num()
+			if err != nil {
+				return v, err
+			}
+			if num == nil {
+				return v, d.errorAt(errors.New("NaN and Infinity are not representable"), offset)
+			}
+			if forceAppend {
+				return append(v, num...), nil
+			}
+			return num, nil
+		}
 		if forceAppend {
 			raw, err := d.RawAppend(Raw(v))
 			if err != nil {
@@ -72,6 +86,10 @@ func (d *Decoder) num(v Num, forceAppend bool) (Num, error) {
 		}
 		return Num(raw), nil
 	default:
-		return v, errors.Errorf("unexpected %s", d.Next())
+		c, err := d.more()
+		if err != nil {
+			return v, err
+		}
+		return v, d.typeError(Number, c, d.offset()-1)
 	}
 }
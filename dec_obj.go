@@ -8,8 +8,8 @@ import (
 //
 // The key value is valid only until f is not returned.
 func (d *Decoder) ObjBytes(f func(d *Decoder, key []byte) error) error {
-	if err := d.consume('{'); err != nil {
-		return errors.Wrap(err, `"{" expected`)
+	if err := d.consumeType('{', Object); err != nil {
+		return err
 	}
 	if f == nil {
 		return d.skipObj()
@@ -17,6 +17,7 @@ func (d *Decoder) ObjBytes(f func(d *Decoder, key []byte) error) error {
 	if err := d.incDepth(); err != nil {
 		return err
 	}
+	d.objStart()
 	c, err := d.more()
 	if err != nil {
 		return errors.Wrap(err, `'"' or "}" expected`)
@@ -32,7 +33,7 @@ func (d *Decoder) ObjBytes(f func(d *Decoder, key []byte) error) error {
 	// See https://github.com/go-faster/jx/pull/62.
 	isBuffer := d.reader == nil
 
-	k, err := d.str(value{raw: isBuffer})
+	k, err := d.objKey(value{raw: isBuffer})
 	if err != nil {
 		return errors.Wrap(err, "field name")
 	}
@@ -40,20 +41,30 @@ func (d *Decoder) ObjBytes(f func(d *Decoder, key []byte) error) error {
 		return errors.Wrap(err, `":" expected`)
 	}
 	// Skip whitespace.
-	if _, err = d.more(); err != nil {
+	if c, err = d.more(); err != nil {
 		return err
 	}
 	d.unread()
 	if err := f(d, k.buf); err != nil {
-		return errors.Wrap(err, "callback")
+		return d.fieldError(err, k.buf, types[c])
 	}
 
 	c, err = d.more()
 	if err != nil {
 		return errors.Wrap(err, `"," or "}" expected`)
 	}
-	for c == ',' {
-		k, err := d.str(value{raw: isBuffer})
+	for n := 2; c == ','; n++ {
+		end, err := d.trailingComma('}')
+		if err != nil {
+			return err
+		}
+		if end {
+			return d.decDepth()
+		}
+		if err := d.checkObjLen(n); err != nil {
+			return err
+		}
+		k, err := d.objKey(value{raw: isBuffer})
 		if err != nil {
 			return errors.Wrap(err, "field name")
 		}
@@ -61,19 +72,19 @@ func (d *Decoder) ObjBytes(f func(d *Decoder, key []byte) error) error {
 			return errors.Wrap(err, `":" expected`)
 		}
 		// Check that value exists.
-		if _, err = d.more(); err != nil {
+		if c, err = d.more(); err != nil {
 			return err
 		}
 		d.unread()
 		if err := f(d, k.buf); err != nil {
-			return errors.Wrap(err, "callback")
+			return d.fieldError(err, k.buf, types[c])
 		}
 		if c, err = d.more(); err != nil {
 			return err
 		}
 	}
 	if c != '}' {
-		err := badToken(c, d.offset()-1)
+		err := d.badToken(c, d.offset()-1)
 		return errors.Wrap(err, `"}" expected`)
 	}
 	return d.decDepth()
@@ -10,16 +10,18 @@ type ObjIter struct {
 	isBuffer bool
 	closed   bool
 	comma    bool
+	count    int
 }
 
 // ObjIter creates new object iterator.
 func (d *Decoder) ObjIter() (ObjIter, error) {
-	if err := d.consume('{'); err != nil {
-		return ObjIter{}, errors.Wrap(err, `"{" expected`)
+	if err := d.consumeType('{', Object); err != nil {
+		return ObjIter{}, err
 	}
 	if err := d.incDepth(); err != nil {
 		return ObjIter{}, err
 	}
+	d.objStart()
 	if _, err := d.more(); err != nil {
 		return ObjIter{}, err
 	}
@@ -53,27 +55,42 @@ func (i *ObjIter) Next() bool {
 	}
 	if i.comma {
 		if c != ',' {
-			err := badToken(c, dec.offset()-1)
+			err := dec.badToken(c, dec.offset()-1)
 			i.err = errors.Wrap(err, `"," expected`)
 			return false
 		}
+		end, err := dec.trailingComma('}')
+		if err != nil {
+			i.err = err
+			return false
+		}
+		if end {
+			i.closed = true
+			i.err = dec.decDepth()
+			return false
+		}
 	} else {
 		dec.unread()
 	}
 
-	k, err := dec.str(value{raw: i.isBuffer})
+	i.count++
+	if err := dec.checkObjLen(i.count); err != nil {
+		i.err = err
+		return false
+	}
+
+	k, err := dec.objKey(value{raw: i.isBuffer})
 	if err != nil {
 		i.err = errors.Wrap(err, "field name")
 		return false
 	}
 	if err := dec.consume(':'); err != nil {
-		i.err = errors.Wrap(err, `":" expected`)
+		i.err = dec.fieldError(errors.Wrap(err, `":" expected`), k.buf, Invalid)
 		return false
 	}
 	// Skip whitespace.
 	if _, err = dec.more(); err != nil {
-		err := badToken(c, dec.offset()-1)
-		i.err = errors.Wrap(err, `"," or "}" expected`)
+		i.err = dec.fieldError(errors.Wrap(err, "value expected"), k.buf, Invalid)
 		return false
 	}
 	dec.unread()
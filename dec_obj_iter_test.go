@@ -54,7 +54,7 @@ func TestDecoder_ObjIter(t *testing.T) {
 		d := DecodeStr(`{`)
 		// Emulate depth
 		d.depth = maxDepth
-		require.ErrorIs(t, testIter(d), errMaxDepth)
+		require.ErrorIs(t, testIter(d), ErrMaxDepth)
 	})
 	t.Run("Empty", func(t *testing.T) {
 		d := DecodeStr(``)
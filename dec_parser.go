@@ -0,0 +1,267 @@
+package jx
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/go-faster/errors"
+)
+
+var (
+	// ErrNeedMore means that Parser needs more input to continue.
+	ErrNeedMore = errors.New("need more input")
+
+	errFeedAfterEnd = errors.New("feed after end of input")
+)
+
+// Parser is push-based incremental json parser.
+//
+// Input is passed to Parser by Feed as it arrives, and tokens or complete
+// top-level values are read by Next and Value, which return ErrNeedMore
+// instead of blocking if token or value is not complete yet:
+//
+//	for chunk := range chunks {
+//		if err := p.Feed(chunk); err != nil {
+//			return err
+//		}
+//		for {
+//			tok, err := p.Next()
+//			if err == jx.ErrNeedMore {
+//				break
+//			}
+//			if err != nil {
+//				return err
+//			}
+//			inspect(tok)
+//		}
+//	}
+//	p.End()
+//
+// Multiple top-level values are allowed. Zero value is ready to use.
+type Parser struct {
+	d     Decoder
+	end   bool
+	value bool // value is being read by Value
+	start int  // offset of value
+
+	// Scan state of next token, kept between calls of Next, so bytes of
+	// incomplete token are examined once.
+	scan  int // offset of first byte that is not scanned
+	state scanState
+}
+
+// scanState is state of Parser scan of next token.
+type scanState byte
+
+const (
+	scanSpace  scanState = iota // whitespace or separator before token
+	scanStr                     // string
+	scanEscape                  // escaped character of string
+	scanWord                    // number or literal
+	scanColon                   // whitespace after key
+)
+
+// NewParser creates new Parser.
+func NewParser() *Parser {
+	return &Parser{}
+}
+
+// Reset resets Parser state and input.
+//
+// Limits are kept.
+func (p *Parser) Reset() {
+	p.d.ResetBytes(p.d.buf[:0])
+	p.end = false
+	p.value = false
+	p.start = 0
+	p.resetScan()
+}
+
+// SetLimits sets limits of parsed input.
+//
+// Limits.Bytes applies to total size of input.
+func (p *Parser) SetLimits(l Limits) {
+	p.d.limits = l
+}
+
+// Feed appends chunk to input.
+//
+// Values of previously returned tokens are invalidated.
+func (p *Parser) Feed(chunk []byte) error {
+	if p.end {
+		return errFeedAfterEnd
+	}
+	d := &p.d
+	keep := d.head
+	if p.value && p.start-d.streamOffset < keep {
+		keep = p.start - d.streamOffset
+	}
+	p.discard(keep)
+
+	if max := d.limits.Bytes; max > 0 && d.streamOffset+len(d.buf)+len(chunk) > max {
+		return ErrMaxBytes
+	}
+	d.buf = append(d.buf, chunk...)
+	d.tail = len(d.buf)
+	return nil
+}
+
+// discard removes n consumed bytes from the beginning of buffer.
+func (p *Parser) discard(n int) {
+	d := &p.d
+	if n <= 0 {
+		return
+	}
+	d.lineBase = d.discardLines(n)
+	d.lineLast = linePos{}
+	d.streamOffset += n
+	d.buf = append(d.buf[:0], d.buf[n:d.tail]...)
+	d.head -= n
+	d.tail = len(d.buf)
+}
+
+// End marks end of input.
+//
+// After End, Next and Value return io.EOF if all input is read or error
+// if last token or value is incomplete.
+func (p *Parser) End() {
+	p.end = true
+}
+
+// Next returns next token.
+//
+// Returns ErrNeedMore if next token is not complete. Number at the end of
+// input is considered incomplete until End is called, because it can be
+// continued by next chunk.
+//
+// Token value is valid until next call of Next or Feed.
+func (p *Parser) Next() (Token, error) {
+	d := &p.d
+	if !p.end && !p.scanned() {
+		return Token{}, ErrNeedMore
+	}
+	var (
+		head  = d.head
+		depth = d.depth
+		n     = len(d.tokens)
+		top   tokenFrame
+	)
+	if n > 0 {
+		top = d.tokens[n-1]
+	}
+	tok, err := d.Token()
+	if p.end {
+		return tok, err
+	}
+	switch {
+	case err == nil && (tok.Kind != TokenNumber || d.head < d.tail):
+		p.resetScan()
+		return tok, nil
+	case err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF):
+		return tok, err
+	}
+	// Token is incomplete, rollback.
+	d.head = head
+	d.depth = depth
+	d.tokens = d.tokens[:n]
+	if n > 0 {
+		d.tokens[n-1] = top
+	}
+	p.resetScan()
+	return Token{}, ErrNeedMore
+}
+
+// resetScan starts scan of next token at current offset.
+func (p *Parser) resetScan() {
+	p.scan = p.d.offset()
+	p.state = scanSpace
+}
+
+// scanned reports whether next token is complete, continuing scan from
+// previous call.
+//
+// Scan only finds end of token, token itself is checked by Token.
+func (p *Parser) scanned() bool {
+	d := &p.d
+	if p.scan < d.offset() {
+		p.resetScan()
+	}
+	buf := d.buf[:d.tail]
+	for i := p.scan - d.streamOffset; i < len(buf); i++ {
+		c := buf[i]
+		switch p.state {
+		case scanSpace:
+			switch {
+			case spaceSet[c] != 0 || c == ',':
+			case c == '"':
+				p.state = scanStr
+			case json5NumSet[c]:
+				// Digits, signs and letters of numbers or literals.
+				p.state = scanWord
+			default:
+				return true
+			}
+		case scanStr:
+			switch c {
+			case '\\':
+				p.state = scanEscape
+			case '"':
+				if !p.key() {
+					return true
+				}
+				// Key is returned with colon.
+				p.state = scanColon
+			}
+		case scanEscape:
+			p.state = scanStr
+		case scanWord:
+			// Word is complete only if followed by other byte, because it
+			// can be continued by next chunk.
+			if !json5NumSet[c] {
+				return true
+			}
+		case scanColon:
+			if spaceSet[c] == 0 {
+				return true
+			}
+		}
+	}
+	p.scan = d.streamOffset + len(buf)
+	return false
+}
+
+// key reports whether scanned token is object key.
+func (p *Parser) key() bool {
+	tokens := p.d.tokens
+	return len(tokens) > 0 && tokens[len(tokens)-1].obj && tokens[len(tokens)-1].state != tokenValue
+}
+
+// Value returns next complete top-level value.
+//
+// Returns ErrNeedMore if value is not complete. Should not be called
+// inside of object or array, started by Next.
+//
+// Returned value is valid until next call of Feed.
+func (p *Parser) Value() (Raw, error) {
+	d := &p.d
+	if len(d.tokens) > 0 && !p.value {
+		return nil, errors.New("value: inside of object or array")
+	}
+	if !p.value {
+		p.value = true
+		p.start = d.offset()
+	}
+	for {
+		if _, err := p.Next(); err != nil {
+			if err != ErrNeedMore {
+				p.value = false
+			}
+			return nil, err
+		}
+		if len(d.tokens) == 0 {
+			p.value = false
+			raw := d.buf[p.start-d.streamOffset : d.head]
+			return bytes.TrimLeft(raw, " \t\r\n"), nil
+		}
+	}
+}
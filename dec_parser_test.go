@@ -0,0 +1,218 @@
+package jx
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// parseChunks feeds input to Parser by chunks of n bytes, returning tokens.
+func parseChunks(p *Parser, input string, n int) (string, error) {
+	var (
+		s   strings.Builder
+		tok Token
+		err error
+	)
+	write := func() {
+		if s.Len() > 0 {
+			s.WriteByte(' ')
+		}
+		fmt.Fprintf(&s, "%d:%s", tok.Depth, tok)
+	}
+	for len(input) > 0 {
+		chunk := input
+		if len(chunk) > n {
+			chunk = chunk[:n]
+		}
+		input = input[len(chunk):]
+		if err := p.Feed([]byte(chunk)); err != nil {
+			return s.String(), err
+		}
+		for {
+			tok, err = p.Next()
+			if err == ErrNeedMore {
+				break
+			}
+			if err != nil {
+				return s.String(), err
+			}
+			write()
+		}
+	}
+	p.End()
+	for {
+		tok, err = p.Next()
+		if err == io.EOF {
+			return s.String(), nil
+		}
+		if err != nil {
+			return s.String(), err
+		}
+		write()
+	}
+}
+
+func TestParser(t *testing.T) {
+	for i, tt := range []struct {
+		input  string
+		expect string
+	}{
+		{`123`, `0:123`},
+		{`-1.5e+10 true null "str"`, `0:-1.5e+10 0:true 0:null 0:"str"`},
+		{`{"keyA": ["a\"b", 10, false]}`, `0:{ 1:"keyA" 1:[ 2:"a\"b" 2:10 2:false 1:] 0:}`},
+		{` [ [ ] , { } ] 1 `, `0:[ 1:[ 1:] 1:{ 1:} 0:] 0:1`},
+	} {
+		tt := tt
+		t.Run(fmt.Sprintf("Test%d", i+1), func(t *testing.T) {
+			for n := 1; n <= len(tt.input); n++ {
+				var p Parser
+				got, err := parseChunks(&p, tt.input, n)
+				require.NoError(t, err, "chunk %d", n)
+				require.Equal(t, tt.expect, got, "chunk %d", n)
+			}
+		})
+	}
+}
+
+func TestParser_Error(t *testing.T) {
+	for _, input := range []string{
+		`{"a" 1}`,
+		`[1,]`,
+		`[1 2]`,
+		`tru`,
+		`[1`,
+		`"abc`,
+		`{"a":1}}`,
+	} {
+		input := input
+		t.Run(input, func(t *testing.T) {
+			for n := 1; n <= len(input); n++ {
+				_, err := parseChunks(NewParser(), input, n)
+				require.Error(t, err, "chunk %d", n)
+			}
+		})
+	}
+	t.Run("Offset", func(t *testing.T) {
+		p := NewParser()
+		_, err := parseChunks(p, "[1,\n 2,\n x]", 2)
+		var syntaxErr *SyntaxError
+		require.ErrorAs(t, err, &syntaxErr)
+		require.Equal(t, 9, syntaxErr.Offset)
+		require.Equal(t, 3, syntaxErr.Line)
+		require.Equal(t, 2, syntaxErr.Column)
+	})
+	t.Run("FeedAfterEnd", func(t *testing.T) {
+		p := NewParser()
+		p.End()
+		require.Error(t, p.Feed([]byte(`1`)))
+		p.Reset()
+		require.NoError(t, p.Feed([]byte(`1 `)))
+		tok, err := p.Next()
+		require.NoError(t, err)
+		require.Equal(t, "1", tok.String())
+	})
+	t.Run("Limits", func(t *testing.T) {
+		p := NewParser()
+		p.SetLimits(Limits{Bytes: 4, Depth: 2})
+		require.NoError(t, p.Feed([]byte(`[[`)))
+		require.NoError(t, p.Feed([]byte(`[`)))
+		_, err := p.Next()
+		require.NoError(t, err)
+		_, err = p.Next()
+		require.NoError(t, err)
+		_, err = p.Next()
+		require.ErrorIs(t, err, ErrMaxDepth)
+		require.ErrorIs(t, p.Feed([]byte(`]]`)), ErrMaxBytes)
+	})
+}
+
+func TestParser_Value(t *testing.T) {
+	const input = `{"a": [1, 2]} "str"  10 [] `
+	for n := 1; n <= len(input); n++ {
+		var (
+			p    Parser
+			got  []string
+			rest = input
+		)
+		for len(rest) > 0 {
+			chunk := rest
+			if len(chunk) > n {
+				chunk = chunk[:n]
+			}
+			rest = rest[len(chunk):]
+			require.NoError(t, p.Feed([]byte(chunk)))
+			for {
+				v, err := p.Value()
+				if err == ErrNeedMore {
+					break
+				}
+				require.NoError(t, err)
+				got = append(got, v.String())
+			}
+		}
+		p.End()
+		for {
+			v, err := p.Value()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+			got = append(got, v.String())
+		}
+		require.Equal(t, []string{`{"a": [1, 2]}`, `"str"`, `10`, `[]`}, got, "chunk %d", n)
+	}
+
+	p := NewParser()
+	require.NoError(t, p.Feed([]byte(`[1`)))
+	_, err := p.Next()
+	require.NoError(t, err)
+	_, err = p.Value()
+	require.Error(t, err)
+}
+
+func TestParser_LongToken(t *testing.T) {
+	const size = 4 << 20
+	for _, tt := range []struct {
+		name  string
+		input string
+		kind  TokenKind
+	}{
+		{"String", `{"key":"` + strings.Repeat(`a\"`, size/3) + `"}`, TokenString},
+		{"Key", `{"` + strings.Repeat(`a`, size) + `"   :1}`, TokenKey},
+		{"Number", `[` + strings.Repeat(`1`, size) + `]`, TokenNumber},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			a := require.New(t)
+			var (
+				p     Parser
+				input = []byte(tt.input)
+				found bool
+			)
+			for len(input) > 0 {
+				chunk := input
+				if len(chunk) > 1024 {
+					chunk = chunk[:1024]
+				}
+				input = input[len(chunk):]
+				a.NoError(p.Feed(chunk))
+				for {
+					tok, err := p.Next()
+					if err == ErrNeedMore {
+						// Incomplete token is scanned once.
+						a.Equal(p.d.streamOffset+p.d.tail, p.scan)
+						break
+					}
+					a.NoError(err)
+					if tok.Kind == tt.kind {
+						found = true
+					}
+				}
+			}
+			a.True(found)
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package jx
+
+import (
+	"strings"
+
+	"github.com/go-faster/errors"
+)
+
+// ErrNotFound means that referenced value does not exist.
+var ErrNotFound = errors.New("not found")
+
+// Seek positions Decoder at value referenced by JSON Pointer, as
+// defined in RFC 6901.
+//
+// Everything before referenced value is skipped, Decoder is left inside
+// enclosing containers, so the next call should read the value itself,
+// like Raw, Str or Obj. Empty pointer references the whole document.
+//
+// Returns ErrNotFound if referenced value does not exist.
+func (d *Decoder) Seek(ptr string) error {
+	if ptr == "" {
+		return nil
+	}
+	if ptr[0] != '/' {
+		return errors.Errorf("invalid pointer %q: must start with %q", ptr, "/")
+	}
+	ptr = ptr[1:]
+	for {
+		tok, rest, more := cutPointer(ptr)
+		if err := validatePointerToken(tok); err != nil {
+			return errors.Wrapf(err, "invalid pointer token %q", tok)
+		}
+		switch tt := d.Next(); tt {
+		case Object:
+			if err := d.seekField(tok); err != nil {
+				return err
+			}
+		case Array:
+			if err := d.seekElem(tok); err != nil {
+				return err
+			}
+		default:
+			return errors.Wrapf(ErrNotFound, "%s has no %q", tt, tok)
+		}
+		if !more {
+			return nil
+		}
+		ptr = rest
+	}
+}
+
+func (d *Decoder) seekField(tok string) error {
+	iter, err := d.ObjIter()
+	if err != nil {
+		return err
+	}
+	for iter.Next() {
+		if pointerTokenEqual(tok, iter.Key()) {
+			return nil
+		}
+		if err := d.Skip(); err != nil {
+			return err
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+	return errors.Wrapf(ErrNotFound, "field %q", unescapePointer(tok))
+}
+
+func (d *Decoder) seekElem(tok string) error {
+	idx, err := pointerIndex(tok)
+	if err != nil {
+		return err
+	}
+	iter, err := d.ArrIter()
+	if err != nil {
+		return err
+	}
+	for i := 0; iter.Next(); i++ {
+		if i == idx {
+			return nil
+		}
+		if err := d.Skip(); err != nil {
+			return err
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+	return errors.Wrapf(ErrNotFound, "index %s", tok)
+}
+
+// Pointer returns value referenced by JSON Pointer, as defined in RFC 6901.
+//
+// Returned value references r.
+func (r Raw) Pointer(ptr string) (Raw, error) {
+	d := DecodeBytes(r)
+	if err := d.Seek(ptr); err != nil {
+		return nil, err
+	}
+	return d.Raw()
+}
+
+// cutPointer cuts first reference token from JSON Pointer without
+// leading slash.
+func cutPointer(ptr string) (tok, rest string, more bool) {
+	idx := strings.IndexByte(ptr, '/')
+	if idx < 0 {
+		return ptr, "", false
+	}
+	return ptr[:idx], ptr[idx+1:], true
+}
+
+func validatePointerToken(tok string) error {
+	for i := 0; i < len(tok); i++ {
+		if tok[i] != '~' {
+			continue
+		}
+		if i+1 >= len(tok) || (tok[i+1] != '0' && tok[i+1] != '1') {
+			return errors.Errorf("bad escape at %d", i)
+		}
+	}
+	return nil
+}
+
+// pointerTokenEqual reports whether escaped reference token equals to key.
+func pointerTokenEqual(tok string, key []byte) bool {
+	j := 0
+	for i := 0; i < len(tok); i++ {
+		c := tok[i]
+		if c == '~' {
+			i++
+			if tok[i] == '1' {
+				c = '/'
+			}
+		}
+		if j >= len(key) || key[j] != c {
+			return false
+		}
+		j++
+	}
+	return j == len(key)
+}
+
+func unescapePointer(tok string) string {
+	if strings.IndexByte(tok, '~') < 0 {
+		return tok
+	}
+	return strings.NewReplacer("~1", "/", "~0", "~").Replace(tok)
+}
+
+func pointerIndex(tok string) (int, error) {
+	if tok == "-" {
+		return 0, errors.Wrap(ErrNotFound, `index "-"`)
+	}
+	if tok == "" || (tok[0] == '0' && len(tok) > 1) {
+		return 0, errors.Errorf("invalid array index %q", tok)
+	}
+	const maxInt = int(^uint(0) >> 1)
+	idx := 0
+	for i := 0; i < len(tok); i++ {
+		c := tok[i]
+		if c < '0' || c > '9' {
+			return 0, errors.Errorf("invalid array index %q", tok)
+		}
+		v := int(c - '0')
+		if idx > (maxInt-v)/10 {
+			return 0, errors.Wrapf(ErrNotFound, "index %s", tok)
+		}
+		idx = idx*10 + v
+	}
+	return idx, nil
+}
+
+func escapePointer(key string) string {
+	if strings.IndexAny(key, "~/") < 0 {
+		return key
+	}
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+}
@@ -0,0 +1,116 @@
+package jx
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// rfc6901Doc is example document from RFC 6901, section 5.
+const rfc6901Doc = `{
+	"foo": ["bar", "baz"],
+	"": 0,
+	"a/b": 1,
+	"c%d": 2,
+	"e^f": 3,
+	"g|h": 4,
+	"i\\j": 5,
+	"k\"l": 6,
+	" ": 7,
+	"m~n": 8,
+	"escaped": {"nested": [null, {"x": true}]}
+}`
+
+func TestDecoder_Seek(t *testing.T) {
+	for i, tt := range []struct {
+		ptr    string
+		expect string
+	}{
+		{"", rfc6901Doc},
+		{"/foo", `["bar", "baz"]`},
+		{"/foo/0", `"bar"`},
+		{"/foo/1", `"baz"`},
+		{"/", `0`},
+		{"/a~1b", `1`},
+		{"/c%d", `2`},
+		{"/e^f", `3`},
+		{"/g|h", `4`},
+		{"/i\\j", `5`},
+		{"/k\"l", `6`},
+		{"/ ", `7`},
+		{"/m~0n", `8`},
+		{"/escaped/nested/1/x", `true`},
+		{"/escaped/nested/0", `null`},
+	} {
+		tt := tt
+		t.Run(fmt.Sprintf("Test%d", i+1), testBufferReader(rfc6901Doc, func(t *testing.T, d *Decoder) {
+			a := require.New(t)
+			a.NoError(d.Seek(tt.ptr))
+			raw, err := d.Raw()
+			a.NoError(err)
+			a.Equal(tt.expect, raw.String())
+
+			v, err := Raw(rfc6901Doc).Pointer(tt.ptr)
+			a.NoError(err)
+			a.Equal(tt.expect, v.String())
+		}))
+	}
+}
+
+func TestDecoder_SeekError(t *testing.T) {
+	t.Run("NotFound", func(t *testing.T) {
+		for _, ptr := range []string{
+			"/bar",
+			"/foo/2",
+			"/foo/-",
+			"/foo/0/bar",
+			"/foo/99999999999999999999999",
+			"/escaped/nested/1/y",
+			"/m~1n",
+		} {
+			ptr := ptr
+			t.Run(ptr, testBufferReader(rfc6901Doc, func(t *testing.T, d *Decoder) {
+				require.ErrorIs(t, d.Seek(ptr), ErrNotFound)
+			}))
+		}
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		for _, ptr := range []string{
+			"foo",
+			"/m~2n",
+			"/m~",
+			"/foo/01",
+			"/foo/-1",
+			"/foo/",
+			"/foo/1a",
+		} {
+			ptr := ptr
+			t.Run(ptr, func(t *testing.T) {
+				err := DecodeStr(rfc6901Doc).Seek(ptr)
+				require.Error(t, err)
+				require.NotErrorIs(t, err, ErrNotFound)
+			})
+		}
+	})
+	t.Run("BadInput", func(t *testing.T) {
+		for _, input := range []string{
+			`{"foo":`,
+			`{"foo" 1}`,
+			`{"bar":[1,}`,
+			`[1,`,
+			`[1 2]`,
+		} {
+			input := input
+			t.Run(input, testBufferReader(input, func(t *testing.T, d *Decoder) {
+				err := d.Seek("/foo/1")
+				require.Error(t, err)
+				require.NotErrorIs(t, err, ErrNotFound)
+			}))
+		}
+	})
+	t.Run("Raw", func(t *testing.T) {
+		_, err := Raw(`{"foo":1}`).Pointer("/bar")
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+}
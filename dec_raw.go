@@ -1,6 +1,7 @@
 package jx
 
 import (
+	"bytes"
 	"io"
 
 	"github.com/go-faster/errors"
@@ -74,8 +75,232 @@ type Raw []byte
 
 // Type of Raw json value.
 func (r Raw) Type() Type {
-	d := Decoder{buf: r, tail: len(r)}
+	d := r.decoder()
 	return d.Next()
 }
 
+func (r Raw) decoder() Decoder {
+	return Decoder{buf: r, tail: len(r)}
+}
+
+// Get returns value of object field.
+//
+// If object has duplicate fields, first one is returned. Returns
+// ErrNotFound if field does not exist or value is not object.
+//
+// Returned value references r.
+func (r Raw) Get(key string) (Raw, error) {
+	d := GetDecoder()
+	defer PutDecoder(d)
+	d.ResetBytes(r)
+	if t := d.Next(); t != Object {
+		return nil, errors.Wrapf(ErrNotFound, "%s has no %q", t, key)
+	}
+	iter, err := d.ObjIter()
+	if err != nil {
+		return nil, err
+	}
+	for iter.Next() {
+		if string(iter.Key()) == key {
+			return d.Raw()
+		}
+		if err := d.Skip(); err != nil {
+			return nil, err
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+	return nil, errors.Wrapf(ErrNotFound, "field %q", key)
+}
+
+// Index returns array element by index.
+//
+// Returns ErrNotFound if index is out of range or value is not array.
+//
+// Returned value references r.
+func (r Raw) Index(idx int) (Raw, error) {
+	d := GetDecoder()
+	defer PutDecoder(d)
+	d.ResetBytes(r)
+	if t := d.Next(); t != Array {
+		return nil, errors.Wrapf(ErrNotFound, "%s has no index %d", t, idx)
+	}
+	if idx < 0 {
+		return nil, errors.Wrapf(ErrNotFound, "index %d", idx)
+	}
+	iter, err := d.ArrIter()
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; iter.Next(); i++ {
+		if i == idx {
+			return d.Raw()
+		}
+		if err := d.Skip(); err != nil {
+			return nil, err
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+	return nil, errors.Wrapf(ErrNotFound, "index %d", idx)
+}
+
+// Len returns count of object members or array elements.
+func (r Raw) Len() (int, error) {
+	d := GetDecoder()
+	defer PutDecoder(d)
+	d.ResetBytes(r)
+	n := 0
+	count := func(d *Decoder) error {
+		n++
+		return d.Skip()
+	}
+	switch t := d.Next(); t {
+	case Object:
+		if err := d.ObjBytes(func(d *Decoder, _ []byte) error {
+			return count(d)
+		}); err != nil {
+			return 0, err
+		}
+	case Array:
+		if err := d.Arr(count); err != nil {
+			return 0, err
+		}
+	default:
+		return 0, errors.Errorf("unexpected type %s", t)
+	}
+	return n, nil
+}
+
+// Obj calls f for every member of object.
+//
+// Key is valid only until f is returned. Value references r.
+func (r Raw) Obj(f func(key []byte, v Raw) error) error {
+	d := GetDecoder()
+	defer PutDecoder(d)
+	d.ResetBytes(r)
+	return d.ObjBytes(func(d *Decoder, key []byte) error {
+		v, err := d.Raw()
+		if err != nil {
+			return err
+		}
+		return f(key, v)
+	})
+}
+
+// Arr calls f for every element of array.
+//
+// Value references r.
+func (r Raw) Arr(f func(v Raw) error) error {
+	d := GetDecoder()
+	defer PutDecoder(d)
+	d.ResetBytes(r)
+	return d.Arr(func(d *Decoder) error {
+		v, err := d.Raw()
+		if err != nil {
+			return err
+		}
+		return f(v)
+	})
+}
+
+// Str decodes string value.
+func (r Raw) Str() (string, error) {
+	d := r.decoder()
+	return d.Str()
+}
+
+// Num decodes number value.
+//
+// Returned value references r.
+func (r Raw) Num() (Num, error) {
+	d := r.decoder()
+	return d.Num()
+}
+
+// Int64 decodes integer value.
+func (r Raw) Int64() (int64, error) {
+	d := r.decoder()
+	return d.Int64()
+}
+
+// Float64 decodes number value.
+func (r Raw) Float64() (float64, error) {
+	d := r.decoder()
+	return d.Float64()
+}
+
+// Bool decodes boolean value.
+func (r Raw) Bool() (bool, error) {
+	d := r.decoder()
+	return d.Bool()
+}
+
 func (r Raw) String() string { return string(r) }
+
+// errRawNotEqual stops iteration in rawEqual.
+var errRawNotEqual = errors.New("not equal")
+
+// rawEqual reports whether json values are semantically equal: objects
+// are compared regardless of member order, strings after unescaping and
+// numbers by numeric value.
+//
+// Values should be valid.
+func rawEqual(a, b Raw) bool {
+	t := a.Type()
+	if t != b.Type() {
+		return false
+	}
+	switch t {
+	case Object:
+		members := map[string]Raw{}
+		if err := b.Obj(func(key []byte, v Raw) error {
+			members[string(key)] = v
+			return nil
+		}); err != nil {
+			return false
+		}
+		n := 0
+		err := a.Obj(func(key []byte, v Raw) error {
+			n++
+			if w, ok := members[string(key)]; !ok || !rawEqual(v, w) {
+				return errRawNotEqual
+			}
+			return nil
+		})
+		return err == nil && n == len(members)
+	case Array:
+		var elems []Raw
+		if err := b.Arr(func(v Raw) error {
+			elems = append(elems, v)
+			return nil
+		}); err != nil {
+			return false
+		}
+		i := 0
+		err := a.Arr(func(v Raw) error {
+			if i >= len(elems) || !rawEqual(v, elems[i]) {
+				return errRawNotEqual
+			}
+			i++
+			return nil
+		})
+		return err == nil && i == len(elems)
+	case String:
+		if bytes.Equal(bytes.TrimSpace(a), bytes.TrimSpace(b)) {
+			return true
+		}
+		x, err := a.Str()
+		if err != nil {
+			return false
+		}
+		y, err := b.Str()
+		return err == nil && x == y
+	case Number:
+		return numEqual(bytes.TrimSpace(a), bytes.TrimSpace(b))
+	default:
+		return bytes.Equal(bytes.TrimSpace(a), bytes.TrimSpace(b))
+	}
+}
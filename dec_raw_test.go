@@ -224,3 +224,169 @@ func BenchmarkDecoder_Raw(b *testing.B) {
 		}
 	})
 }
+
+func TestRaw_Get(t *testing.T) {
+	a := require.New(t)
+	r := Raw(`{"a": {"b": [1, {"c": "d"}]}, "ef": true, "a": 2}`)
+
+	// First duplicate field is returned.
+	v, err := r.Get("a")
+	a.NoError(err)
+	a.Equal(`{"b": [1, {"c": "d"}]}`, v.String())
+
+	// Keys are unescaped.
+	v, err = r.Get("ef")
+	a.NoError(err)
+	a.Equal(`true`, v.String())
+
+	v, err = r.Get("a")
+	a.NoError(err)
+	v, err = v.Get("b")
+	a.NoError(err)
+	v, err = v.Index(1)
+	a.NoError(err)
+	a.Equal(`{"c": "d"}`, v.String())
+
+	for _, tt := range []struct {
+		raw string
+		key string
+	}{
+		{r.String(), "x"},
+		{`[1]`, "a"},
+		{`"a"`, "a"},
+		{``, "a"},
+	} {
+		_, err := Raw(tt.raw).Get(tt.key)
+		a.ErrorIs(err, ErrNotFound, tt.raw)
+	}
+	_, err = Raw(`{"a" 1}`).Get("a")
+	a.Error(err)
+	a.NotErrorIs(err, ErrNotFound)
+}
+
+func TestRaw_Index(t *testing.T) {
+	a := require.New(t)
+	r := Raw(`[1, "foo", [true], {"a": null}]`)
+	for i, expected := range []string{`1`, `"foo"`, `[true]`, `{"a": null}`} {
+		v, err := r.Index(i)
+		a.NoError(err)
+		a.Equal(expected, v.String())
+	}
+	for _, idx := range []int{-1, 4} {
+		_, err := r.Index(idx)
+		a.ErrorIs(err, ErrNotFound)
+	}
+	_, err := Raw(`{"a": 1}`).Index(0)
+	a.ErrorIs(err, ErrNotFound)
+	_, err = Raw(`[1 2]`).Index(1)
+	a.Error(err)
+	a.NotErrorIs(err, ErrNotFound)
+}
+
+func TestRaw_Len(t *testing.T) {
+	for _, tt := range []struct {
+		raw    string
+		expect int
+	}{
+		{`{}`, 0},
+		{`[]`, 0},
+		{`{"a": 1, "b": [1, 2]}`, 2},
+		{`[1, [2, 3], {"a": 4}]`, 3},
+	} {
+		n, err := Raw(tt.raw).Len()
+		require.NoError(t, err, tt.raw)
+		require.Equal(t, tt.expect, n, tt.raw)
+	}
+	for _, r := range []string{`1`, `"foo"`, `[1,`, `{"a"}`} {
+		_, err := Raw(r).Len()
+		require.Error(t, err, r)
+	}
+}
+
+func TestRaw_Iter(t *testing.T) {
+	a := require.New(t)
+
+	var keys, values []string
+	a.NoError(Raw(`{"a": 1, "b\n": [2, 3]}`).Obj(func(key []byte, v Raw) error {
+		keys = append(keys, string(key))
+		values = append(values, v.String())
+		return nil
+	}))
+	a.Equal([]string{"a", "b\n"}, keys)
+	a.Equal([]string{`1`, `[2, 3]`}, values)
+
+	values = values[:0]
+	a.NoError(Raw(`[1, "foo", {}]`).Arr(func(v Raw) error {
+		values = append(values, v.String())
+		return nil
+	}))
+	a.Equal([]string{`1`, `"foo"`, `{}`}, values)
+
+	testErr := fmt.Errorf("test")
+	a.ErrorIs(Raw(`{"a": 1}`).Obj(func(key []byte, v Raw) error { return testErr }), testErr)
+	a.ErrorIs(Raw(`[1]`).Arr(func(v Raw) error { return testErr }), testErr)
+	a.Error(Raw(`[1]`).Obj(func(key []byte, v Raw) error { return nil }))
+	a.Error(Raw(`{}`).Arr(func(v Raw) error { return nil }))
+}
+
+func TestRaw_Typed(t *testing.T) {
+	a := require.New(t)
+
+	s, err := Raw(`"foo\nbar"`).Str()
+	a.NoError(err)
+	a.Equal("foo\nbar", s)
+
+	n, err := Raw(`-42`).Int64()
+	a.NoError(err)
+	a.Equal(int64(-42), n)
+
+	f, err := Raw(`1.5`).Float64()
+	a.NoError(err)
+	a.Equal(1.5, f)
+
+	b, err := Raw(`true`).Bool()
+	a.NoError(err)
+	a.True(b)
+
+	num, err := Raw(`"12"`).Num()
+	a.NoError(err)
+	a.Equal(`"12"`, num.String())
+
+	_, err = Raw(`1`).Str()
+	a.Error(err)
+	_, err = Raw(`"1"`).Int64()
+	a.Error(err)
+	_, err = Raw(`null`).Bool()
+	a.Error(err)
+	_, err = Raw(``).Float64()
+	a.Error(err)
+}
+
+func TestRawEqual(t *testing.T) {
+	for _, tt := range []struct {
+		a, b  string
+		equal bool
+	}{
+		{`null`, ` null `, true},
+		{`true`, `true`, true},
+		{`"a"`, `"a"`, true},
+		{`1`, `1.0`, true},
+		{`[1,"a",[]]`, `[ 1.0 , "a", [ ] ]`, true},
+		{`{"a":1,"b":{"c":[]}}`, `{"b":{"c":[]},"a":1e0}`, true},
+		{`{"a\/b":1}`, `{"a/b":1}`, true},
+		{`null`, `false`, false},
+		{`true`, `false`, false},
+		{`"a"`, `"b"`, false},
+		{`1`, `"1"`, false},
+		{`[1,2]`, `[2,1]`, false},
+		{`[1,2]`, `[1]`, false},
+		{`[1]`, `[1,2]`, false},
+		{`{"a":1}`, `{"a":1,"b":2}`, false},
+		{`{"a":1,"b":2}`, `{"a":1}`, false},
+		{`{"a":1}`, `{"b":1}`, false},
+		{`{"a":1}`, `{"a":2}`, false},
+	} {
+		require.Equal(t, tt.equal, rawEqual(Raw(tt.a), Raw(tt.b)), "%s = %s", tt.a, tt.b)
+		require.Equal(t, tt.equal, rawEqual(Raw(tt.b), Raw(tt.a)), "%s = %s", tt.b, tt.a)
+	}
+}
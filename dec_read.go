@@ -3,6 +3,8 @@ package jx
 import (
 	"io"
 	"math/bits"
+
+	"github.com/go-faster/errors"
 )
 
 // Next gets Type of relatively next json element
@@ -11,6 +13,9 @@ func (d *Decoder) Next() Type {
 	if err == nil {
 		d.unread()
 	}
+	if d.json5 {
+		return json5Types[v]
+	}
 	return types[v]
 }
 
@@ -19,13 +24,23 @@ var spaceSet = [256]byte{
 }
 
 func (d *Decoder) consume(c byte) (err error) {
+	if d.json5 {
+		got, err := d.more()
+		if err != nil {
+			return err
+		}
+		if got != c {
+			return d.badToken(got, d.offset()-1)
+		}
+		return nil
+	}
 	for {
 		buf := d.buf[d.head:d.tail]
 		for i, got := range buf {
 			switch spaceSet[got] {
 			default:
 				if c != got {
-					return badToken(got, d.offset()+i)
+					return d.badToken(got, d.offset()+i)
 				}
 				d.head += i + 1
 				return nil
@@ -35,29 +50,60 @@ func (d *Decoder) consume(c byte) (err error) {
 		}
 		if err = d.read(); err != nil {
 			if err == io.EOF {
-				return io.ErrUnexpectedEOF
+				return d.unexpectedEOF()
 			}
 			return err
 		}
 	}
 }
 
+// consumeType consumes c that starts value of type t.
+//
+// Returns DecodeError if value has other type.
+func (d *Decoder) consumeType(c byte, t Type) error {
+	got, err := d.more()
+	if err != nil {
+		return errors.Wrapf(err, "%q expected", string(c))
+	}
+	if got != c {
+		err := d.typeError(t, got, d.offset()-1)
+		if _, ok := err.(*DecodeError); ok {
+			return err
+		}
+		return errors.Wrapf(err, "%q expected", string(c))
+	}
+	return nil
+}
+
 // more is next but io.EOF is unexpected.
 func (d *Decoder) more() (byte, error) {
 	c, err := d.next()
 	if err == io.EOF {
-		err = io.ErrUnexpectedEOF
+		err = d.unexpectedEOF()
 	}
 	return c, err
 }
 
 // next reads next non-whitespace token or error.
 func (d *Decoder) next() (byte, error) {
+scan:
 	for {
 		buf := d.buf[d.head:d.tail]
 		for i, c := range buf {
 			switch spaceSet[c] {
 			default:
+				if d.json5 {
+					d.head += i
+					ok, err := d.skipJSON5Space(c)
+					if err != nil {
+						return 0, err
+					}
+					if ok {
+						continue scan
+					}
+					d.head++
+					return c, nil
+				}
 				d.head += i + 1
 				return c, nil
 			case 1:
@@ -85,7 +131,7 @@ func (d *Decoder) byte() (byte, error) {
 	if d.head == d.tail {
 		err := d.read()
 		if err == io.EOF {
-			err = io.ErrUnexpectedEOF
+			err = d.unexpectedEOF()
 		}
 		if err != nil {
 			return 0, err
@@ -99,10 +145,16 @@ func (d *Decoder) byte() (byte, error) {
 func (d *Decoder) read() error {
 	if d.reader == nil {
 		d.head = d.tail
-		return io.EOF
+		return d.endOfInput(io.EOF)
 	}
 
-	n, err := d.reader.Read(d.buf)
+	buf, err := d.readBuf(0)
+	if err != nil {
+		return err
+	}
+	// Buffer is overwritten by Read.
+	lines := d.discardLines(d.tail)
+	n, err := d.reader.Read(buf)
 	switch err {
 	case nil:
 	case io.EOF:
@@ -114,6 +166,8 @@ func (d *Decoder) read() error {
 		return err
 	}
 
+	d.lineBase = lines
+	d.lineLast = linePos{}
 	d.streamOffset += d.tail
 	d.head = 0
 	d.tail = n
@@ -123,26 +177,74 @@ func (d *Decoder) read() error {
 func (d *Decoder) readAtLeast(n int) error {
 	if d.reader == nil {
 		d.head = d.tail
-		return io.ErrUnexpectedEOF
+		return d.endOfInput(d.unexpectedEOF())
 	}
 
 	if need := n - len(d.buf); need > 0 {
 		d.buf = append(d.buf, make([]byte, need)...)
 	}
-	n, err := io.ReadAtLeast(d.reader, d.buf, n)
+	buf, err := d.readBuf(n)
+	if err != nil {
+		if err == io.EOF {
+			return d.unexpectedEOF()
+		}
+		return err
+	}
+	lines := d.discardLines(d.tail)
+	n, err = io.ReadAtLeast(d.reader, buf, n)
 	if err != nil {
 		if err == io.EOF && n == 0 {
-			return io.ErrUnexpectedEOF
+			return d.unexpectedEOF()
 		}
 		return err
 	}
 
+	d.lineBase = lines
+	d.lineLast = linePos{}
 	d.streamOffset += d.tail
 	d.head = 0
 	d.tail = n
 	return nil
 }
 
+// fill buffers at least n bytes after head, keeping unread bytes.
+//
+// Returns io.EOF if input ends before.
+func (d *Decoder) fill(n int) error {
+	if d.tail-d.head >= n {
+		return nil
+	}
+	if d.reader == nil {
+		return d.endOfInput(io.EOF)
+	}
+
+	// Move unread bytes to the start of buffer.
+	d.lineBase = d.discardLines(d.head)
+	d.lineLast = linePos{}
+	d.streamOffset += d.head
+	d.tail = copy(d.buf, d.buf[d.head:d.tail])
+	d.head = 0
+	if need := n - len(d.buf); need > 0 {
+		d.buf = append(d.buf, make([]byte, need)...)
+	}
+	for d.tail < n {
+		allowed, err := d.readBuf(0)
+		if err != nil {
+			return err
+		}
+		buf := d.buf[d.tail:]
+		if len(allowed) < len(buf) {
+			buf = buf[:len(allowed)]
+		}
+		k, err := d.reader.Read(buf)
+		d.tail += k
+		if err != nil && (err != io.EOF || k == 0) {
+			return err
+		}
+	}
+	return nil
+}
+
 func (d *Decoder) unread() { d.head-- }
 
 func (d *Decoder) readExact4(b *[4]byte) error {
@@ -159,8 +261,8 @@ func (d *Decoder) readExact4(b *[4]byte) error {
 	return nil
 }
 
-func findInvalidToken4(buf [4]byte, mask uint32, offset int) error {
+func (d *Decoder) findInvalidToken4(buf [4]byte, mask uint32, offset int) error {
 	c := uint32(buf[0]) | uint32(buf[1])<<8 | uint32(buf[2])<<16 | uint32(buf[3])<<24
 	idx := bits.TrailingZeros32(c^mask) / 8
-	return badToken(buf[idx], offset+idx)
+	return d.badToken(buf[idx], offset+idx)
 }
@@ -0,0 +1,162 @@
+package jx
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+
+	"github.com/go-faster/errors"
+)
+
+// seqRS is record separator of json text sequence.
+const seqRS = 0x1E
+
+var (
+	// ErrTruncatedRecord means that json text sequence record is truncated.
+	//
+	// Top-level number, true, false or null that is not followed by
+	// whitespace is truncated, because it can be prefix of another value,
+	// like 12 is prefix of 123.
+	ErrTruncatedRecord = errors.New("truncated record")
+
+	errNoRS = errors.New("missing record separator")
+)
+
+// SeqError is error of single record of SeqReader.
+type SeqError struct {
+	Record int // number of record, starting from 1
+	Err    error
+}
+
+func (e *SeqError) Error() string {
+	return fmt.Sprintf("record %d: %v", e.Record, e.Err)
+}
+
+func (e *SeqError) Unwrap() error {
+	return e.Err
+}
+
+// SeqReader reads json text sequence (application/json-seq), as defined
+// by RFC 7464.
+//
+// Each record starts with record separator (0x1E) and is decoded
+// independently, so malformed or truncated record does not prevent
+// reading of next ones. Empty records are skipped.
+//
+// See https://www.rfc-editor.org/rfc/rfc7464.
+type SeqReader struct {
+	r   *bufio.Reader
+	d   Decoder
+	buf []byte
+	n   int  // number of current record
+	rs  bool // current record starts with separator
+	err error
+}
+
+// NewSeqReader creates new SeqReader from io.Reader.
+func NewSeqReader(r io.Reader) *SeqReader {
+	sr := &SeqReader{}
+	sr.Reset(r)
+	return sr
+}
+
+// Reset resets SeqReader to read from r.
+//
+// Options of Decoder are kept.
+func (r *SeqReader) Reset(rd io.Reader) {
+	if r.r == nil {
+		r.r = bufio.NewReader(rd)
+	} else {
+		r.r.Reset(rd)
+	}
+	r.buf = r.buf[:0]
+	r.n = 0
+	r.rs = false
+	r.err = nil
+	r.d.ResetBytes(nil)
+}
+
+// Next reads next record, returning false on end of input or I/O error.
+func (r *SeqReader) Next() bool {
+	for r.err == nil {
+		// Bytes before first separator are read as record without separator.
+		rs := r.n > 0 || r.rs
+		buf, ok, err := readRecord(r.r, r.buf[:0], seqRS, r.d.limits.Bytes)
+		r.buf = buf
+		if err != nil {
+			r.err = err
+			return false
+		}
+		if !ok {
+			return false
+		}
+		r.rs = true
+		if !isBlank(r.buf) {
+			r.n++
+			r.rs = rs
+			r.d.ResetBytes(r.buf)
+			return true
+		}
+	}
+	return false
+}
+
+// Record returns number of current record, starting from 1.
+func (r *SeqReader) Record() int {
+	return r.n
+}
+
+// Decoder returns Decoder of current record.
+//
+// Options, like limits, set on returned Decoder are kept for next records.
+// Limits.Bytes applies to each record.
+func (r *SeqReader) Decoder() *Decoder {
+	return &r.d
+}
+
+// Raw returns current record without record separator.
+//
+// Do not retain returned value, it references underlying buffer.
+func (r *SeqReader) Raw() Raw {
+	return r.buf
+}
+
+// Decode calls f with Decoder of current record, checking that record
+// has no trailing data and is not truncated.
+//
+// Returned error is *SeqError.
+func (r *SeqReader) Decode(f func(d *Decoder) error) error {
+	if !r.rs {
+		return &SeqError{Record: r.n, Err: errNoRS}
+	}
+	r.d.ResetBytes(r.buf)
+	if err := f(&r.d); err != nil {
+		return &SeqError{Record: r.n, Err: err}
+	}
+	if err := r.d.Skip(); err != io.EOF {
+		return &SeqError{Record: r.n, Err: errors.Wrap(err, "unexpected trialing data")}
+	}
+	switch r.Raw().Type() {
+	case Number, Bool, Null:
+		if spaceSet[r.buf[len(r.buf)-1]] == 0 {
+			return &SeqError{Record: r.n, Err: ErrTruncatedRecord}
+		}
+	}
+	return nil
+}
+
+// Validate validates current record.
+//
+// Returned error is *SeqError.
+func (r *SeqReader) Validate() error {
+	return r.Decode(func(d *Decoder) error {
+		return d.Skip()
+	})
+}
+
+// Err returns I/O error, if any.
+//
+// Errors of records are returned by Decode and Validate.
+func (r *SeqReader) Err() error {
+	return r.err
+}
@@ -0,0 +1,94 @@
+package jx
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeqReader(t *testing.T) {
+	const input = "\x1e{\"id\":1}\n" +
+		"\x1e\x1e\n" +
+		"\x1e{\"id\":\n" + // truncated object
+		"\x1e123" + // truncated number
+		"\x1e{\"id\":2}\n" +
+		"\x1e true \n" +
+		"\x1e{\"a\":[\n1,\n2]}\n"
+	type record struct {
+		n   int
+		raw string
+		err bool
+	}
+	expect := []record{
+		{n: 1, raw: "{\"id\":1}\n"},
+		{n: 2, raw: "{\"id\":\n", err: true},
+		{n: 3, raw: "123", err: true},
+		{n: 4, raw: "{\"id\":2}\n"},
+		{n: 5, raw: " true \n"},
+		{n: 6, raw: "{\"a\":[\n1,\n2]}\n"},
+	}
+	for _, r := range []struct {
+		name string
+		r    func() io.Reader
+	}{
+		{"Reader", func() io.Reader { return strings.NewReader(input) }},
+		{"OneByteReader", func() io.Reader { return iotest.OneByteReader(strings.NewReader(input)) }},
+		{"DataErrReader", func() io.Reader { return iotest.DataErrReader(strings.NewReader(input)) }},
+	} {
+		r := r
+		t.Run(r.name, func(t *testing.T) {
+			a := require.New(t)
+			var got []record
+			sr := NewSeqReader(r.r())
+			for sr.Next() {
+				rec := record{n: sr.Record(), raw: sr.Raw().String()}
+				if err := sr.Validate(); err != nil {
+					var seqErr *SeqError
+					a.ErrorAs(err, &seqErr)
+					a.Equal(rec.n, seqErr.Record)
+					if rec.n == 3 {
+						a.ErrorIs(err, ErrTruncatedRecord)
+					}
+					rec.err = true
+				}
+				got = append(got, rec)
+			}
+			a.NoError(sr.Err())
+			a.Equal(expect, got)
+		})
+	}
+}
+
+func TestSeqReader_NoRS(t *testing.T) {
+	a := require.New(t)
+	sr := NewSeqReader(strings.NewReader("1\n\x1e2\n"))
+	a.True(sr.Next())
+	a.ErrorIs(sr.Validate(), errNoRS)
+	a.True(sr.Next())
+	a.NoError(sr.Validate())
+	a.False(sr.Next())
+
+	sr.Reset(strings.NewReader(" \n\x1e3\n"))
+	a.True(sr.Next())
+	a.Equal(1, sr.Record())
+	v, err := sr.Decoder().Int()
+	a.NoError(err)
+	a.Equal(3, v)
+	a.False(sr.Next())
+}
+
+func TestSeqReader_Limits(t *testing.T) {
+	a := require.New(t)
+	sr := NewSeqReader(strings.NewReader("\x1e[1]\n\x1e[1,2,3]\n\x1e[2]\n"))
+	sr.Decoder().SetLimits(Limits{Bytes: 4})
+	a.True(sr.Next())
+	a.NoError(sr.Validate())
+	a.True(sr.Next())
+	a.ErrorIs(sr.Validate(), ErrMaxBytes)
+	a.True(sr.Next())
+	a.NoError(sr.Validate())
+	a.False(sr.Next())
+}
@@ -12,6 +12,11 @@ func (d *Decoder) Skip() error {
 	if err != nil {
 		return err
 	}
+	if d.json5 {
+		if ok, err := d.skipJSON5(c); ok {
+			return err
+		}
+	}
 	switch c {
 	case '"':
 		if err := d.skipStr(); err != nil {
@@ -39,7 +44,7 @@ func (d *Decoder) Skip() error {
 		}
 		return nil
 	default:
-		return badToken(c, d.offset()-1)
+		return d.badToken(c, d.offset()-1)
 	}
 }
 
@@ -68,6 +73,17 @@ var skipNumberSet = [256]byte{
 //
 // Assumes d.buf is not empty.
 func (d *Decoder) skipNumber() error {
+	if d.limits.NumLen <= 0 {
+		return d.skipNumberLiteral()
+	}
+	start := d.offset()
+	if err := d.skipNumberLiteral(); err != nil {
+		return err
+	}
+	return d.checkNumLen(d.offset() - start)
+}
+
+func (d *Decoder) skipNumberLiteral() error {
 	const (
 		digitTag  byte = 1
 		closerTag byte = 2
@@ -82,7 +98,7 @@ func (d *Decoder) skipNumber() error {
 		}
 		// Character after '-' must be a digit.
 		if skipNumberSet[c] != digitTag {
-			return badToken(c, d.offset()-1)
+			return d.badToken(c, d.offset()-1)
 		}
 		if c != '0' {
 			break
@@ -111,7 +127,7 @@ func (d *Decoder) skipNumber() error {
 		case 'e', 'E':
 			goto stateExp
 		default:
-			return badToken(c, d.offset())
+			return d.badToken(c, d.offset())
 		}
 	}
 	for {
@@ -132,7 +148,7 @@ func (d *Decoder) skipNumber() error {
 				d.head += i
 				goto stateExp
 			default:
-				return badToken(c, d.offset()+i)
+				return d.badToken(c, d.offset()+i)
 			}
 		}
 
@@ -157,7 +173,7 @@ stateDot:
 					d.head += i
 					// Check that dot is not last character.
 					if last == '.' {
-						return io.ErrUnexpectedEOF
+						return d.unexpectedEOF()
 					}
 					return nil
 				case digitTag:
@@ -168,12 +184,12 @@ stateDot:
 				switch c {
 				case 'e', 'E':
 					if last == '.' {
-						return badToken(c, d.offset()+i)
+						return d.badToken(c, d.offset()+i)
 					}
 					d.head += i
 					goto stateExp
 				default:
-					return badToken(c, d.offset()+i)
+					return d.badToken(c, d.offset()+i)
 				}
 			}
 
@@ -183,7 +199,7 @@ stateDot:
 					d.head = d.tail
 					// Check that dot is not last character.
 					if last == '.' {
-						return io.ErrUnexpectedEOF
+						return d.unexpectedEOF()
 					}
 					return nil
 				}
@@ -207,10 +223,10 @@ stateExp:
 				}
 				// There must be a number after sign.
 				if skipNumberSet[num] != digitTag {
-					return badToken(num, d.offset()-1)
+					return d.badToken(num, d.offset()-1)
 				}
 			} else {
-				return badToken(numOrSign, d.offset()-1)
+				return d.badToken(numOrSign, d.offset()-1)
 			}
 		}
 	}
@@ -221,7 +237,7 @@ stateExp:
 				return nil
 			}
 			if skipNumberSet[c] == 0 {
-				return badToken(c, d.offset()+i)
+				return d.badToken(c, d.offset()+i)
 			}
 		}
 
@@ -261,80 +277,43 @@ var (
 	}
 )
 
+// strSpanScalar is portable implementation of strSpan.
+func strSpanScalar(buf []byte) int {
+	for i, c := range buf {
+		if safeSet[c] != 0 {
+			return i
+		}
+	}
+	return len(buf)
+}
+
 // skipStr reads one JSON string.
 //
 // Assumes first quote was consumed.
 func (d *Decoder) skipStr() error {
+	if d.utf8 == UTF8Strict {
+		_, err := d.strUTF8(value{}, true)
+		return err
+	}
 	var (
-		c byte
-		i int
+		c     byte
+		i     int
+		start = d.offset()
 	)
 readStr:
 	for {
-		i = 0
 		buf := d.buf[d.head:d.tail]
-		for len(buf) >= 8 {
-			c = buf[0]
-			if safeSet[c] != 0 {
-				goto readTok
-			}
-			i++
-
-			c = buf[1]
-			if safeSet[c] != 0 {
-				goto readTok
-			}
-			i++
-
-			c = buf[2]
-			if safeSet[c] != 0 {
-				goto readTok
-			}
-			i++
-
-			c = buf[3]
-			if safeSet[c] != 0 {
-				goto readTok
-			}
-			i++
-
-			c = buf[4]
-			if safeSet[c] != 0 {
-				goto readTok
-			}
-			i++
-
-			c = buf[5]
-			if safeSet[c] != 0 {
-				goto readTok
-			}
-			i++
-
-			c = buf[6]
-			if safeSet[c] != 0 {
-				goto readTok
-			}
-			i++
-
-			c = buf[7]
-			if safeSet[c] != 0 {
-				goto readTok
-			}
-			i++
-
-			buf = buf[8:]
-		}
-		var n int
-		for n, c = range buf {
-			if safeSet[c] != 0 {
-				i += n
-				goto readTok
-			}
+		if i = strSpan(buf); i < len(buf) {
+			c = buf[i]
+			goto readTok
 		}
 
+		if err := d.checkStrLen(start, d.streamOffset+d.tail); err != nil {
+			return err
+		}
 		if err := d.read(); err != nil {
 			if err == io.EOF {
-				err = io.ErrUnexpectedEOF
+				err = d.unexpectedEOF()
 			}
 			return err
 		}
@@ -345,7 +324,7 @@ readTok:
 	switch {
 	case c == '"':
 		d.head += i + 1
-		return nil
+		return d.checkStrLen(start, d.offset()-1)
 	case c == '\\':
 		d.head += i + 1
 		v, err := d.byte()
@@ -360,14 +339,14 @@ readTok:
 					return err
 				}
 				if hexSet[h] == 0 {
-					return badToken(h, d.offset()-1)
+					return d.badToken(h, d.offset()-1)
 				}
 			}
 		case 0:
-			return badToken(v, d.offset()-1)
+			return d.badToken(v, d.offset()-1)
 		}
 	case c < ' ':
-		return badToken(c, d.offset()+i)
+		return d.badToken(c, d.offset()+i)
 	}
 	goto readStr
 }
@@ -376,34 +355,56 @@ readTok:
 //
 // Assumes first bracket was consumed.
 func (d *Decoder) skipObj() error {
+	if d.indexable() && d.skipIndexed() {
+		return nil
+	}
 	if err := d.incDepth(); err != nil {
 		return errors.Wrap(err, "inc")
 	}
+	d.objStart()
 
 	c, err := d.more()
 	if err != nil {
 		return errors.Wrap(err, `'"' or "}" expected`)
 	}
-	switch c {
-	case '}':
+	switch {
+	case c == '}':
 		return d.decDepth()
-	case '"':
+	case c == '"', d.json5:
 		d.unread()
 	default:
-		return badToken(c, d.offset()-1)
+		return d.badToken(c, d.offset()-1)
 	}
 
-	for {
-		if err := d.consume('"'); err != nil {
-			return errors.Wrap(err, `'"' expected`)
+	for n := 1; ; n++ {
+		if n > 1 {
+			end, err := d.trailingComma('}')
+			if err != nil {
+				return err
+			}
+			if end {
+				return d.decDepth()
+			}
 		}
-		if err := d.skipStr(); err != nil {
-			return errors.Wrap(err, "read field name")
+		if err := d.checkObjLen(n); err != nil {
+			return err
+		}
+		if d.noDupKeys || d.json5 {
+			if _, err := d.objKey(value{raw: d.reader == nil}); err != nil {
+				return errors.Wrap(err, "read field name")
+			}
+		} else {
+			if err := d.consume('"'); err != nil {
+				return errors.Wrap(err, `'"' expected`)
+			}
+			if err := d.skipStr(); err != nil {
+				return errors.Wrap(err, "read field name")
+			}
 		}
 		if err := d.consume(':'); err != nil {
 			return errors.Wrap(err, `":" expected`)
 		}
-		if err := d.Skip(); err != nil {
+		if err := d.skipElem(); err != nil {
 			return err
 		}
 		c, err := d.more()
@@ -416,7 +417,7 @@ func (d *Decoder) skipObj() error {
 		case '}':
 			return d.decDepth()
 		default:
-			return badToken(c, d.offset()-1)
+			return d.badToken(c, d.offset()-1)
 		}
 	}
 }
@@ -425,6 +426,9 @@ func (d *Decoder) skipObj() error {
 //
 // Assumes first bracket was consumed.
 func (d *Decoder) skipArr() error {
+	if d.indexable() && d.skipIndexed() {
+		return nil
+	}
 	if err := d.incDepth(); err != nil {
 		return errors.Wrap(err, "inc")
 	}
@@ -438,8 +442,20 @@ func (d *Decoder) skipArr() error {
 	}
 	d.unread()
 
-	for {
-		if err := d.Skip(); err != nil {
+	for n := 1; ; n++ {
+		if n > 1 {
+			end, err := d.trailingComma(']')
+			if err != nil {
+				return err
+			}
+			if end {
+				return d.decDepth()
+			}
+		}
+		if err := d.checkArrLen(n); err != nil {
+			return err
+		}
+		if err := d.skipElem(); err != nil {
 			return err
 		}
 		c, err := d.more()
@@ -452,11 +468,21 @@ func (d *Decoder) skipArr() error {
 		case ']':
 			return d.decDepth()
 		default:
-			return badToken(c, d.offset()-1)
+			return d.badToken(c, d.offset()-1)
 		}
 	}
 }
 
+// skipElem skips element of object or array, where end of input is
+// unexpected.
+func (d *Decoder) skipElem() error {
+	err := d.Skip()
+	if err == io.EOF {
+		return d.unexpectedEOF()
+	}
+	return err
+}
+
 // skipSpace skips space characters.
 //
 // Returns io.ErrUnexpectedEOF if got io.EOF.
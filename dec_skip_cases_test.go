@@ -512,7 +512,7 @@ func TestDecoder_Skip(t *testing.T) {
 							return nil
 						}()
 						should.Error(err)
-						if be, ok := errors.Into[*badTokenErr](err); ok {
+						if be, ok := errors.Into[*SyntaxError](err); ok {
 							offset := be.Offset
 							should.True(offset >= 0)
 							should.True(offset < len(input))
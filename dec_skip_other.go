@@ -0,0 +1,9 @@
+//go:build !amd64 && !arm64
+
+package jx
+
+// strSpan returns length of prefix of buf without quotes, backslashes and
+// control characters.
+func strSpan(buf []byte) int {
+	return strSpanScalar(buf)
+}
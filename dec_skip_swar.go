@@ -0,0 +1,42 @@
+//go:build amd64 || arm64
+
+package jx
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// SWAR (SIMD within a register) string scanning, processing 8 bytes at
+// once. Used on 64-bit platforms with fast unaligned loads.
+
+const (
+	swarLSB  = 0x0101010101010101
+	swarMSB  = 0x8080808080808080
+	swarLow7 = 0x7f7f7f7f7f7f7f7f
+)
+
+// swarEq returns word with high bit set in every byte of w equal to c.
+func swarEq(w uint64, c byte) uint64 {
+	x := w ^ (uint64(c) * swarLSB)
+	return ^((x&swarLow7 + swarLow7) | x | swarLow7)
+}
+
+// swarControl returns word with high bit set in every byte of w less
+// than 0x20.
+func swarControl(w uint64) uint64 {
+	return ^((w&swarLow7 + (0x80-0x20)*swarLSB) | w) & swarMSB
+}
+
+// strSpan returns length of prefix of buf without quotes, backslashes and
+// control characters.
+func strSpan(buf []byte) int {
+	i := 0
+	for ; len(buf)-i >= 8; i += 8 {
+		w := binary.LittleEndian.Uint64(buf[i:])
+		if m := swarEq(w, '"') | swarEq(w, '\\') | swarControl(w); m != 0 {
+			return i + bits.TrailingZeros64(m)/8
+		}
+	}
+	return i + strSpanScalar(buf[i:])
+}
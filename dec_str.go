@@ -34,7 +34,10 @@ func (v value) rune(r rune) value {
 }
 
 func (d *Decoder) str(v value) (value, error) {
-	if err := d.consume('"'); err != nil {
+	if d.json5 {
+		return d.strJSON5(v)
+	}
+	if err := d.consumeType('"', String); err != nil {
 		return value{}, err
 	}
 	var (
@@ -101,7 +104,10 @@ func (d *Decoder) str(v value) (value, error) {
 				goto readTok
 			}
 		}
-		return d.strSlow(v)
+		if d.utf8 != UTF8Unchecked {
+			return d.strUTF8(value{buf: v.buf}, false)
+		}
+		return d.strSlow(v, d.offset())
 	}
 readTok:
 	buf := d.buf[d.head:d.tail]
@@ -109,6 +115,12 @@ readTok:
 
 	switch {
 	case c == '"':
+		if d.utf8 != UTF8Unchecked && !utf8.Valid(str) {
+			return d.strUTF8(value{buf: v.buf}, false)
+		}
+		if err := d.checkStrLen(d.offset(), d.offset()+i); err != nil {
+			return v, err
+		}
 		// Skip string + last quote.
 		d.head += i + 1
 		if v.raw {
@@ -116,16 +128,21 @@ readTok:
 		}
 		return value{buf: append(v.buf, str...)}, nil
 	case c == '\\':
+		if d.utf8 != UTF8Unchecked {
+			return d.strUTF8(value{buf: v.buf}, false)
+		}
+		start := d.offset()
 		// Skip only string, keep quote in buffer.
 		d.head += i
 		// We need a copy anyway, because string is escaped.
-		return d.strSlow(value{buf: append(v.buf, str...)})
+		return d.strSlow(value{buf: append(v.buf, str...)}, start)
 	default:
-		return v, badToken(c, d.offset()+i)
+		return v, d.badToken(c, d.offset()+i)
 	}
 }
 
-func (d *Decoder) strSlow(v value) (value, error) {
+// strSlow reads rest of string, started at offset start.
+func (d *Decoder) strSlow(v value, start int) (value, error) {
 	var (
 		c byte
 		i int
@@ -192,10 +209,13 @@ readStr:
 			i++
 		}
 
+		if err := d.checkStrLen(start, d.offset()+i); err != nil {
+			return value{}, err
+		}
 		v.buf = append(v.buf, d.buf[d.head:d.head+i]...)
 		if err := d.read(); err != nil {
 			if err == io.EOF {
-				return value{}, io.ErrUnexpectedEOF
+				return value{}, d.unexpectedEOF()
 			}
 			return value{}, err
 		}
@@ -204,6 +224,9 @@ readTok:
 	buf := d.buf[d.head:d.tail]
 	str := buf[:i]
 	d.head += i + 1
+	if err := d.checkStrLen(start, d.offset()-1); err != nil {
+		return value{}, err
+	}
 
 	switch {
 	case c == '"':
@@ -219,7 +242,7 @@ readTok:
 			return v, errors.Wrap(err, "escape")
 		}
 	default:
-		return v, badToken(c, d.offset()-1)
+		return v, d.badToken(c, d.offset()-1)
 	}
 	goto readStr
 }
@@ -249,41 +272,53 @@ func (d *Decoder) escapedChar(v value, c byte) (value, error) {
 	default:
 		v.buf = append(v.buf, val)
 	case 'u':
+		offset := d.offset() - 2
 		r1, err := d.readU4()
 		if err != nil {
 			return value{}, errors.Wrap(err, "read u4")
 		}
-		if utf16.IsSurrogate(r1) {
+		for {
+			if !utf16.IsSurrogate(r1) {
+				return v.rune(r1), nil
+			}
+			if r1 >= 0xdc00 {
+				// Low surrogate without high one.
+				return d.loneSurrogate(v, r1, offset)
+			}
 			c, err := d.byte()
 			if err != nil {
 				return value{}, err
 			}
 			if c != '\\' {
 				d.unread()
-				return v.rune(r1), nil
+				return d.loneSurrogate(v, r1, offset)
 			}
 			c, err = d.byte()
 			if err != nil {
 				return value{}, err
 			}
 			if c != 'u' {
-				return d.escapedChar(v.rune(r1), c)
+				if v, err = d.loneSurrogate(v, r1, offset); err != nil {
+					return v, err
+				}
+				return d.escapedChar(v, c)
 			}
 			r2, err := d.readU4()
 			if err != nil {
 				return value{}, err
 			}
 			combined := utf16.DecodeRune(r1, r2)
-			if combined == '\uFFFD' {
-				v = v.rune(r1).rune(r2)
-			} else {
-				v = v.rune(combined)
+			if combined != '\uFFFD' {
+				return v.rune(combined), nil
+			}
+			if v, err = d.loneSurrogate(v, r1, offset); err != nil {
+				return v, err
 			}
-		} else {
-			v = v.rune(r1)
+			// Second escape can start another pair.
+			r1, offset = r2, offset+6
 		}
 	case 0:
-		err := badToken(c, d.offset()-1)
+		err := d.badToken(c, d.offset()-1)
 		return v, errors.Wrap(err, "bad escape")
 	}
 	return v, nil
@@ -300,7 +335,7 @@ func (d *Decoder) readU4() (v rune, _ error) {
 	for i, c := range b {
 		val := hexSet[c]
 		if val == 0 {
-			return 0, badToken(c, offset+i)
+			return 0, d.badToken(c, offset+i)
 		}
 		v = v*16 + rune(val-1)
 	}
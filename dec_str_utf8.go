@@ -0,0 +1,157 @@
+package jx
+
+import (
+	"io"
+	"unicode/utf8"
+
+	"github.com/go-faster/errors"
+)
+
+// UTF8Mode defines handling of invalid UTF-8 in strings.
+type UTF8Mode int
+
+const (
+	// UTF8Unchecked passes invalid UTF-8 bytes as is.
+	//
+	// Lone surrogate escapes are replaced with U+FFFD.
+	UTF8Unchecked UTF8Mode = iota
+	// UTF8Strict rejects strings with invalid UTF-8 bytes or lone
+	// surrogate escapes, returning ErrInvalidUTF8.
+	UTF8Strict
+	// UTF8Replace replaces invalid UTF-8 byte sequences and lone surrogate
+	// escapes with U+FFFD.
+	UTF8Replace
+)
+
+// ErrInvalidUTF8 means that string is not valid UTF-8 or contains lone
+// surrogate escape.
+var ErrInvalidUTF8 = errors.New("invalid UTF-8")
+
+// SetUTF8Mode sets handling of invalid UTF-8 in strings.
+//
+// Applies to Str, StrBytes, StrAppend and object keys. Skip and Validate
+// reject invalid strings only in UTF8Strict mode.
+// Mode is kept on Reset and ResetBytes.
+func (d *Decoder) SetUTF8Mode(mode UTF8Mode) {
+	d.utf8 = mode
+}
+
+func (d *Decoder) loneSurrogate(v value, r rune, offset int) (value, error) {
+	if d.utf8 == UTF8Strict {
+		return v, d.errorAt(errors.Wrapf(ErrInvalidUTF8, "lone surrogate %U", r), offset)
+	}
+	return v.rune(utf8.RuneError), nil
+}
+
+// strUTF8 reads rest of string, checking that it is valid UTF-8.
+//
+// String contents are not appended to v if skip is true.
+func (d *Decoder) strUTF8(v value, skip bool) (value, error) {
+	start := d.offset()
+	for {
+		buf := d.buf[d.head:d.tail]
+		i := 0
+		for i < len(buf) && buf[i] < utf8.RuneSelf && safeSet[buf[i]] == 0 {
+			i++
+		}
+		if !skip {
+			v.buf = append(v.buf, buf[:i]...)
+		}
+		d.head += i
+		if i == len(buf) {
+			if err := d.checkStrLen(start, d.offset()); err != nil {
+				return value{}, err
+			}
+			if err := d.read(); err != nil {
+				if err == io.EOF {
+					return value{}, d.unexpectedEOF()
+				}
+				return value{}, err
+			}
+			continue
+		}
+
+		offset := d.offset()
+		c := buf[i]
+		d.head++
+		switch {
+		case c == '"':
+			if err := d.checkStrLen(start, offset); err != nil {
+				return value{}, err
+			}
+			return v, nil
+		case c == '\\':
+			c, err := d.byte()
+			if err != nil {
+				return value{}, err
+			}
+			v, err = d.escapedChar(v, c)
+			if err != nil {
+				return v, errors.Wrap(err, "escape")
+			}
+			if skip {
+				v.buf = v.buf[:0]
+			}
+		case c < ' ':
+			return v, d.badToken(c, offset)
+		default:
+			r, ok, err := d.readRune(c)
+			if err != nil {
+				return value{}, err
+			}
+			if !ok && d.utf8 == UTF8Strict {
+				return v, d.errorAt(errors.Wrapf(ErrInvalidUTF8, "byte %#x", c), offset)
+			}
+			if !skip {
+				v.buf = appendRune(v.buf, r)
+			}
+		}
+	}
+}
+
+// readRune reads rest of UTF-8 sequence, started with c.
+//
+// Returns false and utf8.RuneError if sequence is invalid, consuming
+// its maximal valid prefix, as defined by WHATWG Encoding Standard.
+func (d *Decoder) readRune(c byte) (r rune, ok bool, _ error) {
+	var (
+		n            int
+		lower, upper byte = 0x80, 0xbf
+	)
+	switch {
+	case c >= 0xc2 && c <= 0xdf:
+		n, r = 1, rune(c&0x1f)
+	case c >= 0xe0 && c <= 0xef:
+		n, r = 2, rune(c&0x0f)
+		switch c {
+		case 0xe0:
+			lower = 0xa0
+		case 0xed:
+			// Surrogates are not allowed.
+			upper = 0x9f
+		}
+	case c >= 0xf0 && c <= 0xf4:
+		n, r = 3, rune(c&0x07)
+		switch c {
+		case 0xf0:
+			lower = 0x90
+		case 0xf4:
+			upper = 0x8f
+		}
+	default:
+		return utf8.RuneError, false, nil
+	}
+	for ; n > 0; n-- {
+		b, err := d.byte()
+		if err != nil {
+			return 0, false, err
+		}
+		if b < lower || b > upper {
+			d.unread()
+			return utf8.RuneError, false, nil
+		}
+		lower, upper = 0x80, 0xbf
+		r = r<<6 | rune(b&0x3f)
+	}
+	return r, true, nil
+}
@@ -0,0 +1,118 @@
+package jx
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-faster/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecoder_SetUTF8Mode(t *testing.T) {
+	long := strings.Repeat("ж", 300)
+	for _, tt := range []struct {
+		input   string
+		replace string // expected result in UTF8Replace mode
+		valid   bool   // valid in UTF8Strict mode
+	}{
+		{`"hello"`, "hello", true},
+		{`"héllo, 世界 😀"`, "héllo, 世界 😀", true},
+		{`"` + long + `"`, long, true},
+		{`"😀"`, "😀", true},
+		{`"é\n"`, "é\n", true},
+		{"\"\xef\xbf\xbd\"", "�", true},
+
+		{"\"a\xffb\"", "a�b", false},
+		{"\"\x80\"", "�", false},
+		{"\"\xc0\x80\"", "��", false},
+		{"\"\xe2\x82\"", "�", false},
+		{"\"\xe2\x82x\"", "�x", false},
+		{"\"\xf0\x9f\x98\\n\"", "�\n", false},
+		{"\"\xed\xa0\x80\"", "���", false},
+		{"\"\xf4\x90\x80\x80\"", "����", false},
+		{"\"" + long + "\xff\"", long + "�", false},
+		{`"\ud800"`, "�", false},
+		{`"\udc00"`, "�", false},
+		{`"\ud800x"`, "�x", false},
+		{`"\ud800\n"`, "�\n", false},
+		{`"\ud800A"`, "�A", false},
+		{`"\ud800\ud800"`, "��", false},
+		{`"\udc00😀"`, "�😀", false},
+		{`"\ud800\ud800\udc00"`, "�\U00010000", false},
+		{`"\ud800\ud800\ud800\udc00x"`, "��\U00010000x", false},
+		{`"\udbff\udbff\udfff\udc00"`, "�\U0010FFFF�", false},
+	} {
+		tt := tt
+		t.Run(tt.input, func(t *testing.T) {
+			t.Run("Strict", testBufferReader(tt.input, func(t *testing.T, d *Decoder) {
+				d.SetUTF8Mode(UTF8Strict)
+				s, err := d.Str()
+				if !tt.valid {
+					require.ErrorIs(t, err, ErrInvalidUTF8)
+					return
+				}
+				require.NoError(t, err)
+				require.Equal(t, tt.replace, s)
+			}))
+			t.Run("StrictValidate", testBufferReader(`[`+tt.input+`]`, func(t *testing.T, d *Decoder) {
+				d.SetUTF8Mode(UTF8Strict)
+				err := d.Validate()
+				if !tt.valid {
+					require.ErrorIs(t, err, ErrInvalidUTF8)
+					return
+				}
+				require.NoError(t, err)
+			}))
+			t.Run("Replace", testBufferReader(tt.input, func(t *testing.T, d *Decoder) {
+				d.SetUTF8Mode(UTF8Replace)
+				s, err := d.StrBytes()
+				require.NoError(t, err)
+				require.Equal(t, tt.replace, string(s))
+			}))
+			t.Run("ReplaceAppend", testBufferReader(tt.input, func(t *testing.T, d *Decoder) {
+				d.SetUTF8Mode(UTF8Replace)
+				s, err := d.StrAppend([]byte("prefix"))
+				require.NoError(t, err)
+				require.Equal(t, "prefix"+tt.replace, string(s))
+			}))
+			t.Run("ReplaceValidate", testBufferReader(tt.input, func(t *testing.T, d *Decoder) {
+				d.SetUTF8Mode(UTF8Replace)
+				require.NoError(t, d.Validate())
+			}))
+		})
+	}
+	t.Run("Offset", func(t *testing.T) {
+		for _, tt := range []struct {
+			input  string
+			offset int
+		}{
+			{`"\ud800\ud800\udc00"`, 1},
+			{`"a\udc00\ud800\udc00"`, 2},
+			{`"\ud83d\ude00\ud800\ud800"`, 13},
+		} {
+			tt := tt
+			t.Run(tt.input, testBufferReader(tt.input, func(t *testing.T, d *Decoder) {
+				d.SetUTF8Mode(UTF8Strict)
+				_, err := d.Str()
+				require.ErrorIs(t, err, ErrInvalidUTF8)
+				e, ok := errors.Into[*DecodeError](err)
+				require.True(t, ok, "%+v", err)
+				require.Equal(t, tt.offset, e.Offset)
+			}))
+		}
+	})
+	t.Run("Unchecked", func(t *testing.T) {
+		a := require.New(t)
+		s, err := DecodeStr("\"a\xffb\"").Str()
+		a.NoError(err)
+		a.Equal("a\xffb", s)
+		a.NoError(DecodeStr("\"a\xffb\"").Validate())
+	})
+	t.Run("Key", func(t *testing.T) {
+		d := DecodeStr("{\"a\xff\":1}")
+		d.SetUTF8Mode(UTF8Strict)
+		require.ErrorIs(t, d.Obj(func(d *Decoder, key string) error {
+			return d.Skip()
+		}), ErrInvalidUTF8)
+	})
+}
@@ -42,6 +42,11 @@ func testBufferReader(input string, cb func(t *testing.T, d *Decoder)) func(t *t
 			obr := iotest.DataErrReader(r)
 			cb(t, Decode(obr, 512))
 		})
+
+		t.Run("SmallBuffer", func(t *testing.T) {
+			r := strings.NewReader(input)
+			cb(t, Decode(r, 1))
+		})
 	}
 }
 
@@ -0,0 +1,313 @@
+package jx
+
+import (
+	"io"
+	"strconv"
+
+	"github.com/go-faster/errors"
+)
+
+// TokenKind is kind of json Token.
+type TokenKind byte
+
+// Token kinds.
+const (
+	TokenInvalid TokenKind = iota
+	// TokenObjStart is '{'.
+	TokenObjStart
+	// TokenObjEnd is '}'.
+	TokenObjEnd
+	// TokenArrStart is '['.
+	TokenArrStart
+	// TokenArrEnd is ']'.
+	TokenArrEnd
+	// TokenKey is object key.
+	TokenKey
+	// TokenString is string value.
+	TokenString
+	// TokenNumber is number value.
+	TokenNumber
+	// TokenBool is true or false.
+	TokenBool
+	// TokenNull is null.
+	TokenNull
+)
+
+func (k TokenKind) String() string {
+	switch k {
+	case TokenObjStart:
+		return "object start"
+	case TokenObjEnd:
+		return "object end"
+	case TokenArrStart:
+		return "array start"
+	case TokenArrEnd:
+		return "array end"
+	case TokenKey:
+		return "key"
+	case TokenString:
+		return "string"
+	case TokenNumber:
+		return "number"
+	case TokenBool:
+		return "bool"
+	case TokenNull:
+		return "null"
+	default:
+		return "invalid"
+	}
+}
+
+// Token is json token, returned by Decoder.Token.
+type Token struct {
+	Kind TokenKind
+	// Depth is nesting depth of token, zero for top-level value.
+	//
+	// Delimiters have depth of their object or array, so keys and values
+	// have depth of enclosing delimiters plus one.
+	Depth int
+	// Value is unescaped string of TokenKey and TokenString or literal of
+	// TokenNumber, TokenBool and TokenNull.
+	//
+	// Do not retain, it references underlying buffer.
+	Value []byte
+}
+
+// IsKey reports whether token is object key.
+func (t Token) IsKey() bool {
+	return t.Kind == TokenKey
+}
+
+// Bool reports whether token is true.
+func (t Token) Bool() bool {
+	return t.Kind == TokenBool && string(t.Value) == "true"
+}
+
+// Num returns number of TokenNumber.
+func (t Token) Num() Num {
+	return Num(t.Value)
+}
+
+func (t Token) String() string {
+	switch t.Kind {
+	case TokenKey, TokenString:
+		return strconv.Quote(string(t.Value))
+	case TokenNumber, TokenBool, TokenNull:
+		return string(t.Value)
+	case TokenObjStart:
+		return "{"
+	case TokenObjEnd:
+		return "}"
+	case TokenArrStart:
+		return "["
+	case TokenArrEnd:
+		return "]"
+	default:
+		return t.Kind.String()
+	}
+}
+
+type tokenState byte
+
+const (
+	tokenFirst tokenState = iota // first member or end
+	tokenValue                   // value after key or comma in array
+	tokenComma                   // comma or end
+)
+
+// tokenFrame is state of object or array, read by Token.
+type tokenFrame struct {
+	obj   bool
+	state tokenState
+	n     int // count of members or elements
+	pos   int // offset of expected value
+}
+
+// Token returns next json token.
+//
+// Returns io.EOF at the end of input. Multiple top-level values are
+// allowed, like in encoding/json.
+//
+// Values after TokenKey and elements of arrays can be read by other
+// methods instead, like Skip or Str, and Token continues after them:
+//
+//	for {
+//		tok, err := d.Token()
+//		if err == io.EOF {
+//			break
+//		}
+//		if tok.IsKey() && string(tok.Value) == "ignored" {
+//			if err := d.Skip(); err != nil {
+//				return err
+//			}
+//		}
+//	}
+//
+// Tokens of objects or arrays started by other methods can't be read.
+func (d *Decoder) Token() (Token, error) {
+	if len(d.tokens) == 0 {
+		return d.tokenValue(true)
+	}
+	f := &d.tokens[len(d.tokens)-1]
+	if (f.state == tokenValue || (f.state == tokenFirst && !f.obj)) && d.offset() != f.pos {
+		// Value is read by other method.
+		f.state = tokenComma
+	}
+	end := byte(']')
+	if f.obj {
+		end = '}'
+	}
+	switch f.state {
+	case tokenFirst:
+		c, err := d.more()
+		if err != nil {
+			return Token{}, err
+		}
+		if c == end {
+			return d.tokenEnd()
+		}
+		d.unread()
+		f.n++
+		if f.obj {
+			return d.tokenKey(f)
+		}
+		return d.tokenValue(false)
+	case tokenComma:
+		c, err := d.more()
+		if err != nil {
+			return Token{}, err
+		}
+		if c == end {
+			return d.tokenEnd()
+		}
+		if c != ',' {
+			err := d.badToken(c, d.offset()-1)
+			return Token{}, errors.Wrapf(err, `"," or %q expected`, end)
+		}
+		trailing, err := d.trailingComma(end)
+		if err != nil {
+			return Token{}, err
+		}
+		if trailing {
+			return d.tokenEnd()
+		}
+		f.n++
+		if f.obj {
+			if err := d.checkObjLen(f.n); err != nil {
+				return Token{}, err
+			}
+			return d.tokenKey(f)
+		}
+		if err := d.checkArrLen(f.n); err != nil {
+			return Token{}, err
+		}
+		return d.tokenValue(false)
+	default:
+		return d.tokenValue(false)
+	}
+}
+
+func (d *Decoder) tokenKey(f *tokenFrame) (Token, error) {
+	k, err := d.objKey(value{buf: d.tokenBuf[:0], raw: d.reader == nil})
+	if err != nil {
+		return Token{}, errors.Wrap(err, "field name")
+	}
+	if !k.raw {
+		d.tokenBuf = k.buf
+	}
+	if err := d.consume(':'); err != nil {
+		return Token{}, errors.Wrap(err, `":" expected`)
+	}
+	f.state = tokenValue
+	f.pos = d.offset()
+	return Token{Kind: TokenKey, Depth: d.depth, Value: k.buf}, nil
+}
+
+func (d *Decoder) tokenEnd() (Token, error) {
+	f := d.tokens[len(d.tokens)-1]
+	d.tokens = d.tokens[:len(d.tokens)-1]
+	if err := d.decDepth(); err != nil {
+		return Token{}, err
+	}
+	kind := TokenArrEnd
+	if f.obj {
+		kind = TokenObjEnd
+	}
+	return Token{Kind: kind, Depth: d.depth}, nil
+}
+
+var (
+	tokenTrue  = []byte("true")
+	tokenFalse = []byte("false")
+	tokenNull  = []byte("null")
+)
+
+// tokenValue reads token of value, top-level if top is true.
+func (d *Decoder) tokenValue(top bool) (Token, error) {
+	if !top {
+		d.tokens[len(d.tokens)-1].state = tokenComma
+	}
+	c, err := d.next()
+	if err != nil {
+		if err == io.EOF && !top {
+			err = d.unexpectedEOF()
+		}
+		return Token{}, err
+	}
+	d.unread()
+
+	depth := d.depth
+	t := types[c]
+	if d.json5 {
+		t = json5Types[c]
+	}
+	switch t {
+	case Object, Array:
+		d.head++
+		if err := d.incDepth(); err != nil {
+			return Token{}, err
+		}
+		kind := TokenArrStart
+		if t == Object {
+			kind = TokenObjStart
+			d.objStart()
+		}
+		d.tokens = append(d.tokens, tokenFrame{
+			obj: t == Object,
+			pos: d.offset(),
+		})
+		return Token{Kind: kind, Depth: depth}, nil
+	case String:
+		v, err := d.str(value{buf: d.tokenBuf[:0], raw: d.reader == nil})
+		if err != nil {
+			return Token{}, err
+		}
+		if !v.raw {
+			d.tokenBuf = v.buf
+		}
+		return Token{Kind: TokenString, Depth: depth, Value: v.buf}, nil
+	case Number:
+		n, err := d.Num()
+		if err != nil {
+			return Token{}, err
+		}
+		return Token{Kind: TokenNumber, Depth: depth, Value: n}, nil
+	case Bool:
+		v, err := d.Bool()
+		if err != nil {
+			return Token{}, err
+		}
+		tok := Token{Kind: TokenBool, Depth: depth, Value: tokenFalse}
+		if v {
+			tok.Value = tokenTrue
+		}
+		return tok, nil
+	case Null:
+		if err := d.Null(); err != nil {
+			return Token{}, err
+		}
+		return Token{Kind: TokenNull, Depth: depth, Value: tokenNull}, nil
+	default:
+		return Token{}, d.badToken(c, d.offset())
+	}
+}
@@ -0,0 +1,177 @@
+package jx
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func readTokens(d *Decoder) (string, error) {
+	var s strings.Builder
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return s.String(), nil
+		}
+		if err != nil {
+			return s.String(), err
+		}
+		if s.Len() > 0 {
+			s.WriteByte(' ')
+		}
+		fmt.Fprintf(&s, "%d:%s", tok.Depth, tok)
+		if tok.IsKey() {
+			s.WriteByte(':')
+		}
+	}
+}
+
+func TestDecoder_Token(t *testing.T) {
+	for i, tt := range []struct {
+		input  string
+		expect string
+	}{
+		{`1`, `0:1`},
+		{`"a\nb" true false null -1.5e3`, `0:"a\nb" 0:true 0:false 0:null 0:-1.5e3`},
+		{`{}`, `0:{ 0:}`},
+		{`[]`, `0:[ 0:]`},
+		{
+			`{"a": [1, {"bc": null}], "d": {}}`,
+			`0:{ 1:"a": 1:[ 2:1 2:{ 3:"bc": 3:null 2:} 1:] 1:"d": 1:{ 1:} 0:}`,
+		},
+		{`[[[]]] []`, `0:[ 1:[ 2:[ 2:] 1:] 0:] 0:[ 0:]`},
+	} {
+		tt := tt
+		t.Run(fmt.Sprintf("Test%d", i+1), testBufferReader(tt.input, func(t *testing.T, d *Decoder) {
+			got, err := readTokens(d)
+			require.NoError(t, err)
+			require.Equal(t, tt.expect, got)
+		}))
+	}
+}
+
+func TestDecoder_TokenError(t *testing.T) {
+	for _, input := range []string{
+		`{`,
+		`[`,
+		`{"a"}`,
+		`{"a":}`,
+		`{"a":1,}`,
+		`{1:1}`,
+		`[1,]`,
+		`[1 2]`,
+		`[1}`,
+		`{"a":1]`,
+		`]`,
+		`tru`,
+		`"abc`,
+	} {
+		input := input
+		t.Run(input, testBufferReader(input, func(t *testing.T, d *Decoder) {
+			_, err := readTokens(d)
+			require.Error(t, err)
+		}))
+	}
+}
+
+func TestDecoder_TokenMixed(t *testing.T) {
+	const input = `{"skip": {"a": [1, 2]}, "str": "value", "arr": [{"b": 1}, 2, "c"], "end": true}`
+	testBufferReader(input, func(t *testing.T, d *Decoder) {
+		a := require.New(t)
+		var got []string
+		for {
+			tok, err := d.Token()
+			if err == io.EOF {
+				break
+			}
+			a.NoError(err)
+			got = append(got, tok.String())
+			switch {
+			case tok.IsKey() && string(tok.Value) == "skip":
+				a.NoError(d.Skip())
+			case tok.IsKey() && string(tok.Value) == "str":
+				s, err := d.Str()
+				a.NoError(err)
+				got = append(got, s)
+			case tok.Kind == TokenArrStart:
+				// Read first element by Obj.
+				a.NoError(d.Obj(func(d *Decoder, key string) error {
+					got = append(got, key)
+					return d.Skip()
+				}))
+			}
+		}
+		a.Equal([]string{
+			`{`,
+			`"skip"`,
+			`"str"`, `value`,
+			`"arr"`, `[`, `b`, `2`, `"c"`, `]`,
+			`"end"`, `true`,
+			`}`,
+		}, got)
+	})(t)
+}
+
+func TestDecoder_TokenOptions(t *testing.T) {
+	t.Run("Limits", func(t *testing.T) {
+		a := require.New(t)
+		d := DecodeStr(`[[1]]`)
+		d.SetLimits(Limits{Depth: 1})
+		_, err := readTokens(d)
+		a.ErrorIs(err, ErrMaxDepth)
+
+		d = DecodeStr(`[1,2,3]`)
+		d.SetLimits(Limits{ArrLen: 2})
+		_, err = readTokens(d)
+		a.ErrorIs(err, ErrMaxArrLen)
+	})
+	t.Run("DuplicateKeys", func(t *testing.T) {
+		d := DecodeStr(`{"a":1,"a":2}`)
+		d.DisallowDuplicateKeys(true)
+		_, err := readTokens(d)
+		var dupErr *DuplicateKeyError
+		require.ErrorAs(t, err, &dupErr)
+	})
+	t.Run("JSON5", func(t *testing.T) {
+		d := DecodeStr(`{a: [0x10, 'b',], /* c */}`)
+		d.SetJSON5(true)
+		got, err := readTokens(d)
+		require.NoError(t, err)
+		require.Equal(t, `0:{ 1:"a": 1:[ 2:16 2:"b" 1:] 0:}`, got)
+	})
+	t.Run("Reset", func(t *testing.T) {
+		a := require.New(t)
+		d := DecodeStr(`{"a":`)
+		_, err := readTokens(d)
+		a.Error(err)
+		d.ResetBytes([]byte(`[]`))
+		got, err := readTokens(d)
+		a.NoError(err)
+		a.Equal(`0:[ 0:]`, got)
+	})
+}
+
+func TestToken(t *testing.T) {
+	a := require.New(t)
+	d := DecodeStr(`[true, false, 10]`)
+	var toks []Token
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		a.NoError(err)
+		toks = append(toks, tok)
+	}
+	a.Len(toks, 5)
+	a.True(toks[1].Bool())
+	a.False(toks[2].Bool())
+	v, err := toks[3].Num().Int64()
+	a.NoError(err)
+	a.Equal(int64(10), v)
+	a.Equal("number", toks[3].Kind.String())
+	a.Equal("invalid", Token{}.String())
+}
@@ -0,0 +1,91 @@
+package jx
+
+// Handler handles events of Decoder.Walk.
+//
+// Byte slices and Num passed to handler reference underlying buffer and
+// are valid only until method is returned.
+//
+// Returned error stops walk and is returned by Walk.
+type Handler interface {
+	OnObjectStart() error
+	OnKey(key []byte) error
+	OnObjectEnd() error
+	OnArrayStart() error
+	OnArrayEnd() error
+	OnString(v []byte) error
+	OnNumber(v Num) error
+	OnBool(v bool) error
+	OnNull() error
+}
+
+// NopHandler is Handler that does nothing.
+//
+// Embed it to implement only required methods of Handler.
+type NopHandler struct{}
+
+// OnObjectStart implements Handler.
+func (NopHandler) OnObjectStart() error { return nil }
+
+// OnKey implements Handler.
+func (NopHandler) OnKey([]byte) error { return nil }
+
+// OnObjectEnd implements Handler.
+func (NopHandler) OnObjectEnd() error { return nil }
+
+// OnArrayStart implements Handler.
+func (NopHandler) OnArrayStart() error { return nil }
+
+// OnArrayEnd implements Handler.
+func (NopHandler) OnArrayEnd() error { return nil }
+
+// OnString implements Handler.
+func (NopHandler) OnString([]byte) error { return nil }
+
+// OnNumber implements Handler.
+func (NopHandler) OnNumber(Num) error { return nil }
+
+// OnBool implements Handler.
+func (NopHandler) OnBool(bool) error { return nil }
+
+// OnNull implements Handler.
+func (NopHandler) OnNull() error { return nil }
+
+// Walk reads json value, calling methods of h for every token.
+//
+// Walk does not allocate for keys and strings when reading from byte
+// slice, except of buffer for unescaping, which is reused.
+func (d *Decoder) Walk(h Handler) error {
+	base := len(d.tokens)
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch tok.Kind {
+		case TokenObjStart:
+			err = h.OnObjectStart()
+		case TokenObjEnd:
+			err = h.OnObjectEnd()
+		case TokenArrStart:
+			err = h.OnArrayStart()
+		case TokenArrEnd:
+			err = h.OnArrayEnd()
+		case TokenKey:
+			err = h.OnKey(tok.Value)
+		case TokenString:
+			err = h.OnString(tok.Value)
+		case TokenNumber:
+			err = h.OnNumber(tok.Num())
+		case TokenBool:
+			err = h.OnBool(tok.Bool())
+		case TokenNull:
+			err = h.OnNull()
+		}
+		if err != nil {
+			return err
+		}
+		if len(d.tokens) <= base && !tok.IsKey() {
+			return nil
+		}
+	}
+}
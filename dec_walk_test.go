@@ -0,0 +1,100 @@
+package jx
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/go-faster/errors"
+	"github.com/stretchr/testify/require"
+)
+
+type recordHandler struct {
+	events []string
+}
+
+func (h *recordHandler) add(format string, args ...interface{}) error {
+	h.events = append(h.events, fmt.Sprintf(format, args...))
+	return nil
+}
+
+func (h *recordHandler) OnObjectStart() error    { return h.add("{") }
+func (h *recordHandler) OnKey(key []byte) error  { return h.add("%q:", key) }
+func (h *recordHandler) OnObjectEnd() error      { return h.add("}") }
+func (h *recordHandler) OnArrayStart() error     { return h.add("[") }
+func (h *recordHandler) OnArrayEnd() error       { return h.add("]") }
+func (h *recordHandler) OnString(v []byte) error { return h.add("%q", v) }
+func (h *recordHandler) OnNumber(v Num) error    { return h.add("%s", v) }
+func (h *recordHandler) OnBool(v bool) error     { return h.add("%v", v) }
+func (h *recordHandler) OnNull() error           { return h.add("null") }
+
+func TestDecoder_Walk(t *testing.T) {
+	const input = `{"a": [1, "b\n", true, null], "c": {"d": -0.5}} 10`
+	testBufferReader(input, func(t *testing.T, d *Decoder) {
+		a := require.New(t)
+		h := &recordHandler{}
+		a.NoError(d.Walk(h))
+		a.Equal(`{ "a": [ 1 "b\n" true null ] "c": { "d": -0.5 } }`, strings.Join(h.events, " "))
+
+		// Next top-level value.
+		h = &recordHandler{}
+		a.NoError(d.Walk(h))
+		a.Equal([]string{"10"}, h.events)
+		a.Error(d.Walk(h))
+	})(t)
+}
+
+type countHandler struct {
+	NopHandler
+	strings int
+}
+
+func (h *countHandler) OnString([]byte) error {
+	h.strings++
+	return nil
+}
+
+func TestDecoder_WalkNested(t *testing.T) {
+	a := require.New(t)
+	d := DecodeStr(`{"skip": ["a"], "walk": ["b", {"c": "d"}], "next": "e"}`)
+	h := &countHandler{}
+	for {
+		tok, err := d.Token()
+		a.NoError(err)
+		if tok.Kind == TokenObjEnd {
+			break
+		}
+		switch string(tok.Value) {
+		case "skip":
+			a.NoError(d.Skip())
+		case "walk":
+			a.NoError(d.Walk(h))
+		}
+	}
+	a.Equal(2, h.strings)
+}
+
+func TestDecoder_WalkError(t *testing.T) {
+	a := require.New(t)
+	errStop := errors.New("stop")
+	h := &stopHandler{err: errStop}
+	a.ErrorIs(DecodeStr(`[1, 2, 3]`).Walk(h), errStop)
+	a.Equal(2, h.numbers)
+
+	a.Error(DecodeStr(`[1, 2`).Walk(NopHandler{}))
+	a.Error(DecodeStr(`{"a" 1}`).Walk(NopHandler{}))
+}
+
+type stopHandler struct {
+	NopHandler
+	err     error
+	numbers int
+}
+
+func (h *stopHandler) OnNumber(Num) error {
+	h.numbers++
+	if h.numbers == 2 {
+		return h.err
+	}
+	return nil
+}
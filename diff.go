@@ -0,0 +1,248 @@
+package jx
+
+import (
+	"bytes"
+	"strconv"
+
+	"github.com/go-faster/errors"
+)
+
+// DiffKind is kind of Difference.
+type DiffKind byte
+
+// Difference kinds.
+const (
+	DiffInvalid DiffKind = iota
+	// DiffAdded means that value exists only in second document.
+	DiffAdded
+	// DiffRemoved means that value exists only in first document.
+	DiffRemoved
+	// DiffChanged means that values are different.
+	DiffChanged
+)
+
+func (k DiffKind) String() string {
+	switch k {
+	case DiffAdded:
+		return "added"
+	case DiffRemoved:
+		return "removed"
+	case DiffChanged:
+		return "changed"
+	default:
+		return "invalid"
+	}
+}
+
+// Difference is difference between two json documents.
+type Difference struct {
+	Kind DiffKind
+	// Path is JSON Pointer to value.
+	Path string
+	// From is value in first document, nil if value is added.
+	From Raw
+	// To is value in second document, nil if value is removed.
+	To Raw
+}
+
+func (d Difference) String() string {
+	switch d.Kind {
+	case DiffAdded:
+		return strconv.Quote(d.Path) + ": added " + d.To.String()
+	case DiffRemoved:
+		return strconv.Quote(d.Path) + ": removed " + d.From.String()
+	default:
+		return strconv.Quote(d.Path) + ": " + d.From.String() + " -> " + d.To.String()
+	}
+}
+
+// DiffOptions configures Diff.
+//
+// Zero value compares numbers by literals and arrays by indexes.
+type DiffOptions struct {
+	// Numeric compares numbers by value, so 1, 1.0 and 1e0 are equal.
+	// Numbers are compared exactly, without precision loss.
+	Numeric bool
+	// IgnoreArrayOrder compares arrays as multisets: elements without
+	// equal pair in other array are reported as removed or added, with
+	// indexes of first and second document respectively.
+	IgnoreArrayOrder bool
+}
+
+// errDiffStop stops comparison in equal.
+var errDiffStop = errors.New("stop")
+
+// Diff compares json documents, calling f for every difference with JSON
+// Pointer path to it.
+//
+// Objects are compared by members regardless of order, strings after
+// unescaping. Changed objects and arrays are compared recursively, so
+// only differing leaves are reported, and whole value is reported if
+// types are different.
+//
+// Values of differences reference a and b.
+func Diff(a, b []byte, opts DiffOptions, f func(d Difference) error) error {
+	if err := validateRaw(a); err != nil {
+		return errors.Wrap(err, "a")
+	}
+	if err := validateRaw(b); err != nil {
+		return errors.Wrap(err, "b")
+	}
+	c := differ{opts: opts, f: f}
+	return c.diff("", bytes.TrimSpace(a), bytes.TrimSpace(b))
+}
+
+// DiffDecoder reads value from each decoder and compares them, see Diff.
+//
+// Values of differences are copied, so decoders can be reused.
+func DiffDecoder(a, b *Decoder, opts DiffOptions, f func(d Difference) error) error {
+	x, err := a.RawAppend(nil)
+	if err != nil {
+		return errors.Wrap(err, "a")
+	}
+	y, err := b.RawAppend(nil)
+	if err != nil {
+		return errors.Wrap(err, "b")
+	}
+	c := differ{opts: opts, f: f}
+	// Raw captures whitespace before value.
+	return c.diff("", bytes.TrimSpace(x), bytes.TrimSpace(y))
+}
+
+type differ struct {
+	opts DiffOptions
+	f    func(d Difference) error
+}
+
+func (c differ) diff(path string, a, b Raw) error {
+	ta, tb := a.Type(), b.Type()
+	switch {
+	case ta != tb:
+	case ta == Object:
+		return c.obj(path, a, b)
+	case ta == Array && c.opts.IgnoreArrayOrder:
+		return c.multiset(path, a, b)
+	case ta == Array:
+		return c.arr(path, a, b)
+	case ta == Number:
+		if c.opts.Numeric && numEqual(a, b) || bytes.Equal(a, b) {
+			return nil
+		}
+	case rawEqual(a, b):
+		return nil
+	}
+	return c.f(Difference{Kind: DiffChanged, Path: path, From: a, To: b})
+}
+
+// equal reports whether values have no differences.
+func (c differ) equal(a, b Raw) bool {
+	eq := differ{
+		opts: c.opts,
+		f: func(d Difference) error {
+			return errDiffStop
+		},
+	}
+	return eq.diff("", a, b) == nil
+}
+
+func (c differ) obj(path string, a, b Raw) error {
+	aMembers, aIdx, err := rawMembers(a)
+	if err != nil {
+		return err
+	}
+	bMembers, bIdx, err := rawMembers(b)
+	if err != nil {
+		return err
+	}
+	for i, m := range aMembers {
+		if aIdx[m.key] != i {
+			continue
+		}
+		child := path + "/" + escapePointer(m.key)
+		j, ok := bIdx[m.key]
+		if !ok {
+			if err := c.f(Difference{Kind: DiffRemoved, Path: child, From: m.v}); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := c.diff(child, m.v, bMembers[j].v); err != nil {
+			return err
+		}
+	}
+	for i, m := range bMembers {
+		if _, ok := aIdx[m.key]; ok || bIdx[m.key] != i {
+			continue
+		}
+		child := path + "/" + escapePointer(m.key)
+		if err := c.f(Difference{Kind: DiffAdded, Path: child, To: m.v}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c differ) arr(path string, a, b Raw) error {
+	aElems, err := rawElems(a)
+	if err != nil {
+		return err
+	}
+	bElems, err := rawElems(b)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < len(aElems) && i < len(bElems); i++ {
+		if err := c.diff(path+"/"+strconv.Itoa(i), aElems[i], bElems[i]); err != nil {
+			return err
+		}
+	}
+	for i := len(bElems); i < len(aElems); i++ {
+		if err := c.f(Difference{Kind: DiffRemoved, Path: path + "/" + strconv.Itoa(i), From: aElems[i]}); err != nil {
+			return err
+		}
+	}
+	for i := len(aElems); i < len(bElems); i++ {
+		if err := c.f(Difference{Kind: DiffAdded, Path: path + "/" + strconv.Itoa(i), To: bElems[i]}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c differ) multiset(path string, a, b Raw) error {
+	aElems, err := rawElems(a)
+	if err != nil {
+		return err
+	}
+	bElems, err := rawElems(b)
+	if err != nil {
+		return err
+	}
+	matched := make([]bool, len(bElems))
+	for i, v := range aElems {
+		found := false
+		for j, w := range bElems {
+			// Fast path for identical elements.
+			if !matched[j] && (bytes.Equal(v, w) || c.equal(v, w)) {
+				matched[j] = true
+				found = true
+				break
+			}
+		}
+		if found {
+			continue
+		}
+		if err := c.f(Difference{Kind: DiffRemoved, Path: path + "/" + strconv.Itoa(i), From: v}); err != nil {
+			return err
+		}
+	}
+	for j, w := range bElems {
+		if matched[j] {
+			continue
+		}
+		if err := c.f(Difference{Kind: DiffAdded, Path: path + "/" + strconv.Itoa(j), To: w}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package jx
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/go-faster/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func collectDiff(t *testing.T, a, b string, opts DiffOptions) []string {
+	t.Helper()
+
+	var diffs []string
+	require.NoError(t, Diff([]byte(a), []byte(b), opts, func(d Difference) error {
+		diffs = append(diffs, d.String())
+		return nil
+	}))
+	return diffs
+}
+
+func TestDiff(t *testing.T) {
+	for i, tt := range []struct {
+		a, b   string
+		opts   DiffOptions
+		expect []string
+	}{
+		{`1`, `1`, DiffOptions{}, nil},
+		{`1`, `1.0`, DiffOptions{}, []string{`"": 1 -> 1.0`}},
+		{`1`, `1.0`, DiffOptions{Numeric: true}, nil},
+		{`1`, `2`, DiffOptions{Numeric: true}, []string{`"": 1 -> 2`}},
+		{
+			`12345678901234567890`,
+			`12345678901234567891`,
+			DiffOptions{Numeric: true},
+			[]string{`"": 12345678901234567890 -> 12345678901234567891`},
+		},
+		{`"a"`, `"a"`, DiffOptions{}, nil},
+		{`"a"`, `1`, DiffOptions{}, []string{`"": "a" -> 1`}},
+		{`null`, `false`, DiffOptions{}, []string{`"": null -> false`}},
+		{`{"a":[1]}`, `[1]`, DiffOptions{}, []string{`"": {"a":[1]} -> [1]`}},
+		{
+			` {"a": 1, "b": {"c": [1, 2]}, "d/e": true} `,
+			`{"b": {"c": [1, 3, 4]}, "a": 1, "f~": null}`,
+			DiffOptions{},
+			[]string{
+				`"/b/c/1": 2 -> 3`,
+				`"/b/c/2": added 4`,
+				`"/d~1e": removed true`,
+				`"/f~0": added null`,
+			},
+		},
+		{`[1,2,3]`, `[1]`, DiffOptions{}, []string{`"/1": removed 2`, `"/2": removed 3`}},
+		{`[3,1,2]`, `[1,2,3]`, DiffOptions{IgnoreArrayOrder: true}, nil},
+		{`[1,1,2]`, `[1,2,2]`, DiffOptions{IgnoreArrayOrder: true}, []string{`"/1": removed 1`, `"/2": added 2`}},
+		{`[1.0,{"a":[2,1]}]`, `[{"a":[1,2]},1]`, DiffOptions{IgnoreArrayOrder: true}, []string{`"/0": removed 1.0`, `"/1": added 1`}},
+		{
+			`[1.0,{"a":[2,1]}]`,
+			`[{"a":[1,2]},1]`,
+			DiffOptions{IgnoreArrayOrder: true, Numeric: true},
+			nil,
+		},
+	} {
+		tt := tt
+		t.Run(fmt.Sprintf("Test%d", i+1), func(t *testing.T) {
+			require.Equal(t, tt.expect, collectDiff(t, tt.a, tt.b, tt.opts))
+		})
+	}
+}
+
+func TestDiff_kinds(t *testing.T) {
+	var diffs []Difference
+	require.NoError(t, Diff(
+		[]byte(`{"a":1,"b":2}`),
+		[]byte(`{"b":3,"c":4}`),
+		DiffOptions{},
+		func(d Difference) error {
+			diffs = append(diffs, d)
+			return nil
+		},
+	))
+	require.Equal(t, []Difference{
+		{Kind: DiffRemoved, Path: "/a", From: Raw("1")},
+		{Kind: DiffChanged, Path: "/b", From: Raw("2"), To: Raw("3")},
+		{Kind: DiffAdded, Path: "/c", To: Raw("4")},
+	}, diffs)
+	for k, s := range map[DiffKind]string{
+		DiffInvalid: "invalid",
+		DiffAdded:   "added",
+		DiffRemoved: "removed",
+		DiffChanged: "changed",
+	} {
+		require.Equal(t, s, k.String())
+	}
+}
+
+func TestDiffDecoder(t *testing.T) {
+	a := Decode(strings.NewReader(`{"a": [1, 2]} {"b": 1}`), 4)
+	b := DecodeStr(`{"a": [1, 2.0]} {"b": 1}`)
+	var diffs []Difference
+	f := func(d Difference) error {
+		diffs = append(diffs, d)
+		return nil
+	}
+	require.NoError(t, DiffDecoder(a, b, DiffOptions{}, f))
+	require.Equal(t, []Difference{
+		{Kind: DiffChanged, Path: "/a/1", From: Raw("2"), To: Raw("2.0")},
+	}, diffs)
+
+	diffs = nil
+	require.NoError(t, DiffDecoder(a, b, DiffOptions{}, f))
+	require.Empty(t, diffs)
+
+	require.Error(t, DiffDecoder(a, b, DiffOptions{}, f))
+	require.Error(t, DiffDecoder(DecodeStr(`1`), b, DiffOptions{}, f))
+}
+
+func TestDiffDecoder_whitespace(t *testing.T) {
+	for i, tt := range []struct {
+		a, b string
+	}{
+		{" 1", "1"},
+		{"\n\t1 ", "2"},
+		{` "foo"`, `"bar" `},
+		{"\r\n[1, 2]", "[1,  3]\n"},
+		{` {"a": 1}`, `{"a": 1, "b": 2}`},
+	} {
+		tt := tt
+		t.Run(fmt.Sprintf("Test%d", i+1), func(t *testing.T) {
+			expected := collectDiff(t, tt.a, tt.b, DiffOptions{})
+			for _, bufSize := range []int{0, 1, 4} {
+				var diffs []string
+				a := Decode(strings.NewReader(tt.a), bufSize)
+				b := DecodeStr(tt.b)
+				require.NoError(t, DiffDecoder(a, b, DiffOptions{}, func(d Difference) error {
+					diffs = append(diffs, d.String())
+					return nil
+				}))
+				require.Equal(t, expected, diffs, "bufSize %d", bufSize)
+			}
+		})
+	}
+}
+
+func TestDiff_error(t *testing.T) {
+	f := func(d Difference) error { return nil }
+	require.Error(t, Diff([]byte(`{`), []byte(`{}`), DiffOptions{}, f))
+	require.Error(t, Diff([]byte(`{}`), []byte(`[1,]`), DiffOptions{}, f))
+
+	// Errors of callback are returned.
+	testErr := errors.New("test")
+	for _, opts := range []DiffOptions{{}, {IgnoreArrayOrder: true}} {
+		require.ErrorIs(t, Diff([]byte(`[{"a":1}]`), []byte(`[{"a":2}]`), opts, func(d Difference) error {
+			return testErr
+		}), testErr)
+	}
+}
+
+func TestDiff_testdata(t *testing.T) {
+	runTestdata(t.Fatal, func(name string, data []byte) {
+		require.NoError(t, Diff(data, data, DiffOptions{}, func(d Difference) error {
+			return errors.Errorf("unexpected difference %s", d)
+		}), name)
+	})
+}
@@ -15,3 +15,12 @@ func (e *Encoder) Float64(v float64) bool {
 	return e.comma() ||
 		e.w.Float64(v)
 }
+
+// Float64Canonical encodes float64 as ECMAScript Number.prototype.toString
+// does, as required by RFC 8785.
+//
+// NB: Infinities and NaN are represented as null.
+func (e *Encoder) Float64Canonical(v float64) bool {
+	return e.comma() ||
+		e.w.Float64Canonical(v)
+}
@@ -0,0 +1,152 @@
+package jx
+
+import (
+	"io"
+
+	"github.com/go-faster/errors"
+)
+
+// recordWriter writes records to streaming encoder.
+type recordWriter struct {
+	out       *Encoder // streaming encoder
+	rec       Encoder  // current record
+	buf       []byte   // formatted record
+	flushSize int
+	n         int // count of written records
+}
+
+func newRecordWriter(w io.Writer, flushSize int) recordWriter {
+	bufSize := -1
+	if flushSize > 0 {
+		bufSize = flushSize
+	}
+	return recordWriter{
+		out:       NewStreamingEncoder(w, bufSize),
+		flushSize: flushSize,
+	}
+}
+
+func (w *recordWriter) reset(out io.Writer) {
+	w.out.ResetWriter(out)
+	w.n = 0
+}
+
+func (w *recordWriter) encode(f func(e *Encoder)) []byte {
+	w.rec.Reset()
+	f(&w.rec)
+	return w.rec.Bytes()
+}
+
+// write writes record formatted by appendRecord.
+func (w *recordWriter) write(raw []byte, appendRecord func(buf, raw []byte) ([]byte, error)) error {
+	w.n++
+	buf, err := appendRecord(w.buf[:0], raw)
+	w.buf = buf
+	if err != nil {
+		return errors.Wrapf(err, "record %d", w.n)
+	}
+	if w.out.w.Raw(buf) {
+		return errors.Wrapf(w.out.w.stream.writeErr, "record %d", w.n)
+	}
+	if w.flushSize <= 0 || len(w.out.w.Buf) >= w.flushSize {
+		return w.flush()
+	}
+	return nil
+}
+
+func (w *recordWriter) flush() error {
+	if w.out.w.Flush() {
+		return errors.Wrapf(w.out.w.stream.writeErr, "record %d", w.n)
+	}
+	return nil
+}
+
+// LineWriter writes newline-delimited json records, also known as
+// NDJSON or JSON Lines.
+//
+// Each record is written as single compact line, even if indentation is
+// enabled on Encoder or raw value contains whitespace.
+type LineWriter struct {
+	w recordWriter
+}
+
+// NewLineWriter creates new LineWriter.
+//
+// If flushSize is positive, records are buffered and written to w when at
+// least flushSize bytes are buffered. Otherwise, every record is written
+// immediately.
+func NewLineWriter(w io.Writer, flushSize int) *LineWriter {
+	return &LineWriter{w: newRecordWriter(w, flushSize)}
+}
+
+// Reset resets buffered records and error, setting output writer.
+func (w *LineWriter) Reset(out io.Writer) {
+	w.w.reset(out)
+}
+
+// Encode writes record encoded by f.
+//
+// Record must be single json value. Write error is sticky, all subsequent
+// calls will return it until Reset.
+func (w *LineWriter) Encode(f func(e *Encoder)) error {
+	return w.Raw(w.w.encode(f))
+}
+
+// Raw writes raw json value as record.
+func (w *LineWriter) Raw(raw []byte) error {
+	return w.w.write(raw, appendLine)
+}
+
+// Flush writes buffered records.
+func (w *LineWriter) Flush() error {
+	return w.w.flush()
+}
+
+// Close flushes buffered records.
+//
+// Underlying writer is not closed.
+func (w *LineWriter) Close() error {
+	return w.Flush()
+}
+
+var (
+	errEmptyRecord   = errors.New("empty record")
+	errNewlineRecord = errors.New("newline in string")
+)
+
+// appendLine appends raw json to buf as line, removing whitespace outside
+// of strings.
+//
+// Returns error if raw is not single json value, so removed whitespace
+// can't join separate tokens.
+func appendLine(buf, raw []byte) ([]byte, error) {
+	start := len(buf)
+	var inStr, escaped bool
+	for _, c := range raw {
+		switch {
+		case inStr:
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inStr = false
+			case c == '\n' || c == '\r':
+				return buf, errNewlineRecord
+			}
+		case spaceSet[c] != 0:
+			continue
+		case c == '"':
+			inStr = true
+		}
+		buf = append(buf, c)
+	}
+	if len(buf) == start {
+		return buf, errEmptyRecord
+	}
+	if err := validateRaw(raw); err != nil {
+		return buf[:start], errors.Wrap(err, "invalid record")
+	}
+	return append(buf, '\n'), nil
+}
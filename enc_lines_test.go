@@ -0,0 +1,93 @@
+package jx
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/go-faster/errors"
+	"github.com/stretchr/testify/require"
+)
+
+type countWriter struct {
+	bytes.Buffer
+	writes int
+}
+
+func (w *countWriter) Write(p []byte) (int, error) {
+	w.writes++
+	return w.Buffer.Write(p)
+}
+
+func TestLineWriter(t *testing.T) {
+	record := func(e *Encoder) {
+		e.SetIdent(2)
+		e.Obj(func(e *Encoder) {
+			e.Field("msg", func(e *Encoder) {
+				e.Str("multi\nline")
+			})
+			e.Field("tags", func(e *Encoder) {
+				e.ArrStart()
+				e.Int(1)
+				e.Int(2)
+				e.ArrEnd()
+			})
+		})
+	}
+	const expect = `{"msg":"multi\nline","tags":[1,2]}` + "\n" +
+		`{"a":[1,2]}` + "\n"
+
+	t.Run("PerRecord", func(t *testing.T) {
+		a := require.New(t)
+		var out countWriter
+		w := NewLineWriter(&out, 0)
+		a.NoError(w.Encode(record))
+		a.Equal(1, out.writes)
+		a.NoError(w.Raw([]byte("{\n  \"a\": [1, 2]\n}\n")))
+		a.Equal(2, out.writes)
+		a.NoError(w.Close())
+		a.Equal(expect, out.String())
+	})
+	t.Run("Buffered", func(t *testing.T) {
+		a := require.New(t)
+		var out countWriter
+		w := NewLineWriter(&out, 1024)
+		a.NoError(w.Encode(record))
+		a.NoError(w.Raw([]byte(`{"a":[1,2]}`)))
+		a.Zero(out.writes)
+		a.NoError(w.Flush())
+		a.Equal(1, out.writes)
+		a.Equal(expect, out.String())
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		a := require.New(t)
+		var out bytes.Buffer
+		w := NewLineWriter(&out, 0)
+		a.ErrorIs(w.Encode(func(e *Encoder) {}), errEmptyRecord)
+		a.ErrorIs(w.Raw([]byte(" \n")), errEmptyRecord)
+		a.ErrorIs(w.Raw([]byte("\"a\nb\"")), errNewlineRecord)
+		for _, raw := range []string{
+			`1 2`,
+			`{"a": tru e}`,
+			`{"a": 1`,
+			`[1] [2]`,
+		} {
+			a.Error(w.Raw([]byte(raw)), raw)
+		}
+		a.NoError(w.Raw([]byte(`"a\"\n"`)))
+		a.Equal(`"a\"\n"`+"\n", out.String())
+	})
+	t.Run("WriteErr", func(t *testing.T) {
+		a := require.New(t)
+		errTest := errors.New("test")
+		w := NewLineWriter(&errWriter{err: errTest}, 0)
+		err := w.Raw([]byte(`1`))
+		a.ErrorIs(err, errTest)
+		a.Contains(err.Error(), "record 1")
+		a.ErrorIs(w.Raw([]byte(`2`)), errTest)
+
+		var out bytes.Buffer
+		w.Reset(&out)
+		a.NoError(w.Raw([]byte(`3`)))
+		a.Equal("3\n", out.String())
+	})
+}
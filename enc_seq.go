@@ -0,0 +1,78 @@
+package jx
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/go-faster/errors"
+)
+
+var errRSRecord = errors.New("record separator in record")
+
+// SeqWriter writes json text sequence (application/json-seq), as defined
+// by RFC 7464.
+//
+// Each record is prefixed with record separator (0x1E) and terminated by
+// line feed.
+type SeqWriter struct {
+	w recordWriter
+}
+
+// NewSeqWriter creates new SeqWriter.
+//
+// If flushSize is positive, records are buffered and written to w when at
+// least flushSize bytes are buffered. Otherwise, every record is written
+// immediately.
+func NewSeqWriter(w io.Writer, flushSize int) *SeqWriter {
+	return &SeqWriter{w: newRecordWriter(w, flushSize)}
+}
+
+// Reset resets buffered records and error, setting output writer.
+func (w *SeqWriter) Reset(out io.Writer) {
+	w.w.reset(out)
+}
+
+// Encode writes record encoded by f.
+//
+// Record must be single json value. Write error is sticky, all subsequent
+// calls will return it until Reset.
+func (w *SeqWriter) Encode(f func(e *Encoder)) error {
+	return w.Raw(w.w.encode(f))
+}
+
+// Raw writes raw json value as record.
+func (w *SeqWriter) Raw(raw []byte) error {
+	return w.w.write(raw, appendSeq)
+}
+
+// Flush writes buffered records.
+func (w *SeqWriter) Flush() error {
+	return w.w.flush()
+}
+
+// Close flushes buffered records.
+//
+// Underlying writer is not closed.
+func (w *SeqWriter) Close() error {
+	return w.Flush()
+}
+
+// appendSeq appends raw json to buf as json text sequence record.
+//
+// Returns error if raw is not single json value, so reader can't
+// misinterpret record.
+func appendSeq(buf, raw []byte) ([]byte, error) {
+	raw = bytes.TrimRight(raw, " \t\r\n")
+	switch {
+	case isBlank(raw):
+		return buf, errEmptyRecord
+	case bytes.IndexByte(raw, seqRS) >= 0:
+		return buf, errRSRecord
+	}
+	if err := validateRaw(raw); err != nil {
+		return buf, errors.Wrap(err, "invalid record")
+	}
+	buf = append(buf, seqRS)
+	buf = append(buf, raw...)
+	return append(buf, '\n'), nil
+}
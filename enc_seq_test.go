@@ -0,0 +1,39 @@
+package jx
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeqWriter(t *testing.T) {
+	a := require.New(t)
+	var out bytes.Buffer
+	w := NewSeqWriter(&out, 1024)
+	a.NoError(w.Encode(func(e *Encoder) {
+		e.SetIdent(2)
+		e.ArrStart()
+		e.Int(1)
+		e.ArrEnd()
+	}))
+	a.NoError(w.Raw([]byte("123 \n")))
+	a.ErrorIs(w.Raw([]byte(" ")), errEmptyRecord)
+	a.ErrorIs(w.Raw([]byte("[\x1e]")), errRSRecord)
+	a.Error(w.Raw([]byte("1 2")))
+	a.Error(w.Raw([]byte("[1")))
+	a.Error(w.Raw([]byte("{}}")))
+	a.Zero(out.Len())
+	a.NoError(w.Close())
+	a.Equal("\x1e[\n  1\n]\n\x1e123\n", out.String())
+
+	// Written sequence can be read back.
+	sr := NewSeqReader(&out)
+	var n int
+	for sr.Next() {
+		a.NoError(sr.Validate())
+		n++
+	}
+	a.NoError(sr.Err())
+	a.Equal(2, n)
+}
@@ -3,6 +3,8 @@ package jx_test
 import (
 	"fmt"
 
+	"github.com/go-faster/errors"
+
 	"github.com/go-faster/jx"
 )
 
@@ -195,3 +197,66 @@ func ExampleEncoder_SetIdent() {
 	//   ]
 	// }
 }
+
+func ExampleAny_Field() {
+	v, err := jx.DecodeStr(`{"foo": [1, "bar"]}`).Any()
+	if err != nil {
+		panic(err)
+	}
+	foo, err := v.Field("foo")
+	if err != nil {
+		panic(err)
+	}
+	bar, err := foo.Index(1)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(bar.AsStr())
+	// Output:
+	// bar <nil>
+}
+
+func ExampleDecoder_Seek() {
+	d := jx.DecodeStr(`{"spec": {"containers": [{"image": "nginx"}]}}`)
+	if err := d.Seek("/spec/containers/0/image"); err != nil {
+		panic(err)
+	}
+	fmt.Println(d.Str())
+	// Output:
+	// nginx <nil>
+}
+
+func ExampleJSONPath_Query() {
+	p, err := jx.CompileJSONPath(`$.items[?@.price < 10].name`)
+	if err != nil {
+		panic(err)
+	}
+	d := jx.DecodeStr(`{"items": [{"name": "foo", "price": 5}, {"name": "bar", "price": 15}]}`)
+	if err := p.Query(d, func(v jx.Raw) error {
+		fmt.Println(v)
+		return nil
+	}); err != nil {
+		panic(err)
+	}
+	// Output:
+	// "foo"
+}
+
+func ExampleDecodeError() {
+	d := jx.DecodeStr(`{"items":[{"price":10},{"price":"free"}]}`)
+	err := d.Obj(func(d *jx.Decoder, key string) error {
+		return d.Arr(func(d *jx.Decoder) error {
+			return d.Obj(func(d *jx.Decoder, key string) error {
+				_, err := d.Int()
+				return err
+			})
+		})
+	})
+
+	var e *jx.DecodeError
+	if errors.As(err, &e) {
+		fmt.Println(e.Path(), e.Pointer(), e.Expected, e.Actual, e.Offset)
+	}
+	// Output:
+	// $.items[1].price /items/1/price number string 32
+}
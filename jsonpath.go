@@ -0,0 +1,355 @@
+package jx
+
+import "github.com/go-faster/errors"
+
+// JSONPath is compiled JSONPath query, as defined in RFC 9535.
+//
+// Function extensions are not supported.
+type JSONPath struct {
+	query    string
+	segments []pathSegment
+	root     bool // some filter references root node
+}
+
+// CompileJSONPath parses JSONPath query, like "$.store.book[?@.price < 10].title".
+func CompileJSONPath(query string) (*JSONPath, error) {
+	p := pathParser{s: query}
+	segments, err := p.query()
+	if err != nil {
+		return nil, errors.Wrapf(err, "parse %q", query)
+	}
+	return &JSONPath{
+		query:    query,
+		segments: segments,
+		root:     p.root,
+	}, nil
+}
+
+// String returns source query.
+func (p *JSONPath) String() string { return p.query }
+
+// Query reads value from d and calls f for each node matched by p,
+// in order of resulting node list.
+//
+// Values that are not matched are skipped without decoding. Subtrees
+// are buffered only when query requires it: descendant segments, negative
+// indices and slices, multiple selectors in one segment and filters.
+// If any filter references root node, the whole value is buffered.
+//
+// Do not retain Raw passed to f, it may reference underlying buffer.
+func (p *JSONPath) Query(d *Decoder, f func(v Raw) error) error {
+	e := pathEval{yield: f}
+	if !p.root {
+		return e.eval(d, p.segments)
+	}
+	raw, err := d.Raw()
+	if err != nil {
+		return err
+	}
+	e.root = raw
+	return e.evalRaw(raw, p.segments)
+}
+
+type pathSelectorKind byte
+
+const (
+	pathSelName pathSelectorKind = iota
+	pathSelWildcard
+	pathSelIndex
+	pathSelSlice
+	pathSelFilter
+)
+
+type pathSelector struct {
+	kind   pathSelectorKind
+	name   string      // pathSelName
+	index  int         // pathSelIndex
+	slice  pathSliceOp // pathSelSlice
+	filter *pathFilter // pathSelFilter
+}
+
+// streamable reports whether selector can be applied without buffering
+// the whole node.
+func (s *pathSelector) streamable() bool {
+	switch s.kind {
+	case pathSelIndex:
+		return s.index >= 0
+	case pathSelSlice:
+		return s.slice.streamable()
+	default:
+		return true
+	}
+}
+
+func (s *pathSelector) matchKey(key []byte) bool {
+	switch s.kind {
+	case pathSelName:
+		return string(key) == s.name
+	case pathSelWildcard, pathSelFilter:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s *pathSelector) matchIndex(i int) bool {
+	switch s.kind {
+	case pathSelIndex:
+		return i == s.index
+	case pathSelSlice:
+		return s.slice.match(i)
+	case pathSelWildcard, pathSelFilter:
+		return true
+	default:
+		return false
+	}
+}
+
+type pathSliceOp struct {
+	start, end, step int
+	hasStart, hasEnd bool
+}
+
+func (s pathSliceOp) streamable() bool {
+	return s.step > 0 && s.start >= 0 && (!s.hasEnd || s.end >= 0)
+}
+
+// match reports whether index i is selected, valid only if slice is
+// streamable.
+func (s pathSliceOp) match(i int) bool {
+	if i < s.start || (s.hasEnd && i >= s.end) {
+		return false
+	}
+	return (i-s.start)%s.step == 0
+}
+
+// indices returns selected indices for array of length n in order of selection.
+func (s pathSliceOp) indices(n int) (r []int) {
+	if s.step == 0 {
+		return nil
+	}
+	normalize := func(i int) int {
+		if i >= 0 {
+			return i
+		}
+		return n + i
+	}
+	clamp := func(i, lo, hi int) int {
+		if i < lo {
+			return lo
+		}
+		if i > hi {
+			return hi
+		}
+		return i
+	}
+	start, end := 0, n
+	if s.step < 0 {
+		start, end = n-1, -n-1
+	}
+	if s.hasStart {
+		start = s.start
+	}
+	if s.hasEnd {
+		end = s.end
+	}
+	start, end = normalize(start), normalize(end)
+	if s.step > 0 {
+		lower, upper := clamp(start, 0, n), clamp(end, 0, n)
+		for i := lower; i < upper; i += s.step {
+			r = append(r, i)
+		}
+		return r
+	}
+	upper, lower := clamp(start, -1, n-1), clamp(end, -1, n-1)
+	for i := upper; lower < i; i += s.step {
+		r = append(r, i)
+	}
+	return r
+}
+
+type pathSegment struct {
+	descendant bool
+	selectors  []pathSelector
+}
+
+func (s *pathSegment) streamable() bool {
+	return !s.descendant && len(s.selectors) == 1 && s.selectors[0].streamable()
+}
+
+// singular reports whether segment selects at most one node.
+func (s *pathSegment) singular() bool {
+	if s.descendant || len(s.selectors) != 1 {
+		return false
+	}
+	k := s.selectors[0].kind
+	return k == pathSelName || k == pathSelIndex
+}
+
+type pathEval struct {
+	root  Raw // set only if needed by filters
+	yield func(v Raw) error
+}
+
+// eval applies segments to the value in d, consuming it.
+func (e *pathEval) eval(d *Decoder, segments []pathSegment) error {
+	if len(segments) == 0 {
+		raw, err := d.Raw()
+		if err != nil {
+			return err
+		}
+		return e.yield(raw)
+	}
+	seg := &segments[0]
+	if !seg.streamable() {
+		raw, err := d.Raw()
+		if err != nil {
+			return err
+		}
+		return e.evalRaw(raw, segments)
+	}
+	var (
+		sel  = &seg.selectors[0]
+		rest = segments[1:]
+	)
+	switch d.Next() {
+	case Object:
+		if sel.kind == pathSelIndex || sel.kind == pathSelSlice {
+			return d.Skip()
+		}
+		return d.ObjBytes(func(d *Decoder, key []byte) error {
+			return e.evalChild(d, sel, rest, sel.matchKey(key))
+		})
+	case Array:
+		if sel.kind == pathSelName {
+			return d.Skip()
+		}
+		i := 0
+		return d.Arr(func(d *Decoder) error {
+			match := sel.matchIndex(i)
+			i++
+			return e.evalChild(d, sel, rest, match)
+		})
+	default:
+		return d.Skip()
+	}
+}
+
+func (e *pathEval) evalChild(d *Decoder, sel *pathSelector, rest []pathSegment, match bool) error {
+	if !match {
+		return d.Skip()
+	}
+	if sel.kind != pathSelFilter {
+		return e.eval(d, rest)
+	}
+	raw, err := d.Raw()
+	if err != nil {
+		return err
+	}
+	ok, err := e.test(raw, sel.filter)
+	if err != nil || !ok {
+		return err
+	}
+	return e.evalRaw(raw, rest)
+}
+
+// evalRaw applies segments to buffered value.
+func (e *pathEval) evalRaw(raw Raw, segments []pathSegment) error {
+	if len(segments) == 0 {
+		return e.yield(raw)
+	}
+	seg := &segments[0]
+	next := func(v Raw) error {
+		return e.evalRaw(v, segments[1:])
+	}
+	for i := range seg.selectors {
+		if err := e.selectRaw(raw, &seg.selectors[i], next); err != nil {
+			return err
+		}
+	}
+	if !seg.descendant {
+		return nil
+	}
+	return rawChildren(raw, func(_ []byte, v Raw) error {
+		return e.evalRaw(v, segments)
+	})
+}
+
+func (e *pathEval) selectRaw(raw Raw, sel *pathSelector, f func(v Raw) error) error {
+	switch sel.kind {
+	case pathSelName, pathSelWildcard:
+		if raw.Type() != Object && sel.kind == pathSelName {
+			return nil
+		}
+		return rawChildren(raw, func(key []byte, v Raw) error {
+			if !sel.matchKey(key) {
+				return nil
+			}
+			return f(v)
+		})
+	case pathSelFilter:
+		return rawChildren(raw, func(_ []byte, v Raw) error {
+			ok, err := e.test(v, sel.filter)
+			if err != nil || !ok {
+				return err
+			}
+			return f(v)
+		})
+	case pathSelIndex, pathSelSlice:
+		if raw.Type() != Array {
+			return nil
+		}
+		var elems []Raw
+		if err := rawChildren(raw, func(_ []byte, v Raw) error {
+			elems = append(elems, v)
+			return nil
+		}); err != nil {
+			return err
+		}
+		if sel.kind == pathSelIndex {
+			idx := sel.index
+			if idx < 0 {
+				idx += len(elems)
+			}
+			if idx < 0 || idx >= len(elems) {
+				return nil
+			}
+			return f(elems[idx])
+		}
+		for _, idx := range sel.slice.indices(len(elems)) {
+			if err := f(elems[idx]); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return errors.Errorf("unexpected selector %d", sel.kind)
+	}
+}
+
+// rawChildren calls f for every member of object or element of array.
+//
+// Key is nil for array elements.
+func rawChildren(raw Raw, f func(key []byte, v Raw) error) error {
+	d := DecodeBytes(raw)
+	switch d.Next() {
+	case Object:
+		return d.ObjBytes(func(d *Decoder, key []byte) error {
+			v, err := d.Raw()
+			if err != nil {
+				return err
+			}
+			return f(key, v)
+		})
+	case Array:
+		return d.Arr(func(d *Decoder) error {
+			v, err := d.Raw()
+			if err != nil {
+				return err
+			}
+			return f(nil, v)
+		})
+	default:
+		return nil
+	}
+}
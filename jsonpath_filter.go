@@ -0,0 +1,457 @@
+package jx
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/go-faster/errors"
+)
+
+type pathFilterOp byte
+
+const (
+	filterOr pathFilterOp = iota
+	filterAnd
+	filterNot
+	filterExists
+	filterCmp
+)
+
+type pathCmpOp byte
+
+const (
+	cmpEq pathCmpOp = iota
+	cmpNe
+	cmpLt
+	cmpLe
+	cmpGt
+	cmpGe
+)
+
+// pathFilter is logical expression of filter selector.
+type pathFilter struct {
+	op          pathFilterOp
+	left, right *pathFilter // filterOr, filterAnd, filterNot (left only)
+
+	query *pathFilterQuery // filterExists
+
+	cmp  pathCmpOp // filterCmp
+	a, b pathComparable
+}
+
+// pathFilterQuery is relative (@) or absolute ($) query in filter.
+type pathFilterQuery struct {
+	root     bool
+	segments []pathSegment
+}
+
+// pathComparable is literal or singular query.
+type pathComparable struct {
+	literal Raw
+	query   *pathFilterQuery
+}
+
+func (p *pathParser) logicalOr() (*pathFilter, error) {
+	return p.logical("||", filterOr, p.logicalAnd)
+}
+
+func (p *pathParser) logicalAnd() (*pathFilter, error) {
+	return p.logical("&&", filterAnd, p.basicExpr)
+}
+
+func (p *pathParser) logical(
+	token string,
+	op pathFilterOp,
+	operand func() (*pathFilter, error),
+) (*pathFilter, error) {
+	left, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		save := p.pos
+		p.skipSpace()
+		if !strings.HasPrefix(p.s[p.pos:], token) {
+			p.pos = save
+			return left, nil
+		}
+		p.pos += len(token)
+		p.skipSpace()
+		right, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		left = &pathFilter{op: op, left: left, right: right}
+	}
+}
+
+func (p *pathParser) basicExpr() (*pathFilter, error) {
+	if p.peek() == '!' {
+		p.pos++
+		p.skipSpace()
+		var (
+			f   *pathFilter
+			err error
+		)
+		switch p.peek() {
+		case '(':
+			f, err = p.parenExpr()
+		case '@', '$':
+			var q *pathFilterQuery
+			q, err = p.filterQuery()
+			f = &pathFilter{op: filterExists, query: q}
+		default:
+			return nil, p.unexpected(`"(" or query`)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &pathFilter{op: filterNot, left: f}, nil
+	}
+	if p.peek() == '(' {
+		return p.parenExpr()
+	}
+
+	start := p.pos
+	a, err := p.comparable(false)
+	if err != nil {
+		return nil, err
+	}
+	save := p.pos
+	p.skipSpace()
+	op, ok := p.cmpOp()
+	if !ok {
+		if a.query == nil {
+			return nil, p.unexpected("comparison operator")
+		}
+		p.pos = save
+		return &pathFilter{op: filterExists, query: a.query}, nil
+	}
+	if a.query != nil && !isSingular(a.query) {
+		return nil, errors.Errorf("at %d: non-singular query in comparison", start)
+	}
+	p.skipSpace()
+	b, err := p.comparable(true)
+	if err != nil {
+		return nil, err
+	}
+	return &pathFilter{op: filterCmp, cmp: op, a: a, b: b}, nil
+}
+
+func (p *pathParser) parenExpr() (*pathFilter, error) {
+	if err := p.expect('('); err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	f, err := p.logicalOr()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if err := p.expect(')'); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (p *pathParser) cmpOp() (pathCmpOp, bool) {
+	for _, op := range []struct {
+		token string
+		op    pathCmpOp
+	}{
+		{"==", cmpEq},
+		{"!=", cmpNe},
+		{"<=", cmpLe},
+		{">=", cmpGe},
+		{"<", cmpLt},
+		{">", cmpGt},
+	} {
+		if strings.HasPrefix(p.s[p.pos:], op.token) {
+			p.pos += len(op.token)
+			return op.op, true
+		}
+	}
+	return 0, false
+}
+
+func (p *pathParser) filterQuery() (*pathFilterQuery, error) {
+	q := &pathFilterQuery{root: p.peek() == '$'}
+	if q.root {
+		p.root = true
+	}
+	p.pos++
+	segments, err := p.segments()
+	if err != nil {
+		return nil, err
+	}
+	q.segments = segments
+	return q, nil
+}
+
+func isSingular(q *pathFilterQuery) bool {
+	for i := range q.segments {
+		if !q.segments[i].singular() {
+			return false
+		}
+	}
+	return true
+}
+
+// comparable parses literal or query. If singular is true, query must be singular.
+func (p *pathParser) comparable(singular bool) (pathComparable, error) {
+	start := p.pos
+	switch c := p.peek(); {
+	case c == '@' || c == '$':
+		q, err := p.filterQuery()
+		if err != nil {
+			return pathComparable{}, err
+		}
+		if singular && !isSingular(q) {
+			return pathComparable{}, errors.Errorf("at %d: non-singular query in comparison", start)
+		}
+		return pathComparable{query: q}, nil
+	case c == '\'' || c == '"':
+		s, err := p.str()
+		if err != nil {
+			return pathComparable{}, err
+		}
+		var w Writer
+		w.Str(s)
+		return pathComparable{literal: w.Buf}, nil
+	case isPathIntStart(c):
+		return p.number()
+	}
+	for _, lit := range []string{"true", "false", "null"} {
+		if strings.HasPrefix(p.s[p.pos:], lit) {
+			p.pos += len(lit)
+			return pathComparable{literal: Raw(lit)}, nil
+		}
+	}
+	if p.eof() {
+		return pathComparable{}, p.unexpected("comparable")
+	}
+	if _, err := p.shorthand(); err == nil && p.peek() == '(' {
+		return pathComparable{}, errors.Errorf("at %d: function extensions are not supported", start)
+	}
+	p.pos = start
+	return pathComparable{}, p.unexpected("comparable")
+}
+
+// number parses number literal, which is json number that also allows "-0".
+func (p *pathParser) number() (pathComparable, error) {
+	start := p.pos
+	if p.peek() == '-' {
+		p.pos++
+	}
+	digits := func() int {
+		n := 0
+		for !p.eof() && p.s[p.pos] >= '0' && p.s[p.pos] <= '9' {
+			p.pos++
+			n++
+		}
+		return n
+	}
+	intStart := p.pos
+	switch n := digits(); {
+	case n == 0:
+		return pathComparable{}, p.unexpected("digit")
+	case n > 1 && p.s[intStart] == '0':
+		return pathComparable{}, errors.Errorf("at %d: leading zero in number", start)
+	}
+	if p.peek() == '.' {
+		p.pos++
+		if digits() == 0 {
+			return pathComparable{}, p.unexpected("digit")
+		}
+	}
+	if c := p.peek(); c == 'e' || c == 'E' {
+		p.pos++
+		if c := p.peek(); c == '+' || c == '-' {
+			p.pos++
+		}
+		if digits() == 0 {
+			return pathComparable{}, p.unexpected("digit")
+		}
+	}
+	return pathComparable{literal: Raw(p.s[start:p.pos])}, nil
+}
+
+// errPathStop stops query evaluation.
+var errPathStop = errors.New("stop")
+
+// test evaluates filter against node.
+func (e *pathEval) test(node Raw, f *pathFilter) (bool, error) {
+	switch f.op {
+	case filterOr, filterAnd:
+		ok, err := e.test(node, f.left)
+		if err != nil || ok == (f.op == filterOr) {
+			return ok, err
+		}
+		return e.test(node, f.right)
+	case filterNot:
+		ok, err := e.test(node, f.left)
+		return !ok, err
+	case filterExists:
+		v, err := e.first(node, f.query)
+		return v != nil, err
+	case filterCmp:
+		a, err := e.comparable(node, f.a)
+		if err != nil {
+			return false, err
+		}
+		b, err := e.comparable(node, f.b)
+		if err != nil {
+			return false, err
+		}
+		return pathCompare(f.cmp, a, b), nil
+	default:
+		return false, errors.Errorf("unexpected filter %d", f.op)
+	}
+}
+
+// first returns first node selected by query or nil.
+func (e *pathEval) first(node Raw, q *pathFilterQuery) (Raw, error) {
+	var r Raw
+	sub := pathEval{
+		root: e.root,
+		yield: func(v Raw) error {
+			r = v
+			return errPathStop
+		},
+	}
+	if q.root {
+		node = e.root
+	}
+	if err := sub.evalRaw(node, q.segments); err != nil && !errors.Is(err, errPathStop) {
+		return nil, err
+	}
+	return r, nil
+}
+
+// comparable returns value of comparable or nil if query selected nothing.
+func (e *pathEval) comparable(node Raw, c pathComparable) (Raw, error) {
+	if c.query == nil {
+		return c.literal, nil
+	}
+	return e.first(node, c.query)
+}
+
+// pathCompare compares two values, nil value means empty node list.
+func pathCompare(op pathCmpOp, a, b Raw) bool {
+	switch op {
+	case cmpEq:
+		return pathEqual(a, b)
+	case cmpNe:
+		return !pathEqual(a, b)
+	case cmpLt:
+		return pathLess(a, b)
+	case cmpLe:
+		return pathLess(a, b) || pathEqual(a, b)
+	case cmpGt:
+		return pathLess(b, a)
+	case cmpGe:
+		return pathLess(b, a) || pathEqual(a, b)
+	default:
+		return false
+	}
+}
+
+func pathEqual(a, b Raw) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	at, bt := a.Type(), b.Type()
+	if at != bt {
+		return false
+	}
+	switch at {
+	case Number:
+		return pathNumCmp(Num(a), Num(b)) == 0
+	case String:
+		as, aErr := DecodeBytes(a).StrBytes()
+		bs, bErr := DecodeBytes(b).StrBytes()
+		return aErr == nil && bErr == nil && bytes.Equal(as, bs)
+	case Null:
+		return true
+	case Bool:
+		return bytes.Equal(bytes.TrimSpace(a), bytes.TrimSpace(b))
+	default:
+		av, aErr := DecodeBytes(a).Any()
+		bv, bErr := DecodeBytes(b).Any()
+		return aErr == nil && bErr == nil && pathAnyEqual(av, bv)
+	}
+}
+
+func pathLess(a, b Raw) bool {
+	if a == nil || b == nil {
+		return false
+	}
+	at, bt := a.Type(), b.Type()
+	if at != bt {
+		return false
+	}
+	switch at {
+	case Number:
+		return pathNumCmp(Num(a), Num(b)) < 0
+	case String:
+		as, aErr := DecodeBytes(a).Str()
+		bs, bErr := DecodeBytes(b).Str()
+		// Byte order of UTF-8 strings matches order of code points.
+		return aErr == nil && bErr == nil && as < bs
+	default:
+		return false
+	}
+}
+
+// pathNumCmp compares numbers numerically.
+func pathNumCmp(a, b Num) int {
+	af, aErr := a.Float64()
+	bf, bErr := b.Float64()
+	if aErr != nil || bErr != nil {
+		return bytes.Compare(a, b)
+	}
+	switch {
+	case af < bf:
+		return -1
+	case af > bf:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// pathAnyEqual reports whether values are equal, ignoring order of
+// object members and format of numbers.
+func pathAnyEqual(a, b Any) bool {
+	if a.Type != b.Type || len(a.Child) != len(b.Child) {
+		return false
+	}
+	switch a.Type {
+	case AnyNumber:
+		return pathNumCmp(a.Number, b.Number) == 0
+	case AnyStr:
+		return a.Str == b.Str
+	case AnyBool:
+		return a.Bool == b.Bool
+	case AnyArr:
+		for i := range a.Child {
+			if !pathAnyEqual(a.Child[i], b.Child[i]) {
+				return false
+			}
+		}
+		return true
+	case AnyObj:
+	Fields:
+		for _, ac := range a.Child {
+			for _, bc := range b.Child {
+				if ac.Key == bc.Key && pathAnyEqual(ac, bc) {
+					continue Fields
+				}
+			}
+			return false
+		}
+		return true
+	default:
+		return true
+	}
+}
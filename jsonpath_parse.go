@@ -0,0 +1,348 @@
+package jx
+
+import (
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/go-faster/errors"
+)
+
+// pathParser parses JSONPath query, see RFC 9535, section 2.
+type pathParser struct {
+	s    string
+	pos  int
+	root bool // root identifier used in filter
+}
+
+// maxPathInt is maximum absolute value of integer in query, as defined
+// by I-JSON (RFC 7493).
+const maxPathInt = 1<<53 - 1
+
+func (p *pathParser) errorf(format string, args ...interface{}) error {
+	return errors.Wrapf(errors.Errorf(format, args...), "at %d", p.pos)
+}
+
+func (p *pathParser) eof() bool { return p.pos >= len(p.s) }
+
+func (p *pathParser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+	return p.s[p.pos]
+}
+
+func (p *pathParser) skipSpace() {
+	for !p.eof() && spaceSet[p.s[p.pos]] != 0 {
+		p.pos++
+	}
+}
+
+func (p *pathParser) expect(c byte) error {
+	if p.peek() != c {
+		return p.unexpected(string(c))
+	}
+	p.pos++
+	return nil
+}
+
+func (p *pathParser) unexpected(expected string) error {
+	if p.eof() {
+		return p.errorf("unexpected end of query, expected %s", expected)
+	}
+	return p.errorf("unexpected %q, expected %s", p.s[p.pos], expected)
+}
+
+func (p *pathParser) query() ([]pathSegment, error) {
+	if !utf8.ValidString(p.s) {
+		return nil, errors.New("invalid utf-8")
+	}
+	if err := p.expect('$'); err != nil {
+		return nil, err
+	}
+	segments, err := p.segments()
+	if err != nil {
+		return nil, err
+	}
+	if !p.eof() {
+		return nil, p.unexpected("segment")
+	}
+	return segments, nil
+}
+
+func (p *pathParser) segments() (r []pathSegment, _ error) {
+	for {
+		save := p.pos
+		p.skipSpace()
+		switch p.peek() {
+		case '[':
+			sels, err := p.bracketed()
+			if err != nil {
+				return nil, err
+			}
+			r = append(r, pathSegment{selectors: sels})
+		case '.':
+			p.pos++
+			descendant := p.peek() == '.'
+			if descendant {
+				p.pos++
+			}
+			var (
+				sel pathSelector
+				err error
+			)
+			switch c := p.peek(); {
+			case c == '*':
+				p.pos++
+				sel.kind = pathSelWildcard
+			case descendant && c == '[':
+				sels, err := p.bracketed()
+				if err != nil {
+					return nil, err
+				}
+				r = append(r, pathSegment{descendant: true, selectors: sels})
+				continue
+			default:
+				sel.kind = pathSelName
+				if sel.name, err = p.shorthand(); err != nil {
+					return nil, err
+				}
+			}
+			r = append(r, pathSegment{descendant: descendant, selectors: []pathSelector{sel}})
+		default:
+			p.pos = save
+			return r, nil
+		}
+	}
+}
+
+func (p *pathParser) shorthand() (string, error) {
+	start := p.pos
+	for !p.eof() {
+		c, size := utf8.DecodeRuneInString(p.s[p.pos:])
+		isFirst := c == '_' || c >= 0x80 ||
+			(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+		isDigit := c >= '0' && c <= '9'
+		if !isFirst && (!isDigit || p.pos == start) {
+			break
+		}
+		p.pos += size
+	}
+	if p.pos == start {
+		return "", p.unexpected("member name")
+	}
+	return p.s[start:p.pos], nil
+}
+
+func (p *pathParser) bracketed() (r []pathSelector, _ error) {
+	if err := p.expect('['); err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpace()
+		sel, err := p.selector()
+		if err != nil {
+			return nil, err
+		}
+		r = append(r, sel)
+		p.skipSpace()
+		switch p.peek() {
+		case ']':
+			p.pos++
+			return r, nil
+		case ',':
+			p.pos++
+		default:
+			return nil, p.unexpected(`"," or "]"`)
+		}
+	}
+}
+
+func (p *pathParser) selector() (pathSelector, error) {
+	switch c := p.peek(); c {
+	case '\'', '"':
+		name, err := p.str()
+		if err != nil {
+			return pathSelector{}, err
+		}
+		return pathSelector{kind: pathSelName, name: name}, nil
+	case '*':
+		p.pos++
+		return pathSelector{kind: pathSelWildcard}, nil
+	case '?':
+		p.pos++
+		p.skipSpace()
+		f, err := p.logicalOr()
+		if err != nil {
+			return pathSelector{}, err
+		}
+		return pathSelector{kind: pathSelFilter, filter: f}, nil
+	}
+
+	var (
+		s   pathSliceOp
+		err error
+	)
+	if isPathIntStart(p.peek()) {
+		if s.start, err = p.int(); err != nil {
+			return pathSelector{}, err
+		}
+		s.hasStart = true
+		p.skipSpace()
+	}
+	if p.peek() != ':' {
+		if !s.hasStart {
+			return pathSelector{}, p.unexpected("selector")
+		}
+		return pathSelector{kind: pathSelIndex, index: s.start}, nil
+	}
+	p.pos++
+	p.skipSpace()
+	if isPathIntStart(p.peek()) {
+		if s.end, err = p.int(); err != nil {
+			return pathSelector{}, err
+		}
+		s.hasEnd = true
+		p.skipSpace()
+	}
+	s.step = 1
+	if p.peek() == ':' {
+		p.pos++
+		p.skipSpace()
+		if isPathIntStart(p.peek()) {
+			if s.step, err = p.int(); err != nil {
+				return pathSelector{}, err
+			}
+		}
+	}
+	return pathSelector{kind: pathSelSlice, slice: s}, nil
+}
+
+func isPathIntStart(c byte) bool {
+	return c == '-' || (c >= '0' && c <= '9')
+}
+
+func (p *pathParser) int() (int, error) {
+	start := p.pos
+	neg := p.peek() == '-'
+	if neg {
+		p.pos++
+	}
+	digits := p.pos
+	for !p.eof() && p.s[p.pos] >= '0' && p.s[p.pos] <= '9' {
+		p.pos++
+	}
+	lit := p.s[digits:p.pos]
+	switch {
+	case lit == "":
+		return 0, p.unexpected("digit")
+	case lit[0] == '0' && (len(lit) > 1 || neg):
+		return 0, errors.Errorf("at %d: invalid integer %q", start, p.s[start:p.pos])
+	case len(lit) > 16:
+		return 0, errors.Errorf("at %d: integer %q out of range", start, p.s[start:p.pos])
+	}
+	var v int64
+	for _, c := range []byte(lit) {
+		v = v*10 + int64(c-'0')
+	}
+	// Integer also must fit int on 32-bit platforms.
+	if v > maxPathInt || int64(int(v)) != v {
+		return 0, errors.Errorf("at %d: integer %q out of range", start, p.s[start:p.pos])
+	}
+	if neg {
+		v = -v
+	}
+	return int(v), nil
+}
+
+// str parses string literal.
+func (p *pathParser) str() (string, error) {
+	quote := p.s[p.pos]
+	p.pos++
+	var b strings.Builder
+	for {
+		if p.eof() {
+			return "", p.unexpected("end of string")
+		}
+		c := p.s[p.pos]
+		switch {
+		case c == quote:
+			p.pos++
+			return b.String(), nil
+		case c < 0x20:
+			return "", p.errorf("control character %q in string", c)
+		case c != '\\':
+			b.WriteByte(c)
+			p.pos++
+			continue
+		}
+		p.pos++
+		switch c := p.peek(); c {
+		case 'b':
+			b.WriteByte('\b')
+		case 'f':
+			b.WriteByte('\f')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 't':
+			b.WriteByte('\t')
+		case '/', '\\', quote:
+			b.WriteByte(c)
+		case 'u':
+			p.pos++
+			r, err := p.u4()
+			if err != nil {
+				return "", err
+			}
+			b.WriteRune(r)
+			continue
+		default:
+			return "", p.unexpected("escape sequence")
+		}
+		p.pos++
+	}
+}
+
+// u4 parses escaped unicode character, starting after "\u".
+func (p *pathParser) u4() (rune, error) {
+	hex := func() (rune, error) {
+		if len(p.s)-p.pos < 4 {
+			return 0, p.unexpected("4 hex digits")
+		}
+		var r rune
+		for _, c := range []byte(p.s[p.pos : p.pos+4]) {
+			v := hexSet[c]
+			if v == 0 {
+				return 0, p.errorf("invalid hex digit %q", c)
+			}
+			r = r*16 + rune(v-1)
+		}
+		p.pos += 4
+		return r, nil
+	}
+	r1, err := hex()
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case r1 >= 0xDC00 && r1 <= 0xDFFF:
+		return 0, p.errorf("unexpected low surrogate %U", r1)
+	case !utf16.IsSurrogate(r1):
+		return r1, nil
+	}
+	if !strings.HasPrefix(p.s[p.pos:], `\u`) {
+		return 0, p.errorf("expected low surrogate after %U", r1)
+	}
+	p.pos += 2
+	r2, err := hex()
+	if err != nil {
+		return 0, err
+	}
+	r := utf16.DecodeRune(r1, r2)
+	if r == utf8.RuneError {
+		return 0, p.errorf("invalid surrogate pair %U %U", r1, r2)
+	}
+	return r, nil
+}
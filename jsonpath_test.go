@@ -0,0 +1,236 @@
+package jx
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// jsonPathStore is example document from RFC 9535, section 1.5.
+const jsonPathStore = `{"store":{` +
+	`"book":[` +
+	`{"category":"reference","author":"Nigel Rees","title":"Sayings of the Century","price":8.95},` +
+	`{"category":"fiction","author":"Evelyn Waugh","title":"Sword of Honour","price":12.99},` +
+	`{"category":"fiction","author":"Herman Melville","title":"Moby Dick","isbn":"0-553-21311-3","price":8.99},` +
+	`{"category":"fiction","author":"J. R. R. Tolkien","title":"The Lord of the Rings","isbn":"0-395-19395-8","price":22.99}` +
+	`],` +
+	`"bicycle":{"color":"red","price":399}` +
+	`}}`
+
+func testJSONPath(t *testing.T, input, query string, expect ...string) {
+	t.Helper()
+	p, err := CompileJSONPath(query)
+	require.NoError(t, err)
+	require.Equal(t, query, p.String())
+
+	testBufferReader(input, func(t *testing.T, d *Decoder) {
+		got := []string{}
+		require.NoError(t, p.Query(d, func(v Raw) error {
+			got = append(got, v.String())
+			return nil
+		}))
+		if expect == nil {
+			expect = []string{}
+		}
+		require.Equal(t, expect, got)
+	})(t)
+}
+
+func TestJSONPath_Store(t *testing.T) {
+	const (
+		book0   = `{"category":"reference","author":"Nigel Rees","title":"Sayings of the Century","price":8.95}`
+		book1   = `{"category":"fiction","author":"Evelyn Waugh","title":"Sword of Honour","price":12.99}`
+		book2   = `{"category":"fiction","author":"Herman Melville","title":"Moby Dick","isbn":"0-553-21311-3","price":8.99}`
+		book3   = `{"category":"fiction","author":"J. R. R. Tolkien","title":"The Lord of the Rings","isbn":"0-395-19395-8","price":22.99}`
+		bicycle = `{"color":"red","price":399}`
+	)
+	authors := []string{`"Nigel Rees"`, `"Evelyn Waugh"`, `"Herman Melville"`, `"J. R. R. Tolkien"`}
+	for i, tt := range []struct {
+		query  string
+		expect []string
+	}{
+		{`$`, []string{jsonPathStore}},
+		{`$.store.book[*].author`, authors},
+		{`$..author`, authors},
+		{`$.store.*`, []string{`[` + book0 + `,` + book1 + `,` + book2 + `,` + book3 + `]`, bicycle}},
+		{`$.store..price`, []string{`8.95`, `12.99`, `8.99`, `22.99`, `399`}},
+		{`$..book[2]`, []string{book2}},
+		{`$..book[2].author`, []string{`"Herman Melville"`}},
+		{`$..book[2].publisher`, nil},
+		{`$..book[-1]`, []string{book3}},
+		{`$..book[0,1]`, []string{book0, book1}},
+		{`$..book[:2]`, []string{book0, book1}},
+		{`$..book[?@.isbn]`, []string{book2, book3}},
+		{`$..book[?@.price<10]`, []string{book0, book2}},
+		{`$.store.book[?@.price < 10].title`, []string{`"Sayings of the Century"`, `"Moby Dick"`}},
+		{`$.store.book[?@.price > $.store.bicycle.price]`, nil},
+		{`$.store.book[?@.price <= $.store.book[0].price]`, []string{book0}},
+		{`$.store.book[?@.category == 'fiction' && !@.isbn].title`, []string{`"Sword of Honour"`}},
+		{`$.store.book[?(@.price > 20 || @.price < 9) && @.isbn]["title"]`, []string{`"Moby Dick"`, `"The Lord of the Rings"`}},
+		{`$.store.book[1:3].price`, []string{`12.99`, `8.99`}},
+		{`$.store.book[::-1].price`, []string{`22.99`, `8.99`, `12.99`, `8.95`}},
+		{`$.store.book[-2:].price`, []string{`8.99`, `22.99`}},
+		{`$.store.book[0:4:2].price`, []string{`8.95`, `8.99`}},
+		{`$.store.book[0:4:0].price`, nil},
+		{`$['store'][ 'bicycle' ]['color', 'price']`, []string{`"red"`, `399`}},
+		{`$.store.bicycle['price','color']`, []string{`399`, `"red"`}},
+		{`$.store.book.author`, nil},
+		{`$.store.bicycle[0]`, nil},
+		{`$.store.bicycle.color.length`, nil},
+	} {
+		tt := tt
+		t.Run(fmt.Sprintf("Test%d", i+1), func(t *testing.T) {
+			testJSONPath(t, jsonPathStore, tt.query, tt.expect...)
+		})
+	}
+}
+
+func TestJSONPath_Semantics(t *testing.T) {
+	for i, tt := range []struct {
+		input  string
+		query  string
+		expect []string
+	}{
+		// Descendant segment visits node before its descendants.
+		{`[[1],[2]]`, `$..[*]`, []string{`[1]`, `[2]`, `1`, `2`}},
+		{`{"a":{"a":1}}`, `$..a`, []string{`{"a":1}`, `1`}},
+		{`{"o":{"j":1,"k":2},"a":[5,3]}`, `$..[0]`, []string{`5`}},
+		// Duplicate selectors yield duplicates.
+		{`[1,2]`, `$[0,0]`, []string{`1`, `1`}},
+		{`{"a":1}`, `$['a','a']`, []string{`1`, `1`}},
+		// Escapes in names.
+		{`{"a\"b":1,"☺":2,"\t":3}`, `$["a\"b"]`, []string{`1`}},
+		{`{"a\"b":1,"☺":2,"\t":3}`, `$['☺']`, []string{`2`}},
+		{`{"a\"b":1,"☺":2,"\t":3}`, `$.☺`, []string{`2`}},
+		{`{"a\"b":1,"☺":2,"\t":3}`, `$['\t']`, []string{`3`}},
+		{`{"𝄞":1}`, `$['𝄞']`, []string{`1`}},
+		// Comparisons.
+		{`[1,1.0,"1",true,null,{"a":[1]},[1]]`, `$[?@ == 1]`, []string{`1`, `1.0`}},
+		{`[1,1.0,"1",true,null,{"a":[1]},[1]]`, `$[?@ == '1']`, []string{`"1"`}},
+		{`[1,1.0,"1",true,null,{"a":[1]},[1]]`, `$[?@ == null]`, []string{`null`}},
+		{`[1,1.0,"1",true,null,{"a":[1]},[1]]`, `$[?@ == true]`, []string{`true`}},
+		{`[1,"b","a",null]`, `$[?@ < 'b']`, []string{`"a"`}},
+		{`[1,"b","a",null]`, `$[?@ >= 1]`, []string{`1`}},
+		{`[1,"b","a",null]`, `$[?@ != 1]`, []string{`"b"`, `"a"`, `null`}},
+		{`[{"a":{"b":1,"c":[2]}},{"a":{"c":[2.0],"b":1e0}},{"a":{"c":[2]}}]`, `$[?@.a == $[0].a]`, []string{
+			`{"a":{"b":1,"c":[2]}}`,
+			`{"a":{"c":[2.0],"b":1e0}}`,
+		}},
+		// Missing value compared to missing value.
+		{`[{"a":1},{"b":2}]`, `$[?@.c == @.d]`, []string{`{"a":1}`, `{"b":2}`}},
+		{`[{"a":1},{"b":2}]`, `$[?@.c <= @.d]`, []string{`{"a":1}`, `{"b":2}`}},
+		{`[{"a":1},{"b":2}]`, `$[?@.c < @.d]`, nil},
+		{`[{"a":1},{"b":2}]`, `$[?@.a != 1]`, []string{`{"b":2}`}},
+		// Existence of falsy values.
+		{`[{"a":null},{"a":false},{}]`, `$[?@.a]`, []string{`{"a":null}`, `{"a":false}`}},
+		{`[{"a":[1,2]},{"a":[]}]`, `$[?@.a[*]]`, []string{`{"a":[1,2]}`}},
+		{`[{"a":[1,2]},{"a":[]}]`, `$[?@..[?@ == 2]]`, []string{`{"a":[1,2]}`}},
+		// Filter on object members.
+		{`{"x":{"v":1},"y":{"v":2}}`, `$[?@.v > 1]`, []string{`{"v":2}`}},
+		{`{"x":1,"y":2}`, `$[?-1 < @]`, []string{`1`, `2`}},
+		{`{"x":1,"y":2}`, `$[?1.5e0 > @]`, []string{`1`}},
+		// Whitespace.
+		{`{"a":{"b":1}}`, `$ .a ["b"]`, []string{`1`}},
+		{"{\"a\":[1,2]}", "$.a[ ?\t@ \n>\r1 ]", []string{`2`}},
+	} {
+		tt := tt
+		t.Run(fmt.Sprintf("Test%d", i+1), func(t *testing.T) {
+			testJSONPath(t, tt.input, tt.query, tt.expect...)
+		})
+	}
+}
+
+func TestCompileJSONPath(t *testing.T) {
+	for _, query := range []string{
+		``,
+		`@`,
+		`$.`,
+		`$..`,
+		`$. a`,
+		`$.1a`,
+		`$ `,
+		`$a`,
+		`$[`,
+		`$[]`,
+		`$[1`,
+		`$[1,]`,
+		`$['a]`,
+		`$['\a']`,
+		`$['\"']`,
+		`$["\'"]`,
+		`$['\u12']`,
+		`$['\uDD1E']`,
+		`$['\uD834']`,
+		`$['\uD834A']`,
+		"$['\x01']",
+		"$.\xff",
+		`$[01]`,
+		`$[-0]`,
+		`$[9007199254740992]`,
+		`$[99999999999999999999]`,
+		`$[1:2:3:4]`,
+		`$[?]`,
+		`$[?@.a ==]`,
+		`$[?@.a = 1]`,
+		`$[?1]`,
+		`$[?'a']`,
+		`$[?@.* == 1]`,
+		`$[?1 == @..a]`,
+		`$[?@[0:1] == 1]`,
+		`$[?length(@) == 1]`,
+		`$[?(@.a]`,
+		`$[?!1]`,
+		`$[?@ == 01]`,
+		`$[?@ == 1.]`,
+		`$[?@ == 1e]`,
+		`$[?@ == -]`,
+		`$[?@ == nul]`,
+		`$[?@.a && ]`,
+	} {
+		query := query
+		t.Run(query, func(t *testing.T) {
+			_, err := CompileJSONPath(query)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestJSONPath_QueryError(t *testing.T) {
+	for _, query := range []string{
+		`$`,
+		`$.a.b`,
+		`$..b`,
+		`$.a[?@.c]`,
+		`$.a[?$.c]`,
+		`$.a[-1]`,
+		`$.a[*][0]`,
+	} {
+		p, err := CompileJSONPath(query)
+		require.NoError(t, err)
+		for _, input := range []string{
+			`{"a":[{"b":1},{"b":2]}`,
+			`{"a":[1,2`,
+			`{"a":`,
+			`[`,
+			``,
+		} {
+			input := input
+			t.Run(query+input, testBufferReader(input, func(t *testing.T, d *Decoder) {
+				err := p.Query(d, func(v Raw) error { return nil })
+				require.Error(t, err)
+			}))
+		}
+	}
+	t.Run("Callback", func(t *testing.T) {
+		p, err := CompileJSONPath(`$..b`)
+		require.NoError(t, err)
+		for _, input := range []string{
+			`{"a":[{"b":1}]}`,
+			`{"b":1}`,
+		} {
+			err := p.Query(DecodeStr(input), func(v Raw) error { return errPathStop })
+			require.ErrorIs(t, err, errPathStop)
+		}
+	})
+}
@@ -0,0 +1,213 @@
+package jsonschema
+
+import (
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// formats are checkers of "format" keyword by name.
+var formats = map[string]func(s string) bool{
+	"date-time":     isDateTime,
+	"date":          isDate,
+	"time":          isTime,
+	"email":         isEmail,
+	"hostname":      isHostname,
+	"ipv4":          isIPv4,
+	"ipv6":          isIPv6,
+	"uri":           isURI,
+	"uri-reference": isURIReference,
+	"uuid":          isUUID,
+	"regex":         isRegex,
+	"json-pointer":  isJSONPointer,
+}
+
+// isDateTime checks RFC 3339 date-time.
+func isDateTime(s string) bool {
+	i := strings.IndexAny(s, "Tt")
+	if i < 0 {
+		return false
+	}
+	return isDate(s[:i]) && isTime(s[i+1:])
+}
+
+// isDate checks RFC 3339 full-date.
+func isDate(s string) bool {
+	_, err := time.Parse("2006-01-02", s)
+	return err == nil
+}
+
+// isTime checks RFC 3339 full-time, leap second is allowed.
+func isTime(s string) bool {
+	// partial-time is "hh:mm:ss" with optional fraction.
+	if len(s) < len("15:04:05Z") || s[2] != ':' || s[5] != ':' {
+		return false
+	}
+	var hms [3]int
+	for i := range hms {
+		d := s[i*3 : i*3+2]
+		if !isDigits(d) {
+			return false
+		}
+		hms[i] = int(d[0]-'0')*10 + int(d[1]-'0')
+	}
+	h, m, sec := hms[0], hms[1], hms[2]
+	if h > 23 || m > 59 || sec > 60 {
+		return false
+	}
+	s = s[8:]
+	if s != "" && s[0] == '.' {
+		n := 1
+		for n < len(s) && isDigit(s[n]) {
+			n++
+		}
+		if n == 1 {
+			return false
+		}
+		s = s[n:]
+	}
+
+	// time-offset is "Z" or "+hh:mm".
+	var offset int
+	switch {
+	case s == "Z" || s == "z":
+	case len(s) == 6 && (s[0] == '+' || s[0] == '-') && s[3] == ':' &&
+		isDigits(s[1:3]) && isDigits(s[4:6]):
+		oh := int(s[1]-'0')*10 + int(s[2]-'0')
+		om := int(s[4]-'0')*10 + int(s[5]-'0')
+		if oh > 23 || om > 59 {
+			return false
+		}
+		offset = oh*60 + om
+		if s[0] == '+' {
+			offset = -offset
+		}
+	default:
+		return false
+	}
+	if sec == 60 {
+		// Leap second is inserted at 23:59:60 UTC.
+		utc := ((h*60+m+offset)%(24*60) + 24*60) % (24 * 60)
+		return utc == 23*60+59
+	}
+	return true
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if !isDigit(s[i]) {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// isEmail checks RFC 5321 mailbox.
+func isEmail(s string) bool {
+	addr, err := mail.ParseAddress(s)
+	return err == nil && addr.Address == s && addr.Name == ""
+}
+
+// isHostname checks RFC 1123 hostname.
+func isHostname(s string) bool {
+	s = strings.TrimSuffix(s, ".")
+	if s == "" || len(s) > 253 {
+		return false
+	}
+	for _, label := range strings.Split(s, ".") {
+		if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for i := 0; i < len(label); i++ {
+			c := label[i]
+			if !isDigit(c) && c != '-' && (c|0x20 < 'a' || c|0x20 > 'z') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// isIPv4 checks dotted-quad IPv4 address.
+func isIPv4(s string) bool {
+	if strings.Count(s, ".") != 3 {
+		return false
+	}
+	for _, part := range strings.Split(s, ".") {
+		// Leading zeroes are ambiguous.
+		if !isDigits(part) || len(part) > 3 || len(part) > 1 && part[0] == '0' {
+			return false
+		}
+	}
+	ip := net.ParseIP(s)
+	return ip != nil && ip.To4() != nil
+}
+
+// isIPv6 checks RFC 4291 IPv6 address.
+func isIPv6(s string) bool {
+	return strings.Contains(s, ":") && !strings.Contains(s, "%") && net.ParseIP(s) != nil
+}
+
+// isURI checks absolute RFC 3986 URI.
+func isURI(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && u.Scheme != "" && !strings.ContainsAny(s, " \\")
+}
+
+// isURIReference checks RFC 3986 URI or relative reference.
+func isURIReference(s string) bool {
+	_, err := url.Parse(s)
+	return err == nil && !strings.ContainsAny(s, " \\")
+}
+
+// isUUID checks RFC 4122 UUID, like "2eb8aa08-aa98-11ea-b4aa-73b441d16380".
+func isUUID(s string) bool {
+	if len(s) != 36 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch i {
+		case 8, 13, 18, 23:
+			if c != '-' {
+				return false
+			}
+		default:
+			if !isDigit(c) && (c|0x20 < 'a' || c|0x20 > 'f') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// isRegex checks regular expression.
+//
+// NB: Go RE2 syntax is used instead of ECMA-262.
+func isRegex(s string) bool {
+	_, err := regexp.Compile(s)
+	return err == nil
+}
+
+// isJSONPointer checks RFC 6901 JSON Pointer.
+func isJSONPointer(s string) bool {
+	if s != "" && s[0] != '/' {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] != '~' {
+			continue
+		}
+		if i+1 == len(s) || s[i+1] != '0' && s[i+1] != '1' {
+			return false
+		}
+	}
+	return true
+}
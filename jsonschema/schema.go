@@ -0,0 +1,507 @@
+// Package jsonschema implements JSON Schema validation on top of jx.Decoder.
+//
+// Supported keywords of draft 2020-12 are:
+//
+//   - core: $ref to JSON Pointer within schema document, $defs,
+//   - applicator: allOf, anyOf, oneOf, not, prefixItems, items,
+//     properties, patternProperties, additionalProperties,
+//   - validation: type, enum, const, minimum, maximum, exclusiveMinimum,
+//     exclusiveMaximum, minLength, maxLength, pattern, minItems, maxItems,
+//     uniqueItems, minProperties, maxProperties, required,
+//   - format assertion, see Schema.Validate.
+//
+// Other keywords are ignored.
+package jsonschema
+
+import (
+	"bytes"
+	"math/big"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-faster/errors"
+
+	"github.com/go-faster/jx"
+)
+
+// Schema is compiled JSON Schema.
+//
+// Schema is safe for concurrent use.
+type Schema struct {
+	// Boolean schema, which result is valid.
+	boolean bool
+	valid   bool
+
+	// In-place applicators.
+	ref   *Schema
+	allOf []*Schema
+	anyOf []*Schema
+	oneOf []*Schema
+	not   *Schema
+	// local is set if schema has keywords, which are not in-place
+	// applicators.
+	local bool
+
+	types typeSet
+	enum  []jx.Raw
+	cnst  jx.Raw // nil if absent
+
+	minimum          *bound
+	maximum          *bound
+	exclusiveMinimum *bound
+	exclusiveMaximum *bound
+
+	minLength int // -1 if absent
+	maxLength int // -1 if absent
+	pattern   *regexp.Regexp
+	format    string
+	isFormat  func(s string) bool
+
+	prefixItems []*Schema
+	items       *Schema
+	minItems    int // -1 if absent
+	maxItems    int // -1 if absent
+	uniqueItems bool
+
+	properties           map[string]*Schema
+	patternProperties    []patternSchema
+	additionalProperties *Schema
+	required             []string
+	minProperties        int // -1 if absent
+	maxProperties        int // -1 if absent
+}
+
+// bound is numeric limit.
+type bound struct {
+	v   *big.Float
+	lit string
+}
+
+type patternSchema struct {
+	re     *regexp.Regexp
+	schema *Schema
+}
+
+// buffered reports whether value should be buffered to be inspected more
+// than once.
+func (s *Schema) buffered() bool {
+	applicators := len(s.allOf) + len(s.anyOf) + len(s.oneOf)
+	if s.ref != nil {
+		applicators++
+	}
+	if s.not != nil {
+		applicators++
+	}
+	if s.local {
+		applicators++
+	}
+	return applicators > 1 || len(s.anyOf) > 0 || len(s.oneOf) > 0 || s.not != nil ||
+		s.enum != nil || s.cnst != nil || s.uniqueItems
+}
+
+// single returns the only in-place applicator of schema without other
+// keywords, nil if there is no such.
+func (s *Schema) single() *Schema {
+	if s.boolean || s.buffered() || s.local {
+		return nil
+	}
+	if s.ref != nil {
+		return s.ref
+	}
+	if len(s.allOf) == 1 {
+		return s.allOf[0]
+	}
+	return nil
+}
+
+// inPlace calls f for every in-place applicator.
+func (s *Schema) inPlace(f func(sub *Schema) bool) bool {
+	if s.ref != nil && !f(s.ref) {
+		return false
+	}
+	if s.not != nil && !f(s.not) {
+		return false
+	}
+	for _, list := range [][]*Schema{s.allOf, s.anyOf, s.oneOf} {
+		for _, sub := range list {
+			if !f(sub) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Compile compiles JSON Schema document.
+//
+// References are resolved within document, so $ref should be JSON Pointer
+// fragment, like "#/$defs/name".
+func Compile(data []byte) (*Schema, error) {
+	if err := jx.DecodeBytes(data).Validate(); err != nil {
+		return nil, errors.Wrap(err, "validate")
+	}
+	c := compiler{
+		// Keywords like enum reference document.
+		root:    append(jx.Raw(nil), bytes.TrimSpace(data)...),
+		schemas: map[string]*Schema{},
+	}
+	s, err := c.compile("")
+	if err != nil {
+		return nil, err
+	}
+	if err := c.checkLoops(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+type compiler struct {
+	root    jx.Raw
+	schemas map[string]*Schema // by JSON Pointer
+}
+
+// compile compiles schema referenced by JSON Pointer.
+func (c *compiler) compile(ptr string) (*Schema, error) {
+	if s, ok := c.schemas[ptr]; ok {
+		return s, nil
+	}
+	raw, err := c.root.Pointer(ptr)
+	if err != nil {
+		return nil, err
+	}
+	s := &Schema{
+		minLength:     -1,
+		maxLength:     -1,
+		minItems:      -1,
+		maxItems:      -1,
+		minProperties: -1,
+		maxProperties: -1,
+	}
+	// Register before parsing to handle recursive references.
+	c.schemas[ptr] = s
+
+	switch t := raw.Type(); t {
+	case jx.Bool:
+		s.boolean = true
+		s.valid, err = raw.Bool()
+		return s, err
+	case jx.Object:
+	default:
+		return nil, errors.Errorf("unexpected schema type %s", t)
+	}
+	if err := raw.Obj(func(key []byte, v jx.Raw) error {
+		k := string(key)
+		if err := c.keyword(s, ptr+"/"+escapePointer(k), k, v); err != nil {
+			return errors.Wrap(err, k)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// checkLoops checks that in-place applicators, like $ref or allOf, have
+// no cycles, which would validate value infinitely.
+func (c *compiler) checkLoops() error {
+	const (
+		visiting = 1
+		done     = 2
+	)
+	var (
+		state = make(map[*Schema]int, len(c.schemas))
+		visit func(s *Schema) bool
+	)
+	visit = func(s *Schema) bool {
+		switch state[s] {
+		case visiting:
+			return false
+		case done:
+			return true
+		}
+		state[s] = visiting
+		if !s.inPlace(visit) {
+			return false
+		}
+		state[s] = done
+		return true
+	}
+	for ptr, s := range c.schemas {
+		if !visit(s) {
+			return errors.Errorf("schema %q: infinite recursion", ptr)
+		}
+	}
+	return nil
+}
+
+// keyword compiles schema keyword, which value is at ptr.
+func (c *compiler) keyword(s *Schema, ptr, key string, v jx.Raw) (err error) {
+	switch key {
+	case "$ref":
+		ref, err := v.Str()
+		if err != nil {
+			return err
+		}
+		s.ref, err = c.compileRef(ref)
+		return err
+	case "allOf":
+		s.allOf, err = c.compileArr(ptr, v)
+		return err
+	case "anyOf":
+		s.anyOf, err = c.compileArr(ptr, v)
+		return err
+	case "oneOf":
+		s.oneOf, err = c.compileArr(ptr, v)
+		return err
+	case "not":
+		s.not, err = c.compile(ptr)
+		return err
+	}
+
+	local := true
+	switch key {
+	case "type":
+		s.types, err = parseTypes(v)
+	case "enum":
+		err = v.Arr(func(v jx.Raw) error {
+			s.enum = append(s.enum, v)
+			return nil
+		})
+		if err == nil && s.enum == nil {
+			s.enum = []jx.Raw{}
+		}
+	case "const":
+		s.cnst = v
+	case "minimum":
+		s.minimum, err = parseNum(v)
+	case "maximum":
+		s.maximum, err = parseNum(v)
+	case "exclusiveMinimum":
+		s.exclusiveMinimum, err = parseNum(v)
+	case "exclusiveMaximum":
+		s.exclusiveMaximum, err = parseNum(v)
+	case "minLength":
+		s.minLength, err = parseCount(v)
+	case "maxLength":
+		s.maxLength, err = parseCount(v)
+	case "pattern":
+		s.pattern, err = parsePattern(v)
+	case "format":
+		if s.format, err = v.Str(); err == nil {
+			// Unknown formats are ignored.
+			s.isFormat = formats[s.format]
+		}
+	case "prefixItems":
+		s.prefixItems, err = c.compileArr(ptr, v)
+	case "items":
+		s.items, err = c.compile(ptr)
+	case "minItems":
+		s.minItems, err = parseCount(v)
+	case "maxItems":
+		s.maxItems, err = parseCount(v)
+	case "uniqueItems":
+		s.uniqueItems, err = v.Bool()
+	case "properties":
+		s.properties = map[string]*Schema{}
+		err = v.Obj(func(key []byte, _ jx.Raw) error {
+			k := string(key)
+			sub, err := c.compile(ptr + "/" + escapePointer(k))
+			if err != nil {
+				return errors.Wrap(err, k)
+			}
+			s.properties[k] = sub
+			return nil
+		})
+	case "patternProperties":
+		err = v.Obj(func(key []byte, _ jx.Raw) error {
+			k := string(key)
+			re, err := regexp.Compile(k)
+			if err != nil {
+				return err
+			}
+			sub, err := c.compile(ptr + "/" + escapePointer(k))
+			if err != nil {
+				return errors.Wrap(err, k)
+			}
+			s.patternProperties = append(s.patternProperties, patternSchema{re: re, schema: sub})
+			return nil
+		})
+	case "additionalProperties":
+		s.additionalProperties, err = c.compile(ptr)
+	case "required":
+		err = v.Arr(func(v jx.Raw) error {
+			name, err := v.Str()
+			if err != nil {
+				return err
+			}
+			s.required = append(s.required, name)
+			return nil
+		})
+	case "minProperties":
+		s.minProperties, err = parseCount(v)
+	case "maxProperties":
+		s.maxProperties, err = parseCount(v)
+	default:
+		// Annotations and unknown keywords.
+		local = false
+	}
+	if local {
+		s.local = true
+	}
+	return err
+}
+
+// compileArr compiles array of schemas at ptr.
+func (c *compiler) compileArr(ptr string, v jx.Raw) (schemas []*Schema, _ error) {
+	err := v.Arr(func(jx.Raw) error {
+		s, err := c.compile(ptr + "/" + strconv.Itoa(len(schemas)))
+		if err != nil {
+			return errors.Wrapf(err, "%d", len(schemas))
+		}
+		schemas = append(schemas, s)
+		return nil
+	})
+	return schemas, err
+}
+
+func (c *compiler) compileRef(ref string) (*Schema, error) {
+	if !strings.HasPrefix(ref, "#") {
+		return nil, errors.Errorf("unsupported reference %q: only references within document are supported", ref)
+	}
+	ptr, err := url.PathUnescape(ref[1:])
+	if err != nil {
+		return nil, errors.Wrapf(err, "reference %q", ref)
+	}
+	if ptr != "" && ptr[0] != '/' {
+		return nil, errors.Errorf("unsupported reference %q: anchors are not supported", ref)
+	}
+	s, err := c.compile(ptr)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reference %q", ref)
+	}
+	return s, nil
+}
+
+// typeSet is set of json types of "type" keyword.
+type typeSet uint8
+
+const (
+	typeNull typeSet = 1 << iota
+	typeBoolean
+	typeObject
+	typeArray
+	typeNumber
+	typeInteger
+	typeString
+)
+
+var typeNames = []struct {
+	t    typeSet
+	name string
+}{
+	{typeNull, "null"},
+	{typeBoolean, "boolean"},
+	{typeObject, "object"},
+	{typeArray, "array"},
+	{typeNumber, "number"},
+	{typeInteger, "integer"},
+	{typeString, "string"},
+}
+
+func (t typeSet) String() string {
+	var names []string
+	for _, n := range typeNames {
+		if t&n.t != 0 {
+			names = append(names, n.name)
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+func parseType(name string) (typeSet, error) {
+	for _, n := range typeNames {
+		if n.name == name {
+			return n.t, nil
+		}
+	}
+	return 0, errors.Errorf("unknown type %q", name)
+}
+
+func parseTypes(v jx.Raw) (typeSet, error) {
+	if v.Type() == jx.String {
+		name, err := v.Str()
+		if err != nil {
+			return 0, err
+		}
+		return parseType(name)
+	}
+	var types typeSet
+	err := v.Arr(func(v jx.Raw) error {
+		name, err := v.Str()
+		if err != nil {
+			return err
+		}
+		t, err := parseType(name)
+		types |= t
+		return err
+	})
+	return types, err
+}
+
+// numPrec is precision of compared numbers.
+const numPrec = 256
+
+func parseNum(v jx.Raw) (*bound, error) {
+	// Raw.Num also accepts numbers in strings.
+	if t := v.Type(); t != jx.Number {
+		return nil, errors.Errorf("unexpected type %s, expected %s", t, jx.Number)
+	}
+	n, err := v.Num()
+	if err != nil {
+		return nil, err
+	}
+	f, err := numFloat(n)
+	if err != nil {
+		return nil, err
+	}
+	return &bound{v: f, lit: n.String()}, nil
+}
+
+func numFloat(n jx.Num) (*big.Float, error) {
+	f, _, err := big.ParseFloat(n.String(), 10, numPrec, big.ToNearestEven)
+	if err != nil {
+		return nil, errors.Wrapf(err, "number %s", n)
+	}
+	return f, nil
+}
+
+// parseCount parses non-negative integer, which can be written as number
+// with zero fractional part, like 2.0.
+func parseCount(v jx.Raw) (int, error) {
+	b, err := parseNum(v)
+	if err != nil {
+		return 0, err
+	}
+	n, acc := b.v.Int64()
+	if !b.v.IsInt() || acc != big.Exact || n < 0 || int64(int(n)) != n {
+		return 0, errors.Errorf("invalid count %s", b.lit)
+	}
+	return int(n), nil
+}
+
+func parsePattern(v jx.Raw) (*regexp.Regexp, error) {
+	pattern, err := v.Str()
+	if err != nil {
+		return nil, err
+	}
+	return regexp.Compile(pattern)
+}
+
+func escapePointer(key string) string {
+	if strings.IndexAny(key, "~/") < 0 {
+		return key
+	}
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+}
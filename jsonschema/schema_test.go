@@ -0,0 +1,333 @@
+package jsonschema
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/go-faster/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-faster/jx"
+)
+
+func TestCompile(t *testing.T) {
+	for _, schema := range []string{
+		`true`,
+		`false`,
+		`{}`,
+		`{"type":["string","null"],"title":"ignored","x-unknown":[1]}`,
+		`{"$defs":{"a":{"$ref":"#/$defs/b"},"b":{"type":"string"}},"$ref":"#/$defs/a"}`,
+		`{"$defs":{"a~b/c":{"type":"string"}},"$ref":"#/$defs/a~0b~1c"}`,
+		`{"$defs":{"a b":{"type":"string"}},"$ref":"#/$defs/a%20b"}`,
+		// Recursion through properties consumes value.
+		`{"properties":{"next":{"$ref":"#"}}}`,
+		`{"items":{"anyOf":[{"$ref":"#"},{"type":"null"}]}}`,
+		// Counts can be integral numbers with fraction or exponent.
+		`{"minLength":2.0,"maxItems":1e1}`,
+	} {
+		_, err := Compile([]byte(schema))
+		require.NoError(t, err, schema)
+	}
+	for _, schema := range []string{
+		``,
+		`{`,
+		`1`,
+		`{"type":"foo"}`,
+		`{"type":1}`,
+		`{"minLength":-1}`,
+		`{"minLength":1.5}`,
+		`{"pattern":"["}`,
+		`{"patternProperties":{"[":{}}}`,
+		`{"properties":{"a":1}}`,
+		`{"$ref":"other.json"}`,
+		`{"$ref":"#anchor"}`,
+		`{"$ref":"#/$defs/missing"}`,
+		`{"$ref":"#"}`,
+		`{"$defs":{"a":{"allOf":[{"$ref":"#/$defs/b"}]},"b":{"not":{"$ref":"#/$defs/a"}}},"$ref":"#/$defs/a"}`,
+		`{"required":[1]}`,
+		`{"minimum":"1"}`,
+		`{"maximum":"1"}`,
+		`{"minLength":"2"}`,
+		`{"minLength":1e100}`,
+	} {
+		_, err := Compile([]byte(schema))
+		require.Error(t, err, schema)
+	}
+}
+
+func TestSchema_Validate(t *testing.T) {
+	for i, tt := range []struct {
+		schema  string
+		valid   []string
+		invalid []string
+	}{
+		{`true`, []string{`1`, `{"a":[]}`}, nil},
+		{`false`, nil, []string{`1`, `null`}},
+		{
+			`{"type":"integer"}`,
+			[]string{`1`, `-1`, `1.0`, `1e2`, `100000000000000000000000000000000000000`},
+			[]string{`1.5`, `"1"`, `1e-2`, `null`},
+		},
+		{
+			`{"type":["boolean","null","object"]}`,
+			[]string{`true`, `null`, `{}`},
+			[]string{`1`, `""`, `[]`},
+		},
+		{
+			`{"minimum":1,"exclusiveMaximum":10}`,
+			[]string{`1`, `9.999999999999999999999999`, `"not a number"`},
+			[]string{`0.999999999999999999999999`, `10`, `1e300`},
+		},
+		{
+			`{"exclusiveMinimum":0,"maximum":1e400}`,
+			[]string{`1e-400`, `1e400`},
+			[]string{`0`, `-1`, `1e401`},
+		},
+		{
+			`{"minLength":2,"maxLength":3,"pattern":"^[a-zя]+$"}`,
+			[]string{`"ab"`, `"яяя"`, `"яя"`, `1`},
+			[]string{`"a"`, `"abcd"`, `"AB"`},
+		},
+		{
+			`{"enum":[1,"a",{"b":[null]}]}`,
+			[]string{`1`, `1.0`, `"a"`, `"a"`, `{ "b": [ null ] }`},
+			[]string{`2`, `"b"`, `{"b":[]}`, `{}`},
+		},
+		{`{"const":{"a":1,"b":2}}`, []string{`{"b":2,"a":1}`}, []string{`{"a":1}`, `null`}},
+		{
+			`{"type":"object","properties":{"a":{"type":"string"}},"required":["a","b"]}`,
+			[]string{`{"a":"","b":1}`, `{"b":null,"c":1,"a":"x"}`},
+			[]string{`{"a":1,"b":1}`, `{"a":""}`, `[]`},
+		},
+		{
+			`{"properties":{"a":{"minimum":1}},"patternProperties":{"^a":{"maximum":2},"^b":{"type":"string"}},"additionalProperties":false}`,
+			[]string{`{}`, `{"a":1,"aa":0,"b":"x"}`, `1`},
+			[]string{`{"a":3}`, `{"a":0}`, `{"b":1}`, `{"c":1}`},
+		},
+		{
+			`{"minProperties":1,"maxProperties":2}`,
+			[]string{`{"a":1}`, `{"a":1,"b":2}`, `[]`},
+			[]string{`{}`, `{"a":1,"b":2,"c":3}`},
+		},
+		{
+			`{"prefixItems":[{"type":"string"},{"type":"number"}],"items":{"type":"null"},"minItems":1,"maxItems":4}`,
+			[]string{`["a"]`, `["a",1]`, `["a",1,null,null]`},
+			[]string{`[]`, `[1]`, `["a","b"]`, `["a",1,1]`, `["a",1,null,null,null]`},
+		},
+		{
+			`{"uniqueItems":true,"items":{"type":"number"}}`,
+			[]string{`[]`, `[1,2,3]`},
+			[]string{`[1,2,1.0]`, `[1,"a"]`},
+		},
+		{`{"uniqueItems":true}`, []string{`[{"a":1},{"a":2}]`}, []string{`[{"a":1,"b":2},{"b":2,"a":1}]`}},
+		{
+			`{"allOf":[{"minimum":1},{"maximum":2}]}`,
+			[]string{`1`, `2`},
+			[]string{`0`, `3`},
+		},
+		{
+			`{"anyOf":[{"type":"string"},{"minimum":1}]}`,
+			[]string{`"a"`, `1`},
+			[]string{`0`},
+		},
+		{
+			`{"oneOf":[{"type":"integer"},{"minimum":2}]}`,
+			[]string{`1`, `2.5`},
+			[]string{`2`, `1.5`},
+		},
+		{`{"not":{"type":"string"}}`, []string{`1`}, []string{`"a"`}},
+		{
+			`{"$defs":{"node":{"type":"object","properties":{"value":{"type":"integer"},"next":{"$ref":"#/$defs/node"}}}},"$ref":"#/$defs/node"}`,
+			[]string{`{"value":1,"next":{"value":2,"next":{}}}`},
+			[]string{`{"value":1,"next":{"value":"2"}}`, `{"next":{"next":1}}`},
+		},
+		{
+			`{"$ref":"#/$defs/pos","maximum":10,"$defs":{"pos":{"exclusiveMinimum":0}}}`,
+			[]string{`1`, `10`},
+			[]string{`0`, `11`},
+		},
+		{
+			`{"type":"string","format":"date-time"}`,
+			[]string{`"2020-01-02T03:04:05Z"`, `"2020-01-02t03:04:05.123+03:00"`},
+			[]string{`"2020-01-02"`, `"2020-13-02T03:04:05Z"`, `"2020-01-02T03:04:05"`},
+		},
+		{`{"format":"unknown"}`, []string{`"foo"`}, nil},
+	} {
+		tt := tt
+		t.Run(fmt.Sprintf("Test%d", i+1), func(t *testing.T) {
+			s, err := Compile([]byte(tt.schema))
+			require.NoError(t, err)
+
+			for _, input := range tt.valid {
+				require.NoError(t, s.ValidateBytes([]byte(input)), input)
+				d := jx.Decode(iotest.OneByteReader(strings.NewReader(input)), 1)
+				require.NoError(t, s.Validate(d), input)
+
+				d = jx.Decode(iotest.OneByteReader(strings.NewReader(input)), 1)
+				raw, err := s.ValidateRaw(d)
+				require.NoError(t, err, input)
+				require.Equal(t, input, raw.String())
+			}
+			for _, input := range tt.invalid {
+				var errs Errors
+				require.ErrorAs(t, s.ValidateBytes([]byte(input)), &errs, input)
+				d := jx.Decode(iotest.OneByteReader(strings.NewReader(input)), 1)
+				require.ErrorAs(t, s.Validate(d), &errs, input)
+
+				d = jx.Decode(iotest.OneByteReader(strings.NewReader(input)), 1)
+				_, err := s.ValidateRaw(d)
+				require.ErrorAs(t, err, &errs, input)
+			}
+		})
+	}
+}
+
+func TestSchema_Validate_errors(t *testing.T) {
+	s, err := Compile([]byte(`{
+		"type": "object",
+		"properties": {
+			"id": {"type": "integer", "minimum": 1},
+			"tags": {"type": "array", "items": {"type": "string", "maxLength": 3}},
+			"a/b": {"enum": ["x", "y"]},
+			"nested": {"$ref": "#"}
+		},
+		"required": ["id"]
+	}`))
+	require.NoError(t, err)
+
+	input := `{"id":0,"tags":["foo","long",1],"a/b":"z","nested":{"id":"1","nested":{}}}`
+	expected := Errors{
+		{Path: "/id", Keyword: "minimum", Message: "0 is less than 1"},
+		{Path: "/tags/1", Keyword: "maxLength", Message: "length 4 is greater than 3"},
+		{Path: "/tags/2", Keyword: "type", Message: "expected string, got number"},
+		{Path: "/a~1b", Keyword: "enum", Message: `value is not one of ["x", "y"]`},
+		{Path: "/nested/id", Keyword: "type", Message: "expected integer, got string"},
+		{Path: "/nested/nested", Keyword: "required", Message: `missing property "id"`},
+	}
+	for _, d := range []*jx.Decoder{
+		jx.DecodeStr(input),
+		jx.Decode(iotest.OneByteReader(strings.NewReader(input)), 1),
+	} {
+		var errs Errors
+		require.ErrorAs(t, s.Validate(d), &errs)
+		require.Equal(t, expected, errs)
+	}
+	require.Equal(t,
+		`"/id": minimum: 0 is less than 1`,
+		expected[0].Error(),
+	)
+
+	// Decoding errors are returned as is.
+	for _, input := range []string{
+		``,
+		`{"id":1`,
+		`{"id":1,"tags":[1,]}`,
+		`{"id":1} {}`,
+		`{"id":1} }`,
+	} {
+		err := s.ValidateBytes([]byte(input))
+		require.Error(t, err, input)
+		var errs Errors
+		require.False(t, errors.As(err, &errs), input)
+	}
+}
+
+func TestFormats(t *testing.T) {
+	for _, tt := range []struct {
+		format  string
+		valid   []string
+		invalid []string
+	}{
+		{
+			"date",
+			[]string{"2020-02-29", "1999-12-31"},
+			[]string{"2021-02-29", "2020-1-01", "20200101", ""},
+		},
+		{
+			"time",
+			[]string{"23:59:60Z", "15:59:60-08:00", "00:00:00.5+01:00", "12:00:00z"},
+			[]string{"12:00:00", "24:00:00Z", "12:00:60Z", "12:00:00.Z", "12:00:00+1:00", "1:00:00Z"},
+		},
+		{
+			"email",
+			[]string{"joe@example.com", "a.b+c@example.org"},
+			[]string{"joe", "Joe <joe@example.com>", "@example.com"},
+		},
+		{
+			"hostname",
+			[]string{"example.com", "a-b.example.com.", "localhost"},
+			[]string{"", "-a.com", "a_b.com", "a..b", strings.Repeat("a", 64) + ".com"},
+		},
+		{
+			"ipv4",
+			[]string{"127.0.0.1", "255.255.255.255"},
+			[]string{"256.0.0.1", "1.2.3", "01.2.3.4", "::1"},
+		},
+		{
+			"ipv6",
+			[]string{"::1", "2001:db8::8a2e:370:7334", "::ffff:127.0.0.1"},
+			[]string{"127.0.0.1", "1::2::3", "fe80::1%eth0"},
+		},
+		{
+			"uri",
+			[]string{"https://example.com/a?b#c", "urn:isbn:0451450523"},
+			[]string{"/relative", "http://a b", "%"},
+		},
+		{
+			"uri-reference",
+			[]string{"/relative", "#frag", "https://example.com"},
+			[]string{"\\\\host", "%zz"},
+		},
+		{
+			"uuid",
+			[]string{"2eb8aa08-aa98-11ea-b4aa-73b441d16380", "2EB8AA08-AA98-11EA-B4AA-73B441D16380"},
+			[]string{"2eb8aa08aa9811eab4aa73b441d16380", "2eb8aa08-aa98-11ea-b4aa-73b441d1638g"},
+		},
+		{
+			"regex",
+			[]string{"^a+$", ""},
+			[]string{"[", "(?<"},
+		},
+		{
+			"json-pointer",
+			[]string{"", "/", "/a~0b/~1/0"},
+			[]string{"a", "/~", "/~2"},
+		},
+	} {
+		check := formats[tt.format]
+		for _, s := range tt.valid {
+			require.True(t, check(s), "%s: %q", tt.format, s)
+		}
+		for _, s := range tt.invalid {
+			require.False(t, check(s), "%s: %q", tt.format, s)
+		}
+	}
+}
+
+func BenchmarkSchema_Validate(b *testing.B) {
+	s, err := Compile([]byte(`{
+		"type": "object",
+		"properties": {
+			"id": {"type": "integer", "minimum": 1},
+			"name": {"type": "string", "minLength": 1},
+			"tags": {"type": "array", "items": {"type": "string"}},
+			"kind": {"enum": ["a", "b"]}
+		},
+		"required": ["id", "name"]
+	}`))
+	if err != nil {
+		b.Fatal(err)
+	}
+	data := []byte(`{"id":10,"name":"foo","tags":["a","b","c"],"kind":"b","extra":{"a":[1,2,3]}}`)
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := s.ValidateBytes(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,559 @@
+package jsonschema
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-faster/errors"
+
+	"github.com/go-faster/jx"
+)
+
+// Error is validation error.
+type Error struct {
+	// Path is JSON Pointer to invalid value.
+	Path string
+	// Keyword is schema keyword, which value does not satisfy.
+	Keyword string
+	Message string
+}
+
+func (e Error) Error() string {
+	return strconv.Quote(e.Path) + ": " + e.Keyword + ": " + e.Message
+}
+
+// Errors is list of validation errors, in order of occurrence in document.
+type Errors []Error
+
+func (e Errors) Error() string {
+	var b strings.Builder
+	for i, err := range e {
+		if i > 0 {
+			b.WriteString("; ")
+		}
+		b.WriteString(err.Error())
+	}
+	return b.String()
+}
+
+// Validate reads single value from d and validates it.
+//
+// Value is validated while it is read, so only parts of value, which are
+// inspected by several subschemas (like anyOf or enum) are buffered.
+//
+// Returns Errors if value is invalid, other errors are decoding errors.
+// Value is consumed, use ValidateRaw to decode it after validation.
+//
+// Supported formats are date-time, date, time, email, hostname, ipv4,
+// ipv6, uri, uri-reference, uuid, regex and json-pointer, others are
+// ignored.
+func (s *Schema) Validate(d *jx.Decoder) error {
+	v := validator{}
+	if err := v.validate(s, d); err != nil {
+		return err
+	}
+	if len(v.errs) > 0 {
+		return v.errs
+	}
+	return nil
+}
+
+// ValidateRaw reads single value from d, validates it and returns it as
+// raw json, so value can be decoded after validation without reading d
+// again:
+//
+//	raw, err := s.ValidateRaw(d)
+//	if err != nil {
+//		return err
+//	}
+//	return user.Decode(jx.DecodeBytes(raw))
+//
+// Do not retain returned value, it references underlying buffer of d,
+// like Decoder.Raw.
+func (s *Schema) ValidateRaw(d *jx.Decoder) (jx.Raw, error) {
+	raw, err := d.Raw()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ValidateBytes(raw); err != nil {
+		return nil, err
+	}
+	return raw, nil
+}
+
+// ValidateBytes validates json document, see Validate.
+func (s *Schema) ValidateBytes(data []byte) error {
+	d := jx.GetDecoder()
+	defer jx.PutDecoder(d)
+	d.ResetBytes(data)
+
+	v := validator{}
+	if err := v.validate(s, d); err != nil {
+		return err
+	}
+	switch err := d.Skip(); err {
+	case io.EOF:
+	case nil:
+		return errors.New("unexpected trailing data")
+	default:
+		return errors.Wrap(err, "unexpected trailing data")
+	}
+	if len(v.errs) > 0 {
+		return v.errs
+	}
+	return nil
+}
+
+// pathToken is reference token of path to current value.
+type pathToken struct {
+	// Key is [start, end) of validator.keys, if start >= 0.
+	start, end int
+	idx        int
+}
+
+type validator struct {
+	errs Errors
+
+	path []pathToken
+	keys []byte // keys of path
+	seen []bool // stack of found required properties
+}
+
+// pointer returns JSON Pointer to current value.
+func (v *validator) pointer() string {
+	var b strings.Builder
+	for _, t := range v.path {
+		b.WriteByte('/')
+		if t.start < 0 {
+			b.WriteString(strconv.Itoa(t.idx))
+			continue
+		}
+		b.WriteString(escapePointer(string(v.keys[t.start:t.end])))
+	}
+	return b.String()
+}
+
+func (v *validator) fail(keyword, format string, args ...interface{}) {
+	v.errs = append(v.errs, Error{
+		Path:    v.pointer(),
+		Keyword: keyword,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (v *validator) pushKey(key []byte) {
+	start := len(v.keys)
+	v.keys = append(v.keys, key...)
+	v.path = append(v.path, pathToken{start: start, end: len(v.keys)})
+}
+
+func (v *validator) pushIndex(idx int) {
+	v.path = append(v.path, pathToken{start: -1, idx: idx})
+}
+
+func (v *validator) pop() {
+	t := v.path[len(v.path)-1]
+	if t.start >= 0 {
+		v.keys = v.keys[:t.start]
+	}
+	v.path = v.path[:len(v.path)-1]
+}
+
+// validate reads value from d and validates it against s, collecting
+// validation errors.
+func (v *validator) validate(s *Schema, d *jx.Decoder) error {
+	// Compiler checks that in-place applicators have no loops.
+	for sub := s.single(); sub != nil; sub = s.single() {
+		s = sub
+	}
+	switch {
+	case s.boolean:
+		if !s.valid {
+			v.fail("false", "value is not allowed")
+		}
+		return d.Skip()
+	case s.buffered():
+		raw, err := d.Raw()
+		if err != nil {
+			return err
+		}
+		// Raw of streaming decoder is valid until next read, but d
+		// is not read until raw is validated.
+		return v.validateRaw(s, raw)
+	case s.local:
+		return v.local(s, d)
+	default:
+		// Empty schema.
+		return d.Skip()
+	}
+}
+
+// validateRaw validates buffered value.
+func (v *validator) validateRaw(s *Schema, raw jx.Raw) error {
+	if s.enum != nil {
+		found := false
+		for _, e := range s.enum {
+			if equal(raw, e) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			v.fail("enum", "value is not one of %s", enumList(s.enum))
+		}
+	}
+	if s.cnst != nil && !equal(raw, s.cnst) {
+		v.fail("const", "value is not %s", s.cnst)
+	}
+
+	if s.ref != nil {
+		if err := v.validateSub(s.ref, raw); err != nil {
+			return err
+		}
+	}
+	for _, sub := range s.allOf {
+		if err := v.validateSub(sub, raw); err != nil {
+			return err
+		}
+	}
+	if len(s.anyOf) > 0 {
+		found := false
+		for _, sub := range s.anyOf {
+			ok, err := v.matches(sub, raw)
+			if err != nil {
+				return err
+			}
+			if ok {
+				found = true
+				break
+			}
+		}
+		if !found {
+			v.fail("anyOf", "value does not match any schema")
+		}
+	}
+	if len(s.oneOf) > 0 {
+		var matched []int
+		for i, sub := range s.oneOf {
+			ok, err := v.matches(sub, raw)
+			if err != nil {
+				return err
+			}
+			if ok {
+				matched = append(matched, i)
+			}
+		}
+		switch len(matched) {
+		case 0:
+			v.fail("oneOf", "value does not match any schema")
+		case 1:
+		default:
+			v.fail("oneOf", "value matches schemas %v, expected one", matched)
+		}
+	}
+	if s.not != nil {
+		ok, err := v.matches(s.not, raw)
+		if err != nil {
+			return err
+		}
+		if ok {
+			v.fail("not", "value matches schema")
+		}
+	}
+
+	if !s.local {
+		return nil
+	}
+	d := jx.GetDecoder()
+	defer jx.PutDecoder(d)
+	d.ResetBytes(raw)
+	return v.local(s, d)
+}
+
+// validateSub validates buffered value against subschema.
+func (v *validator) validateSub(s *Schema, raw jx.Raw) error {
+	d := jx.GetDecoder()
+	defer jx.PutDecoder(d)
+	d.ResetBytes(raw)
+	return v.validate(s, d)
+}
+
+// matches reports whether buffered value is valid against s, dropping
+// validation errors.
+func (v *validator) matches(s *Schema, raw jx.Raw) (bool, error) {
+	n := len(v.errs)
+	if err := v.validateSub(s, raw); err != nil {
+		return false, err
+	}
+	ok := len(v.errs) == n
+	v.errs = v.errs[:n]
+	return ok, nil
+}
+
+// local validates value against keywords of s, which are not in-place
+// applicators.
+func (v *validator) local(s *Schema, d *jx.Decoder) error {
+	switch t := d.Next(); t {
+	case jx.Object:
+		if !v.checkType(s, typeObject, t) {
+			return d.Skip()
+		}
+		return v.obj(s, d)
+	case jx.Array:
+		if !v.checkType(s, typeArray, t) {
+			return d.Skip()
+		}
+		return v.arr(s, d)
+	case jx.String:
+		str, err := d.StrBytes()
+		if err != nil {
+			return err
+		}
+		if v.checkType(s, typeString, t) {
+			v.str(s, str)
+		}
+		return nil
+	case jx.Number:
+		n, err := d.Num()
+		if err != nil {
+			return err
+		}
+		return v.num(s, n)
+	case jx.Bool:
+		if _, err := d.Bool(); err != nil {
+			return err
+		}
+		v.checkType(s, typeBoolean, t)
+		return nil
+	case jx.Null:
+		if err := d.Null(); err != nil {
+			return err
+		}
+		v.checkType(s, typeNull, t)
+		return nil
+	default:
+		return d.Skip()
+	}
+}
+
+func (v *validator) checkType(s *Schema, typ typeSet, t jx.Type) bool {
+	if s.types == 0 || s.types&typ != 0 {
+		return true
+	}
+	got := t.String()
+	if t == jx.Bool {
+		got = "boolean"
+	}
+	v.fail("type", "expected %s, got %s", s.types, got)
+	return false
+}
+
+func (v *validator) str(s *Schema, str []byte) {
+	if s.minLength >= 0 || s.maxLength >= 0 {
+		n := utf8.RuneCount(str)
+		if s.minLength >= 0 && n < s.minLength {
+			v.fail("minLength", "length %d is less than %d", n, s.minLength)
+		}
+		if s.maxLength >= 0 && n > s.maxLength {
+			v.fail("maxLength", "length %d is greater than %d", n, s.maxLength)
+		}
+	}
+	if s.pattern != nil && !s.pattern.Match(str) {
+		v.fail("pattern", "value does not match %q", s.pattern)
+	}
+	if s.isFormat != nil && !s.isFormat(string(str)) {
+		v.fail("format", "value is not valid %s", s.format)
+	}
+}
+
+func (v *validator) num(s *Schema, n jx.Num) error {
+	needValue := s.minimum != nil || s.maximum != nil ||
+		s.exclusiveMinimum != nil || s.exclusiveMaximum != nil ||
+		s.types&typeInteger != 0 && s.types&typeNumber == 0
+	if !needValue {
+		v.checkType(s, typeNumber, jx.Number)
+		return nil
+	}
+	f, err := numFloat(n)
+	if err != nil {
+		return err
+	}
+	if f.IsInt() {
+		if !v.checkType(s, typeNumber|typeInteger, jx.Number) {
+			return nil
+		}
+	} else if !v.checkType(s, typeNumber, jx.Number) {
+		return nil
+	}
+	for _, c := range [...]struct {
+		keyword string
+		b       *bound
+		fails   func(cmp int) bool
+		message string
+	}{
+		{"minimum", s.minimum, func(cmp int) bool { return cmp < 0 }, "less than"},
+		{"maximum", s.maximum, func(cmp int) bool { return cmp > 0 }, "greater than"},
+		{"exclusiveMinimum", s.exclusiveMinimum, func(cmp int) bool { return cmp <= 0 }, "less than or equal to"},
+		{"exclusiveMaximum", s.exclusiveMaximum, func(cmp int) bool { return cmp >= 0 }, "greater than or equal to"},
+	} {
+		if c.b != nil && c.fails(f.Cmp(c.b.v)) {
+			v.fail(c.keyword, "%s is %s %s", n, c.message, c.b.lit)
+		}
+	}
+	return nil
+}
+
+func (v *validator) obj(s *Schema, d *jx.Decoder) error {
+	seen := len(v.seen)
+	for range s.required {
+		v.seen = append(v.seen, false)
+	}
+	defer func() {
+		v.seen = v.seen[:seen]
+	}()
+
+	n := 0
+	if err := d.ObjBytes(func(d *jx.Decoder, key []byte) error {
+		n++
+		for i, name := range s.required {
+			if name == string(key) {
+				v.seen[seen+i] = true
+			}
+		}
+		v.pushKey(key)
+		defer v.pop()
+		return v.member(s, d, key)
+	}); err != nil {
+		return err
+	}
+
+	if s.minProperties >= 0 && n < s.minProperties {
+		v.fail("minProperties", "%d properties, expected at least %d", n, s.minProperties)
+	}
+	if s.maxProperties >= 0 && n > s.maxProperties {
+		v.fail("maxProperties", "%d properties, expected at most %d", n, s.maxProperties)
+	}
+	for i, name := range s.required {
+		if !v.seen[seen+i] {
+			v.fail("required", "missing property %q", name)
+		}
+	}
+	return nil
+}
+
+// member validates value of object member.
+func (v *validator) member(s *Schema, d *jx.Decoder, key []byte) error {
+	var (
+		buf  [4]*Schema
+		subs = buf[:0]
+	)
+	if sub, ok := s.properties[string(key)]; ok {
+		subs = append(subs, sub)
+	}
+	for _, p := range s.patternProperties {
+		if p.re.Match(key) {
+			subs = append(subs, p.schema)
+		}
+	}
+	if len(subs) == 0 && s.additionalProperties != nil {
+		subs = append(subs, s.additionalProperties)
+	}
+
+	switch len(subs) {
+	case 0:
+		return d.Skip()
+	case 1:
+		return v.validate(subs[0], d)
+	default:
+		raw, err := d.Raw()
+		if err != nil {
+			return err
+		}
+		for _, sub := range subs {
+			if err := v.validateSub(sub, raw); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func (v *validator) arr(s *Schema, d *jx.Decoder) error {
+	var (
+		n     = 0
+		elems []jx.Raw
+	)
+	if err := d.Arr(func(d *jx.Decoder) error {
+		idx := n
+		n++
+
+		sub := s.items
+		if idx < len(s.prefixItems) {
+			sub = s.prefixItems[idx]
+		}
+		if !s.uniqueItems {
+			if sub == nil {
+				return d.Skip()
+			}
+			v.pushIndex(idx)
+			defer v.pop()
+			return v.validate(sub, d)
+		}
+
+		// Array is buffered, so elements reference buffer.
+		raw, err := d.Raw()
+		if err != nil {
+			return err
+		}
+		for i, e := range elems {
+			if equal(raw, e) {
+				v.fail("uniqueItems", "elements %d and %d are equal", i, idx)
+				break
+			}
+		}
+		elems = append(elems, raw)
+		if sub == nil {
+			return nil
+		}
+		v.pushIndex(idx)
+		defer v.pop()
+		return v.validateSub(sub, raw)
+	}); err != nil {
+		return err
+	}
+
+	if s.minItems >= 0 && n < s.minItems {
+		v.fail("minItems", "%d items, expected at least %d", n, s.minItems)
+	}
+	if s.maxItems >= 0 && n > s.maxItems {
+		v.fail("maxItems", "%d items, expected at most %d", n, s.maxItems)
+	}
+	return nil
+}
+
+// errNotEqual stops comparison in equal.
+var errNotEqual = errors.New("not equal")
+
+// equal reports whether json values are equal, numbers are compared by
+// value.
+func equal(a, b jx.Raw) bool {
+	return jx.Diff(a, b, jx.DiffOptions{Numeric: true}, func(jx.Difference) error {
+		return errNotEqual
+	}) == nil
+}
+
+func enumList(values []jx.Raw) string {
+	var b strings.Builder
+	b.WriteByte('[')
+	for i, e := range values {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.Write(e)
+	}
+	b.WriteByte(']')
+	return b.String()
+}
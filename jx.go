@@ -39,6 +39,10 @@ func GetDecoder() *Decoder {
 // PutDecoder puts *Decoder into pool.
 func PutDecoder(d *Decoder) {
 	d.Reset(nil)
+	d.limits = Limits{}
+	d.noDupKeys = false
+	d.utf8 = UTF8Unchecked
+	d.json5 = false
 	decPool.Put(d)
 }
 
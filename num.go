@@ -199,3 +199,95 @@ func (n Num) Zero() bool {
 	}
 	return true
 }
+
+// numEqual reports whether raw json numbers are numerically equal,
+// regardless of their formats, so 1, 1.0 and 10e-1 are equal.
+//
+// Numbers are compared exactly, without conversion to float64.
+func numEqual(a, b []byte) bool {
+	x, y := parseDecimal(a), parseDecimal(b)
+	if x.len() == 0 || y.len() == 0 {
+		return x.len() == y.len()
+	}
+	if x.neg != y.neg || x.len() != y.len() || x.exp != y.exp {
+		return false
+	}
+	for i := 0; i < x.len(); i++ {
+		if x.at(i) != y.at(i) {
+			return false
+		}
+	}
+	return true
+}
+
+// decimal is number in form 0.d × 10^exp, where d are significant digits
+// of integer and fractional parts without leading and trailing zeros.
+type decimal struct {
+	neg        bool
+	int, frac  []byte
+	start, end int // significant digits of int followed by frac
+	exp        int64
+}
+
+func parseDecimal(n []byte) decimal {
+	if len(n) >= 2 && n[0] == '"' {
+		n = n[1 : len(n)-1]
+	}
+	var v decimal
+	if len(n) > 0 && n[0] == '-' {
+		v.neg = true
+		n = n[1:]
+	}
+	i := 0
+	for i < len(n) && n[i] >= '0' && n[i] <= '9' {
+		i++
+	}
+	v.int, n = n[:i], n[i:]
+	if len(n) > 0 && n[0] == '.' {
+		i = 1
+		for i < len(n) && n[i] >= '0' && n[i] <= '9' {
+			i++
+		}
+		v.frac, n = n[1:i], n[i:]
+	}
+	if len(n) > 1 && (n[0] == 'e' || n[0] == 'E') {
+		n = n[1:]
+		neg := n[0] == '-'
+		if n[0] == '-' || n[0] == '+' {
+			n = n[1:]
+		}
+		// Saturate exponent, such numbers can't be represented anyway.
+		const maxExp = 1 << 40
+		for _, c := range n {
+			if v.exp < maxExp {
+				v.exp = v.exp*10 + int64(c-'0')
+			}
+		}
+		if neg {
+			v.exp = -v.exp
+		}
+	}
+
+	v.end = len(v.int) + len(v.frac)
+	for v.start < v.end && v.at(0) == '0' {
+		v.start++
+	}
+	for v.end > v.start && v.digit(v.end-1) == '0' {
+		v.end--
+	}
+	v.exp += int64(len(v.int) - v.start)
+	return v
+}
+
+// len returns count of significant digits.
+func (v decimal) len() int { return v.end - v.start }
+
+// at returns i-th significant digit.
+func (v decimal) at(i int) byte { return v.digit(v.start + i) }
+
+func (v decimal) digit(i int) byte {
+	if i < len(v.int) {
+		return v.int[i]
+	}
+	return v.frac[i-len(v.int)]
+}
@@ -244,3 +244,33 @@ func BenchmarkNum(b *testing.B) {
 		})
 	})
 }
+
+func TestNumEqual(t *testing.T) {
+	for _, tt := range []struct {
+		a, b  string
+		equal bool
+	}{
+		{`0`, `0`, true},
+		{`0`, `-0`, true},
+		{`0`, `0.000e10`, true},
+		{`1`, `1.0`, true},
+		{`1`, `10e-1`, true},
+		{`1`, `0.1E+1`, true},
+		{`100`, `1e2`, true},
+		{`-12.5`, `-125e-1`, true},
+		{`0.001`, `1e-3`, true},
+		{`"12"`, `12.0`, true},
+		{`12345678901234567890123`, `1.2345678901234567890123e22`, true},
+		{`1`, `-1`, false},
+		{`1`, `0`, false},
+		{`1`, `2`, false},
+		{`1`, `10`, false},
+		{`1`, `1.0000000000000000000001`, false},
+		{`12`, `21`, false},
+		{`1e2`, `1e3`, false},
+		{`0.1`, `0.01`, false},
+	} {
+		require.Equal(t, tt.equal, numEqual([]byte(tt.a), []byte(tt.b)), "%s = %s", tt.a, tt.b)
+		require.Equal(t, tt.equal, numEqual([]byte(tt.b), []byte(tt.a)), "%s = %s", tt.b, tt.a)
+	}
+}
@@ -0,0 +1,548 @@
+package jx
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+
+	"github.com/go-faster/errors"
+)
+
+// ErrPatchTest means that "test" operation of JSON Patch failed.
+var ErrPatchTest = errors.New("test failed")
+
+// errPatchStop stops iteration over container in locatePatch.
+var errPatchStop = errors.New("stop")
+
+// PatchOp is operation of JSON Patch.
+type PatchOp struct {
+	// Op is one of "add", "remove", "replace", "move", "copy" or "test".
+	Op string
+	// Path is JSON Pointer to target location.
+	Path string
+	// From is JSON Pointer to source location of "move" and "copy".
+	From string
+	// Value of "add", "replace" and "test", nil if absent.
+	Value Raw
+}
+
+// Decode decodes operation from json object.
+//
+// Value is copied, so decoder buffer can be reused.
+func (op *PatchOp) Decode(d *Decoder) error {
+	*op = PatchOp{}
+	var hasPath, hasFrom bool
+	if err := d.ObjBytes(func(d *Decoder, key []byte) error {
+		var err error
+		switch string(key) {
+		case "op":
+			op.Op, err = d.Str()
+		case "path":
+			op.Path, err = d.Str()
+			hasPath = true
+		case "from":
+			op.From, err = d.Str()
+			hasFrom = true
+		case "value":
+			op.Value, err = d.RawAppend(nil)
+		default:
+			err = d.Skip()
+		}
+		return err
+	}); err != nil {
+		return err
+	}
+
+	if err := op.validate(); err != nil {
+		return err
+	}
+	switch {
+	case !hasPath:
+		return errors.Errorf("%s: missing %q", op.Op, "path")
+	case !hasFrom && (op.Op == "move" || op.Op == "copy"):
+		return errors.Errorf("%s: missing %q", op.Op, "from")
+	}
+	return nil
+}
+
+func (op PatchOp) validate() error {
+	switch op.Op {
+	case "add", "replace", "test":
+		if op.Value == nil {
+			return errors.Errorf("%s: missing %q", op.Op, "value")
+		}
+	case "remove", "move", "copy":
+	default:
+		return errors.Errorf("unknown op %q", op.Op)
+	}
+	return nil
+}
+
+// Encode encodes operation as json object.
+func (op PatchOp) Encode(e *Encoder) {
+	e.ObjStart()
+	e.FieldStart("op")
+	e.Str(op.Op)
+	switch op.Op {
+	case "move", "copy":
+		e.FieldStart("from")
+		e.Str(op.From)
+	}
+	e.FieldStart("path")
+	e.Str(op.Path)
+	if op.Value != nil {
+		e.FieldStart("value")
+		e.Raw(op.Value)
+	}
+	e.ObjEnd()
+}
+
+// Patch is JSON Patch document, as defined in RFC 6902.
+type Patch []PatchOp
+
+// Decode decodes patch from json array, appending operations to p.
+func (p *Patch) Decode(d *Decoder) error {
+	return d.Arr(func(d *Decoder) error {
+		var op PatchOp
+		if err := op.Decode(d); err != nil {
+			return errors.Wrapf(err, "op %d", len(*p))
+		}
+		*p = append(*p, op)
+		return nil
+	})
+}
+
+// Encode encodes patch as json array.
+func (p Patch) Encode(e *Encoder) {
+	e.ArrStart()
+	for _, op := range p {
+		op.Encode(e)
+	}
+	e.ArrEnd()
+}
+
+// Apply applies patch to json document, writing result to e.
+//
+// Operations are applied sequentially, and nothing is written if any of
+// them fails. Document is patched at byte level: only values on the way
+// to target locations are decoded, everything else is copied verbatim,
+// keeping original formatting, so small patches of large documents are
+// cheap.
+//
+// Returns ErrNotFound if target location does not exist and ErrPatchTest
+// if "test" operation fails.
+func (p Patch) Apply(e *Encoder, doc []byte) error {
+	if err := validateRaw(doc); err != nil {
+		return errors.Wrap(err, "document")
+	}
+	var (
+		a   patcher
+		cur = doc
+	)
+	for i, op := range p {
+		next, err := a.apply(cur, op)
+		if err != nil {
+			return errors.Wrapf(err, "op %d: %s %q", i, op.Op, op.Path)
+		}
+		cur = next
+	}
+	e.Raw(bytes.TrimSpace(cur))
+	return nil
+}
+
+// validateRaw validates that data is single json value.
+func validateRaw(data []byte) error {
+	d := GetDecoder()
+	defer PutDecoder(d)
+	d.ResetBytes(data)
+	return d.Validate()
+}
+
+// patcher applies operations of Patch, alternating between two buffers.
+type patcher struct {
+	buf   [2][]byte
+	i     int    // index of next buffer to write
+	key   []byte // encoded key of added member
+	value []byte // copy of moved value
+}
+
+func (a *patcher) apply(doc []byte, op PatchOp) ([]byte, error) {
+	if err := op.validate(); err != nil {
+		return nil, err
+	}
+	if op.Value != nil {
+		if err := validateRaw(op.Value); err != nil {
+			return nil, errors.Wrap(err, "value")
+		}
+	}
+	switch op.Op {
+	case "add":
+		return a.add(doc, op.Path, bytes.TrimSpace(op.Value))
+	case "remove":
+		return a.remove(doc, op.Path)
+	case "replace":
+		l, err := locatePatch(doc, op.Path)
+		if err != nil {
+			return nil, err
+		}
+		if !l.found {
+			return nil, l.notFound()
+		}
+		return a.splice(doc, l.start, l.end, bytes.TrimSpace(op.Value)), nil
+	case "move":
+		if op.From == op.Path {
+			_, err := getPatch(doc, op.From)
+			return doc, err
+		}
+		if strings.HasPrefix(op.Path, op.From+"/") {
+			return nil, errors.New("from is prefix of path")
+		}
+		v, err := getPatch(doc, op.From)
+		if err != nil {
+			return nil, errors.Wrap(err, "from")
+		}
+		// Value references doc, which is overwritten by add.
+		a.value = append(a.value[:0], v...)
+		if doc, err = a.remove(doc, op.From); err != nil {
+			return nil, errors.Wrap(err, "from")
+		}
+		return a.add(doc, op.Path, a.value)
+	case "copy":
+		v, err := getPatch(doc, op.From)
+		if err != nil {
+			return nil, errors.Wrap(err, "from")
+		}
+		return a.add(doc, op.Path, v)
+	default: // test
+		v, err := getPatch(doc, op.Path)
+		if err != nil {
+			return nil, err
+		}
+		if !rawEqual(v, op.Value) {
+			return nil, ErrPatchTest
+		}
+		return doc, nil
+	}
+}
+
+func (a *patcher) add(doc []byte, ptr string, v []byte) ([]byte, error) {
+	l, err := locatePatch(doc, ptr)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case l.found && l.parent == Array:
+		// Insert before existing element.
+		return a.splice(doc, l.item, l.item, v, []byte{','}), nil
+	case l.found:
+		return a.splice(doc, l.start, l.end, v), nil
+	case l.parent == Array && l.idx >= 0 && l.idx != l.n:
+		return nil, l.notFound()
+	}
+
+	// Append new member or element.
+	a.key = a.key[:0]
+	if l.last >= 0 {
+		a.key = append(a.key, ',')
+	}
+	if l.parent == Object {
+		w := Writer{Buf: a.key}
+		w.FieldStart(l.key)
+		a.key = w.Buf
+	}
+	at := l.close
+	if l.last >= 0 {
+		at = l.last
+	}
+	return a.splice(doc, at, at, a.key, v), nil
+}
+
+func (a *patcher) remove(doc []byte, ptr string) ([]byte, error) {
+	if ptr == "" {
+		return nil, errors.New("can't remove root")
+	}
+	l, err := locatePatch(doc, ptr)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case !l.found:
+		return nil, l.notFound()
+	case !l.first:
+		// Remove with preceding comma.
+		return a.splice(doc, l.prev, l.end), nil
+	case l.next >= 0:
+		// Remove with following comma.
+		return a.splice(doc, l.item, l.next), nil
+	default:
+		return a.splice(doc, l.prev, l.close), nil
+	}
+}
+
+// splice returns doc with bytes in [from, to) replaced by parts.
+func (a *patcher) splice(doc []byte, from, to int, parts ...[]byte) []byte {
+	buf := append(a.buf[a.i][:0], doc[:from]...)
+	for _, p := range parts {
+		buf = append(buf, p...)
+	}
+	buf = append(buf, doc[to:]...)
+	a.buf[a.i] = buf
+	a.i ^= 1
+	return buf
+}
+
+// getPatch returns value referenced by pointer.
+func getPatch(doc []byte, ptr string) ([]byte, error) {
+	l, err := locatePatch(doc, ptr)
+	if err != nil {
+		return nil, err
+	}
+	if !l.found {
+		return nil, l.notFound()
+	}
+	return doc[l.start:l.end], nil
+}
+
+// patchLoc is location referenced by pointer in document.
+//
+// All offsets are offsets in document.
+type patchLoc struct {
+	parent Type   // type of container, Invalid for root
+	key    string // unescaped key of object member
+	idx    int    // index of array element, -1 for "-"
+	found  bool   // value exists
+
+	// Value, if found.
+	item  int  // offset of member key or array element
+	start int  // offset of value
+	end   int  // offset after value
+	prev  int  // offset after previous value or opening bracket
+	first bool // value is first in container
+	next  int  // offset of next member or element, -1 if none
+
+	// Container, set if value is not found or is last.
+	n     int // count of members or elements
+	last  int // offset after last value, -1 if container is empty
+	close int // offset of closing bracket
+}
+
+func (l patchLoc) notFound() error {
+	if l.parent == Object {
+		return errors.Wrapf(ErrNotFound, "field %q", l.key)
+	}
+	return errors.Wrapf(ErrNotFound, "index %d", l.idx)
+}
+
+// locatePatch finds location referenced by pointer.
+//
+// Parent of location must exist.
+func locatePatch(doc []byte, ptr string) (patchLoc, error) {
+	l := patchLoc{next: -1, last: -1}
+	if ptr == "" {
+		l.found = true
+		l.first = true
+		l.start = len(doc) - len(bytes.TrimLeft(doc, " \t\n\r"))
+		l.end = len(bytes.TrimRight(doc, " \t\n\r"))
+		return l, nil
+	}
+	if ptr[0] != '/' {
+		return l, errors.Errorf("invalid pointer %q: must start with %q", ptr, "/")
+	}
+	i := strings.LastIndexByte(ptr, '/')
+	parent, tok := ptr[:i], ptr[i+1:]
+	if err := validatePointerToken(tok); err != nil {
+		return l, errors.Wrapf(err, "invalid pointer token %q", tok)
+	}
+
+	d := GetDecoder()
+	defer PutDecoder(d)
+	d.ResetBytes(doc)
+	if err := d.Seek(parent); err != nil {
+		return l, err
+	}
+	switch l.parent = d.Next(); l.parent {
+	case Object:
+		l.key = unescapePointer(tok)
+	case Array:
+		l.idx = -1
+		if tok != "-" {
+			idx, err := pointerIndex(tok)
+			if err != nil {
+				return l, err
+			}
+			l.idx = idx
+		}
+	default:
+		return l, errors.Wrapf(ErrNotFound, "%s has no %q", l.parent, tok)
+	}
+
+	prev := d.offset() + 1
+	item := func(d *Decoder, match bool) error {
+		item := skipPatchSep(doc, prev)
+		start := skipPatchSep(doc, d.offset())
+		if err := d.Skip(); err != nil {
+			return err
+		}
+		end := d.offset()
+		if l.found {
+			l.next = item
+			return errPatchStop
+		}
+		if match {
+			l.found = true
+			l.item, l.start, l.end = item, start, end
+			l.prev = prev
+			l.first = l.n == 0
+		}
+		l.n++
+		l.last = end
+		prev = end
+		return nil
+	}
+	var err error
+	if l.parent == Object {
+		err = d.ObjBytes(func(d *Decoder, key []byte) error {
+			return item(d, pointerTokenEqual(tok, key))
+		})
+	} else {
+		err = d.Arr(func(d *Decoder) error {
+			return item(d, l.n == l.idx)
+		})
+	}
+	switch {
+	case err == nil:
+		l.close = d.offset() - 1
+		return l, nil
+	case errors.Is(err, errPatchStop):
+		return l, nil
+	default:
+		return l, err
+	}
+}
+
+// skipPatchSep returns offset of first byte after i, which is not
+// whitespace or comma.
+func skipPatchSep(doc []byte, i int) int {
+	for i < len(doc) {
+		switch doc[i] {
+		case ' ', '\t', '\n', '\r', ',':
+			i++
+		default:
+			return i
+		}
+	}
+	return i
+}
+
+// GeneratePatch returns JSON Patch, which transforms document from to
+// document to.
+//
+// Objects are compared by members and arrays by elements at the same
+// indexes, so patch is not always minimal: element inserted to the
+// beginning of array replaces all elements after it.
+//
+// Values of patch reference to.
+func GeneratePatch(from, to []byte) (Patch, error) {
+	if err := validateRaw(from); err != nil {
+		return nil, errors.Wrap(err, "from")
+	}
+	if err := validateRaw(to); err != nil {
+		return nil, errors.Wrap(err, "to")
+	}
+	var p Patch
+	if err := p.diff("", bytes.TrimSpace(from), bytes.TrimSpace(to)); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// rawMember is object member of Raw value.
+type rawMember struct {
+	key string
+	v   Raw
+}
+
+// rawMembers returns members of object and index of last member with
+// given key.
+func rawMembers(r Raw) (m []rawMember, idx map[string]int, _ error) {
+	idx = map[string]int{}
+	err := r.Obj(func(key []byte, v Raw) error {
+		idx[string(key)] = len(m)
+		m = append(m, rawMember{key: string(key), v: v})
+		return nil
+	})
+	return m, idx, err
+}
+
+func rawElems(r Raw) (elems []Raw, _ error) {
+	err := r.Arr(func(v Raw) error {
+		elems = append(elems, v)
+		return nil
+	})
+	return elems, err
+}
+
+func (p *Patch) diff(path string, from, to Raw) error {
+	switch ft, tt := from.Type(), to.Type(); {
+	case ft == Object && tt == Object:
+		return p.diffObj(path, from, to)
+	case ft == Array && tt == Array:
+		return p.diffArr(path, from, to)
+	}
+	if !rawEqual(from, to) {
+		*p = append(*p, PatchOp{Op: "replace", Path: path, Value: to})
+	}
+	return nil
+}
+
+func (p *Patch) diffObj(path string, from, to Raw) error {
+	fromMembers, fromIdx, err := rawMembers(from)
+	if err != nil {
+		return err
+	}
+	toMembers, toIdx, err := rawMembers(to)
+	if err != nil {
+		return err
+	}
+	for _, m := range fromMembers {
+		if _, ok := toIdx[m.key]; !ok {
+			*p = append(*p, PatchOp{Op: "remove", Path: path + "/" + escapePointer(m.key)})
+		}
+	}
+	for _, m := range toMembers {
+		child := path + "/" + escapePointer(m.key)
+		j, ok := fromIdx[m.key]
+		if !ok {
+			*p = append(*p, PatchOp{Op: "add", Path: child, Value: m.v})
+			continue
+		}
+		if err := p.diff(child, fromMembers[j].v, m.v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *Patch) diffArr(path string, from, to Raw) error {
+	fromElems, err := rawElems(from)
+	if err != nil {
+		return err
+	}
+	toElems, err := rawElems(to)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < len(fromElems) && i < len(toElems); i++ {
+		if err := p.diff(path+"/"+strconv.Itoa(i), fromElems[i], toElems[i]); err != nil {
+			return err
+		}
+	}
+	// Remove from the end, so indexes of other elements are kept.
+	for i := len(fromElems) - 1; i >= len(toElems); i-- {
+		*p = append(*p, PatchOp{Op: "remove", Path: path + "/" + strconv.Itoa(i)})
+	}
+	for i := len(fromElems); i < len(toElems); i++ {
+		*p = append(*p, PatchOp{Op: "add", Path: path + "/" + strconv.Itoa(i), Value: toElems[i]})
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package jx
+
+import (
+	"bytes"
+
+	"github.com/go-faster/errors"
+)
+
+// ApplyMergePatch applies JSON Merge Patch, as defined in RFC 7386, to
+// document read from d, writing result to e.
+//
+// Document is streamed: only objects, which members are patched, are
+// decoded, and all other values are copied to e as Raw, so memory usage
+// does not depend on size of document.
+//
+// Document is validated while being read, so e can contain partial result
+// if error is returned.
+func ApplyMergePatch(e *Encoder, d *Decoder, patch []byte) error {
+	if err := validateRaw(patch); err != nil {
+		return errors.Wrap(err, "patch")
+	}
+	return mergePatch(e, d, bytes.TrimSpace(patch))
+}
+
+func mergePatch(e *Encoder, d *Decoder, patch Raw) error {
+	if patch.Type() != Object {
+		if err := d.Skip(); err != nil {
+			return err
+		}
+		e.Raw(patch)
+		return nil
+	}
+	if d.Next() != Object {
+		if err := d.Skip(); err != nil {
+			return err
+		}
+		return writeMergePatch(e, patch)
+	}
+
+	members, idx, err := rawMembers(patch)
+	if err != nil {
+		return err
+	}
+	patched := make([]bool, len(members))
+	e.ObjStart()
+	if err := d.ObjBytes(func(d *Decoder, key []byte) error {
+		i, ok := idx[string(key)]
+		if !ok {
+			v, err := d.Raw()
+			if err != nil {
+				return err
+			}
+			e.FieldStart(string(key))
+			e.Raw(v)
+			return nil
+		}
+		patched[i] = true
+		v := members[i].v
+		if v.Type() == Null {
+			return d.Skip()
+		}
+		e.FieldStart(string(key))
+		return mergePatch(e, d, v)
+	}); err != nil {
+		return err
+	}
+	for i, m := range members {
+		if patched[i] || idx[m.key] != i || m.v.Type() == Null {
+			continue
+		}
+		e.FieldStart(m.key)
+		if err := writeMergePatch(e, m.v); err != nil {
+			return err
+		}
+	}
+	e.ObjEnd()
+	return nil
+}
+
+// writeMergePatch writes result of applying patch to non-object value,
+// which is patch without null members.
+func writeMergePatch(e *Encoder, patch Raw) error {
+	if patch.Type() != Object {
+		e.Raw(patch)
+		return nil
+	}
+	members, idx, err := rawMembers(patch)
+	if err != nil {
+		return err
+	}
+	e.ObjStart()
+	for i, m := range members {
+		if idx[m.key] != i || m.v.Type() == Null {
+			continue
+		}
+		e.FieldStart(m.key)
+		if err := writeMergePatch(e, m.v); err != nil {
+			return err
+		}
+	}
+	e.ObjEnd()
+	return nil
+}
+
+// GenerateMergePatch writes JSON Merge Patch, which transforms document
+// from to document to, to e.
+//
+// Only objects are compared member by member, other values are replaced
+// as a whole. Merge patch can't set null values of object members, so such
+// members of to are removed by generated patch.
+func GenerateMergePatch(e *Encoder, from, to []byte) error {
+	if err := validateRaw(from); err != nil {
+		return errors.Wrap(err, "from")
+	}
+	if err := validateRaw(to); err != nil {
+		return errors.Wrap(err, "to")
+	}
+	return generateMergePatch(e, bytes.TrimSpace(from), bytes.TrimSpace(to))
+}
+
+func generateMergePatch(e *Encoder, from, to Raw) error {
+	if from.Type() != Object || to.Type() != Object {
+		e.Raw(to)
+		return nil
+	}
+	fromMembers, fromIdx, err := rawMembers(from)
+	if err != nil {
+		return err
+	}
+	toMembers, toIdx, err := rawMembers(to)
+	if err != nil {
+		return err
+	}
+
+	e.ObjStart()
+	for i, m := range fromMembers {
+		if _, ok := toIdx[m.key]; ok || fromIdx[m.key] != i {
+			continue
+		}
+		e.FieldStart(m.key)
+		e.Null()
+	}
+	for i, m := range toMembers {
+		if toIdx[m.key] != i {
+			continue
+		}
+		j, ok := fromIdx[m.key]
+		if !ok {
+			e.FieldStart(m.key)
+			e.Raw(m.v)
+			continue
+		}
+		if v := fromMembers[j].v; !rawEqual(v, m.v) {
+			e.FieldStart(m.key)
+			if err := generateMergePatch(e, v, m.v); err != nil {
+				return err
+			}
+		}
+	}
+	e.ObjEnd()
+	return nil
+}
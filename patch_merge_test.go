@@ -0,0 +1,127 @@
+package jx
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyMergePatch(t *testing.T) {
+	for i, tt := range []struct {
+		doc    string
+		patch  string
+		expect string
+	}{
+		// RFC 7386, appendix A.
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"e":null,"a":1}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+
+		// Untouched values are copied.
+		{`{"a": [1, {"b": null}], "b": 1}`, `{"b": 2}`, `{"a":[1, {"b": null}],"b":2}`},
+		{` {"a": {"b": [ 1 ], "c": 1}} `, `{"a": {"c": null}}`, `{"a":{"b":[ 1 ]}}`},
+		// Last duplicate of patch member is used.
+		{`{"a":1}`, `{"a":2,"a":3,"b":4,"b":null}`, `{"a":3}`},
+		{`1`, `{"a":{"b":1,"b":null}}`, `{"a":{}}`},
+	} {
+		tt := tt
+		t.Run(fmt.Sprintf("Test%d", i+1), func(t *testing.T) {
+			for _, d := range []*Decoder{
+				DecodeStr(tt.doc),
+				Decode(iotest.OneByteReader(strings.NewReader(tt.doc)), 1),
+			} {
+				var e Encoder
+				require.NoError(t, ApplyMergePatch(&e, d, []byte(tt.patch)))
+				require.Equal(t, tt.expect, e.String())
+			}
+		})
+	}
+	t.Run("Error", func(t *testing.T) {
+		for _, tt := range []struct {
+			doc, patch string
+		}{
+			{`{}`, `{`},
+			{`{}`, `{} {}`},
+			{`{"a":`, `{}`},
+			{`{"a":1`, `{"b":1}`},
+			{`{"a":[1,}`, `{"b":1}`},
+			{`[1,]`, `{}`},
+			{`[1,]`, `1`},
+		} {
+			var e Encoder
+			require.Error(t, ApplyMergePatch(&e, DecodeStr(tt.doc), []byte(tt.patch)), "%s %s", tt.doc, tt.patch)
+		}
+	})
+}
+
+func TestGenerateMergePatch(t *testing.T) {
+	docs := []string{
+		`null`,
+		`1`,
+		`"foo"`,
+		`[]`,
+		`[1,null]`,
+		`{}`,
+		`{"a":1,"b":[1,2],"c":{"d":"e"}}`,
+		`{"c":{"d":"f","g":1},"b":[1],"a":1.0}`,
+		`{"a":{"b":{"c":[]}},"":true}`,
+		`{"a":{"b":{"d":false}}}`,
+	}
+	for _, from := range docs {
+		for _, to := range docs {
+			var patch Encoder
+			require.NoError(t, GenerateMergePatch(&patch, []byte(from), []byte(to)))
+
+			var e Encoder
+			require.NoError(t, ApplyMergePatch(&e, DecodeStr(from), patch.Bytes()))
+			require.True(t, rawEqual(Raw(to), e.Bytes()), "%s -> %s: %s", from, to, e.Bytes())
+		}
+	}
+
+	var e Encoder
+	require.NoError(t, GenerateMergePatch(&e,
+		[]byte(`{"a":{"b":1,"c":[1]},"d":1,"e":1}`),
+		[]byte(`{"a":{"b":1.0,"c":[2]},"e":1,"f":{"g":null}}`),
+	))
+	require.Equal(t, `{"d":null,"a":{"c":[2]},"f":{"g":null}}`, e.String())
+
+	require.Error(t, GenerateMergePatch(&e, []byte(`{`), []byte(`{}`)))
+	require.Error(t, GenerateMergePatch(&e, []byte(`{}`), []byte(`{`)))
+}
+
+func BenchmarkApplyMergePatch(b *testing.B) {
+	runTestdataFile("citm_catalog.json", b.Fatal, func(name string, data []byte) {
+		patch := []byte(`{"areaNames":{"205705993":"foo"},"subjectNames":null}`)
+		var (
+			d = DecodeBytes(data)
+			e = GetEncoder()
+		)
+		defer PutEncoder(e)
+
+		b.ReportAllocs()
+		b.SetBytes(int64(len(data)))
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			d.ResetBytes(data)
+			e.Reset()
+			if err := ApplyMergePatch(e, d, patch); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,311 @@
+package jx
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func applyPatch(t *testing.T, doc, patch string) (string, error) {
+	t.Helper()
+
+	var p Patch
+	require.NoError(t, p.Decode(DecodeStr(patch)))
+	e := GetEncoder()
+	defer PutEncoder(e)
+	if err := p.Apply(e, []byte(doc)); err != nil {
+		return "", err
+	}
+	return e.String(), nil
+}
+
+func TestPatch_Apply(t *testing.T) {
+	for i, tt := range []struct {
+		doc    string
+		patch  string
+		expect string
+	}{
+		// RFC 6902, appendix A.
+		{
+			`{"foo":"bar"}`,
+			`[{"op":"add","path":"/baz","value":"qux"}]`,
+			`{"foo":"bar","baz":"qux"}`,
+		},
+		{
+			`{"foo":["bar","baz"]}`,
+			`[{"op":"add","path":"/foo/1","value":"qux"}]`,
+			`{"foo":["bar","qux","baz"]}`,
+		},
+		{
+			`{"baz":"qux","foo":"bar"}`,
+			`[{"op":"remove","path":"/baz"}]`,
+			`{"foo":"bar"}`,
+		},
+		{
+			`{"foo":["bar","qux","baz"]}`,
+			`[{"op":"remove","path":"/foo/1"}]`,
+			`{"foo":["bar","baz"]}`,
+		},
+		{
+			`{"baz":"qux","foo":"bar"}`,
+			`[{"op":"replace","path":"/baz","value":"boo"}]`,
+			`{"baz":"boo","foo":"bar"}`,
+		},
+		{
+			`{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`,
+			`[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`,
+			`{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`,
+		},
+		{
+			`{"foo":["all","grass","cows","eat"]}`,
+			`[{"op":"move","from":"/foo/1","path":"/foo/3"}]`,
+			`{"foo":["all","cows","eat","grass"]}`,
+		},
+		{
+			`{"baz":"qux","foo":["a",2,"c"]}`,
+			`[{"op":"test","path":"/baz","value":"qux"},{"op":"test","path":"/foo/1","value":2}]`,
+			`{"baz":"qux","foo":["a",2,"c"]}`,
+		},
+		{
+			`{"foo":"bar"}`,
+			`[{"op":"add","path":"/child","value":{"grandchild":{}}}]`,
+			`{"foo":"bar","child":{"grandchild":{}}}`,
+		},
+		{
+			`{"foo":"bar"}`,
+			`[{"op":"add","path":"/baz","value":"qux","xyz":123}]`,
+			`{"foo":"bar","baz":"qux"}`,
+		},
+		{
+			`{"/":9,"~1":10}`,
+			`[{"op":"test","path":"/~01","value":10}]`,
+			`{"/":9,"~1":10}`,
+		},
+		{
+			`{"foo":["bar"]}`,
+			`[{"op":"add","path":"/foo/-","value":["abc","def"]}]`,
+			`{"foo":["bar",["abc","def"]]}`,
+		},
+
+		// Formatting is kept.
+		{
+			`{ "a": 1, "b": [ 1, 2 ] }`,
+			`[{"op":"remove","path":"/a"}]`,
+			`{ "b": [ 1, 2 ] }`,
+		},
+		{
+			`{ "a": 1, "b": 2 }`,
+			`[{"op":"remove","path":"/b"}]`,
+			`{ "a": 1 }`,
+		},
+		{
+			`{ "a": 1 }`,
+			`[{"op":"remove","path":"/a"}]`,
+			`{}`,
+		},
+		{
+			`[ ]`,
+			`[{"op":"add","path":"/-","value":1}]`,
+			`[ 1]`,
+		},
+		{
+			`[1, 2]`,
+			`[{"op":"add","path":"/2","value":3},{"op":"add","path":"/0","value": 0 }]`,
+			`[0,1, 2,3]`,
+		},
+		{
+			`{}`,
+			`[{"op":"add","path":"/a\"~1b","value":1},{"op":"add","path":"/c","value":2}]`,
+			`{"a\"/b":1,"c":2}`,
+		},
+
+		// Root.
+		{
+			` {"a":1} `,
+			`[{"op":"replace","path":"","value":[1]}]`,
+			`[1]`,
+		},
+		{
+			`{"a":1}`,
+			`[{"op":"add","path":"","value":2},{"op":"test","path":"","value":2.0}]`,
+			`2`,
+		},
+
+		// Copy and move.
+		{
+			`{"a":{"b":[1]},"c":{}}`,
+			`[{"op":"copy","from":"/a/b","path":"/c/d"},{"op":"add","path":"/c/d/-","value":2}]`,
+			`{"a":{"b":[1]},"c":{"d":[1,2]}}`,
+		},
+		{
+			`{"a":[1,2,3]}`,
+			`[{"op":"move","from":"/a/0","path":"/a/-"},{"op":"move","from":"/a","path":"/a"}]`,
+			`{"a":[2,3,1]}`,
+		},
+		{
+			`{"a":{"b":1},"c":2}`,
+			`[{"op":"move","from":"/a","path":"/c"}]`,
+			`{"c":{"b":1}}`,
+		},
+
+		// Semantic equality of test.
+		{
+			`{"a":{"x":1e2,"y":"A"},"b":[1,{}]}`,
+			`[{"op":"test","path":"","value":{"b":[1.0,{}],"a":{"y":"A","x":100}}}]`,
+			`{"a":{"x":1e2,"y":"A"},"b":[1,{}]}`,
+		},
+	} {
+		tt := tt
+		t.Run(fmt.Sprintf("Test%d", i+1), func(t *testing.T) {
+			got, err := applyPatch(t, tt.doc, tt.patch)
+			require.NoError(t, err)
+			require.Equal(t, tt.expect, got)
+		})
+	}
+}
+
+func TestPatch_Apply_error(t *testing.T) {
+	for i, tt := range []struct {
+		doc    string
+		patch  string
+		target error
+	}{
+		{`{"baz":"qux"}`, `[{"op":"test","path":"/baz","value":"bar"}]`, ErrPatchTest},
+		{`{"/":9,"~1":10}`, `[{"op":"test","path":"/~01","value":"10"}]`, ErrPatchTest},
+		{`[1,2]`, `[{"op":"test","path":"","value":[2,1]}]`, ErrPatchTest},
+		{`{"a":1}`, `[{"op":"test","path":"","value":{"a":1,"b":2}}]`, ErrPatchTest},
+		{`{"foo":"bar"}`, `[{"op":"add","path":"/baz/bat","value":"qux"}]`, ErrNotFound},
+		{`{"foo":"bar"}`, `[{"op":"remove","path":"/baz"}]`, ErrNotFound},
+		{`{"foo":"bar"}`, `[{"op":"replace","path":"/baz","value":1}]`, ErrNotFound},
+		{`{"foo":"bar"}`, `[{"op":"copy","from":"/baz","path":"/x"}]`, ErrNotFound},
+		{`[1,2]`, `[{"op":"add","path":"/3","value":1}]`, ErrNotFound},
+		{`[1,2]`, `[{"op":"remove","path":"/-"}]`, ErrNotFound},
+		{`[1,2]`, `[{"op":"test","path":"/2","value":1}]`, ErrNotFound},
+		{`"foo"`, `[{"op":"add","path":"/a","value":1}]`, ErrNotFound},
+		{`{"a":1}`, `[{"op":"remove","path":"/a"},{"op":"remove","path":"/a"}]`, ErrNotFound},
+		{`{"a":1}`, `[{"op":"remove","path":""}]`, nil},
+		{`{"a":{}}`, `[{"op":"move","from":"/a","path":"/a/b"}]`, nil},
+		{`[1,2]`, `[{"op":"add","path":"/01","value":1}]`, nil},
+		{`{"a":1}`, `[{"op":"add","path":"a","value":1}]`, nil},
+		{`{"a":1}`, `[{"op":"add","path":"/~2","value":1}]`, nil},
+		{`{"a":1`, `[]`, nil},
+		{`{"a":1} 1`, `[]`, nil},
+	} {
+		tt := tt
+		t.Run(fmt.Sprintf("Test%d", i+1), func(t *testing.T) {
+			_, err := applyPatch(t, tt.doc, tt.patch)
+			require.Error(t, err)
+			if tt.target != nil {
+				require.ErrorIs(t, err, tt.target)
+			}
+		})
+	}
+	t.Run("Invalid", func(t *testing.T) {
+		for _, p := range []Patch{
+			{{Op: "foo", Path: "/a"}},
+			{{Op: "add", Path: "/a"}},
+			{{Op: "add", Path: "/a", Value: Raw(`{`)}},
+		} {
+			e := GetEncoder()
+			require.Error(t, p.Apply(e, []byte(`{}`)))
+			require.Empty(t, e.Bytes())
+			PutEncoder(e)
+		}
+	})
+}
+
+func TestPatch_Decode(t *testing.T) {
+	for _, input := range []string{
+		`{}`,
+		`[{}]`,
+		`[{"op":"add","path":"/a"}]`,
+		`[{"op":"add","value":1}]`,
+		`[{"op":"move","path":"/a"}]`,
+		`[{"op":"copy","path":"/a"}]`,
+		`[{"op":"foo","path":"/a"}]`,
+		`[{"op":1,"path":"/a"}]`,
+	} {
+		var p Patch
+		require.Error(t, p.Decode(DecodeStr(input)), input)
+	}
+
+	const input = `[{"op":"test","path":"/a","value":null},{"op":"remove","path":"/b"},{"op":"move","from":"/b","path":"/c"}]`
+	var p Patch
+	require.NoError(t, p.Decode(DecodeStr(input)))
+	require.Equal(t, Patch{
+		{Op: "test", Path: "/a", Value: Raw("null")},
+		{Op: "remove", Path: "/b"},
+		{Op: "move", From: "/b", Path: "/c"},
+	}, p)
+
+	var e Encoder
+	p.Encode(&e)
+	require.Equal(t, input, e.String())
+}
+
+func TestGeneratePatch(t *testing.T) {
+	docs := []string{
+		`null`,
+		`1`,
+		`1.0`,
+		`"foo"`,
+		`[]`,
+		`[1,2,3]`,
+		`[1,[2,3],{"a":4}]`,
+		`[3,2,1,0]`,
+		`{}`,
+		`{"a":1,"b":[1,2],"c":{"d":"e"}}`,
+		`{"c":{"d":"f","g":null},"b":[1],"a":1.0}`,
+		`{"a/b":{"~":[]},"":true}`,
+		`{"a/b":{"~":[false]}}`,
+	}
+	for _, from := range docs {
+		for _, to := range docs {
+			p, err := GeneratePatch([]byte(from), []byte(to))
+			require.NoError(t, err)
+			if rawEqual(Raw(from), Raw(to)) {
+				require.Empty(t, p, "%s -> %s", from, to)
+			}
+
+			var e Encoder
+			require.NoError(t, p.Apply(&e, []byte(from)))
+			require.True(t, rawEqual(Raw(to), e.Bytes()), "%s -> %s: %s", from, to, e.Bytes())
+		}
+	}
+
+	p, err := GeneratePatch([]byte(`{"a":[1,2,3],"b":1}`), []byte(`{"a":[1,5],"c":1}`))
+	require.NoError(t, err)
+	require.Equal(t, Patch{
+		{Op: "remove", Path: "/b"},
+		{Op: "replace", Path: "/a/1", Value: Raw("5")},
+		{Op: "remove", Path: "/a/2"},
+		{Op: "add", Path: "/c", Value: Raw("1")},
+	}, p)
+
+	_, err = GeneratePatch([]byte(`{`), []byte(`{}`))
+	require.Error(t, err)
+	_, err = GeneratePatch([]byte(`{}`), []byte(`{`))
+	require.Error(t, err)
+}
+
+func BenchmarkPatch_Apply(b *testing.B) {
+	runTestdataFile("citm_catalog.json", b.Fatal, func(name string, data []byte) {
+		p := Patch{
+			{Op: "replace", Path: "/areaNames/205705993", Value: Raw(`"foo"`)},
+			{Op: "add", Path: "/events/138586341/topicIds/-", Value: Raw(`1`)},
+		}
+		e := GetEncoder()
+		defer PutEncoder(e)
+
+		b.ReportAllocs()
+		b.SetBytes(int64(len(data)))
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			e.Reset()
+			if err := p.Apply(e, data); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,213 @@
+package jx
+
+import "github.com/go-faster/errors"
+
+// Projection selects parts of json document by paths, like "fields" query
+// parameter of REST API does.
+//
+// Paths are JSON Pointers to object members. Arrays are transparent, so
+// path applies to every element of array: "/items/id" selects "id" member
+// of every element of "items" array. Selecting member selects all its
+// subtree.
+//
+// Projection is safe for concurrent use.
+type Projection struct {
+	root    projNode
+	exclude bool
+}
+
+// projNode is node of path tree.
+type projNode struct {
+	// all is set if whole value is selected by path.
+	all      bool
+	children map[string]*projNode
+}
+
+// IncludePaths returns Projection, which keeps only values on given paths.
+//
+// Objects and arrays on the way to selected values are kept, other values
+// are removed. If document itself is not object or array and is not
+// selected by empty path, it is replaced with null.
+func IncludePaths(paths ...string) (*Projection, error) {
+	return newProjection(paths, false)
+}
+
+// ExcludePaths returns Projection, which removes values on given paths,
+// keeping all other values.
+func ExcludePaths(paths ...string) (*Projection, error) {
+	p, err := newProjection(paths, true)
+	if err != nil {
+		return nil, err
+	}
+	if p.root.all {
+		return nil, errors.New("whole document can't be excluded")
+	}
+	return p, nil
+}
+
+func newProjection(paths []string, exclude bool) (*Projection, error) {
+	p := &Projection{exclude: exclude}
+	for _, ptr := range paths {
+		if err := p.root.add(ptr); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// add adds JSON Pointer to tree.
+func (n *projNode) add(ptr string) error {
+	if ptr == "" {
+		n.selectAll()
+		return nil
+	}
+	if ptr[0] != '/' {
+		return errors.Errorf("invalid pointer %q: must start with %q", ptr, "/")
+	}
+	rest := ptr[1:]
+	for !n.all {
+		tok, next, more := cutPointer(rest)
+		if err := validatePointerToken(tok); err != nil {
+			return errors.Wrapf(err, "invalid pointer %q", ptr)
+		}
+		key := unescapePointer(tok)
+		child, ok := n.children[key]
+		if !ok {
+			if n.children == nil {
+				n.children = map[string]*projNode{}
+			}
+			child = &projNode{}
+			n.children[key] = child
+		}
+		n = child
+		if !more {
+			n.selectAll()
+			break
+		}
+		rest = next
+	}
+	return nil
+}
+
+// selectAll selects whole subtree, so longer paths are no-op.
+func (n *projNode) selectAll() {
+	n.all = true
+	n.children = nil
+}
+
+// Apply reads value from d and writes its projection to e.
+//
+// Selected and kept values are copied as Raw, removed values are skipped,
+// so only objects and arrays on the way to paths are decoded, and both
+// d and e can be streaming.
+//
+// Document is validated while being read, so e can contain partial result
+// if error is returned.
+func (p *Projection) Apply(e *Encoder, d *Decoder) error {
+	if p.exclude {
+		return p.root.exclude(e, d)
+	}
+	return p.root.include(e, d)
+}
+
+// selects reports whether value d is positioned at should be written by
+// include.
+func (n *projNode) selects(d *Decoder) bool {
+	if n.all {
+		return true
+	}
+	t := d.Next()
+	return t == Object || t == Array
+}
+
+func (n *projNode) include(e *Encoder, d *Decoder) error {
+	if n.all {
+		return copyRaw(e, d)
+	}
+	switch d.Next() {
+	case Object:
+		e.ObjStart()
+		if err := d.ObjBytes(func(d *Decoder, key []byte) error {
+			child, ok := n.children[string(key)]
+			if !ok || !child.selects(d) {
+				return d.Skip()
+			}
+			e.FieldStart(string(key))
+			return child.include(e, d)
+		}); err != nil {
+			return err
+		}
+		e.ObjEnd()
+		return nil
+	case Array:
+		e.ArrStart()
+		if err := d.Arr(func(d *Decoder) error {
+			if !n.selects(d) {
+				return d.Skip()
+			}
+			return n.include(e, d)
+		}); err != nil {
+			return err
+		}
+		e.ArrEnd()
+		return nil
+	default:
+		// Document is not selected.
+		if err := d.Skip(); err != nil {
+			return err
+		}
+		e.Null()
+		return nil
+	}
+}
+
+func (n *projNode) exclude(e *Encoder, d *Decoder) error {
+	if len(n.children) == 0 {
+		return copyRaw(e, d)
+	}
+	switch d.Next() {
+	case Object:
+		e.ObjStart()
+		if err := d.ObjBytes(func(d *Decoder, key []byte) error {
+			child, ok := n.children[string(key)]
+			switch {
+			case !ok:
+				e.FieldStart(string(key))
+				return copyRaw(e, d)
+			case child.all:
+				return d.Skip()
+			default:
+				e.FieldStart(string(key))
+				return child.exclude(e, d)
+			}
+		}); err != nil {
+			return err
+		}
+		e.ObjEnd()
+		return nil
+	case Array:
+		e.ArrStart()
+		if err := d.Arr(func(d *Decoder) error {
+			return n.exclude(e, d)
+		}); err != nil {
+			return err
+		}
+		e.ArrEnd()
+		return nil
+	default:
+		return copyRaw(e, d)
+	}
+}
+
+// copyRaw copies value from d to e.
+func copyRaw(e *Encoder, d *Decoder) error {
+	// Skip leading whitespace, which is captured by Raw otherwise.
+	d.Next()
+	v, err := d.Raw()
+	if err != nil {
+		return err
+	}
+	// Raw is invalidated by next read of streaming decoder.
+	e.Raw(v)
+	return nil
+}
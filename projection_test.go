@@ -0,0 +1,135 @@
+package jx
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjection_Apply(t *testing.T) {
+	const (
+		items = `[
+			{"id": 3, "price": {"value": 10, "currency": "EUR"}},
+			{"id": 4, "price": null},
+			5,
+			[{"id": 6, "name": "baz"}]
+		]`
+		doc = `{
+		"id": 1,
+		"name": "foo",
+		"owner": {"id": 2, "name": "bar", "tags": ["a"]},
+		"items": ` + items + `,
+		"a/b": {"~": 1, "c": 2}
+	}`
+	)
+	for i, tt := range []struct {
+		include bool
+		paths   []string
+		input   string
+		expect  string
+	}{
+		{true, []string{"/id", "/name"}, doc, `{"id":1,"name":"foo"}`},
+		{true, []string{"/owner/name", "/owner/tags"}, doc, `{"owner":{"name":"bar","tags":["a"]}}`},
+		{true, []string{"/owner", "/owner/name"}, doc, `{"owner":{"id": 2, "name": "bar", "tags": ["a"]}}`},
+		{true, []string{"/owner/name", "/owner"}, doc, `{"owner":{"id": 2, "name": "bar", "tags": ["a"]}}`},
+		{true, []string{"/items/id"}, doc, `{"items":[{"id":3},{"id":4},[{"id":6}]]}`},
+		{true, []string{"/items/price/value"}, doc, `{"items":[{"price":{"value":10}},{},[{}]]}`},
+		{true, []string{"/a~1b/~0"}, doc, `{"a/b":{"~":1}}`},
+		{true, []string{"/id/foo", "/missing"}, doc, `{}`},
+		{true, nil, doc, `{}`},
+		{true, []string{""}, ` [1, 2] `, `[1, 2]`},
+		{true, []string{"/a"}, `[1,{"a":1,"b":2},"c"]`, `[{"a":1}]`},
+		{true, []string{"/a"}, `"foo"`, `null`},
+		{true, []string{"/"}, `{"":1,"a":2}`, `{"":1}`},
+		// Duplicate members are projected independently.
+		{true, []string{"/a/b"}, `{"a":{"b":1},"a":{"c":2}}`, `{"a":{"b":1},"a":{}}`},
+
+		{false, []string{"/id", "/owner/tags"}, doc,
+			`{"name":"foo","owner":{"id":2,"name":"bar"},"items":` + items + `,"a/b":{"~": 1, "c": 2}}`},
+		{false, []string{"/items/price/currency", "/items/id", "/owner", "/name", "/a~1b", "/id"}, doc,
+			`{"items":[{"price":{"value":10}},{"price":null},5,[{"name":"baz"}]]}`},
+		{false, nil, ` {"a": [1]} `, `{"a": [1]}`},
+		{false, []string{"/a"}, `"foo"`, `"foo"`},
+		{false, []string{"/a/b"}, `[{"a":{"b":1,"c":2}},{"a":3}]`, `[{"a":{"c":2}},{"a":3}]`},
+	} {
+		tt := tt
+		t.Run(fmt.Sprintf("Test%d", i+1), func(t *testing.T) {
+			newProjection := ExcludePaths
+			if tt.include {
+				newProjection = IncludePaths
+			}
+			p, err := newProjection(tt.paths...)
+			require.NoError(t, err)
+
+			for _, d := range []*Decoder{
+				DecodeStr(tt.input),
+				Decode(iotest.OneByteReader(strings.NewReader(tt.input)), 1),
+			} {
+				var e Encoder
+				require.NoError(t, p.Apply(&e, d))
+				require.Equal(t, tt.expect, e.String())
+			}
+		})
+	}
+}
+
+func TestProjection_Apply_error(t *testing.T) {
+	for _, paths := range [][]string{
+		{"a"},
+		{"/a", "/~2"},
+		{"/a~"},
+	} {
+		_, err := IncludePaths(paths...)
+		require.Error(t, err, paths)
+		_, err = ExcludePaths(paths...)
+		require.Error(t, err, paths)
+	}
+	_, err := ExcludePaths("/a", "")
+	require.Error(t, err)
+
+	include, err := IncludePaths("/a/b")
+	require.NoError(t, err)
+	exclude, err := ExcludePaths("/a/b")
+	require.NoError(t, err)
+	for _, input := range []string{
+		``,
+		`{`,
+		`{"a":`,
+		`{"a":{"b":1,}}`,
+		`{"a":{"c":[1,]}}`,
+		`[{"a":1},]`,
+		`{"b":[1,]}`,
+	} {
+		var e Encoder
+		require.Error(t, include.Apply(&e, DecodeStr(input)), input)
+		require.Error(t, exclude.Apply(&e, DecodeStr(input)), input)
+	}
+}
+
+func BenchmarkProjection_Apply(b *testing.B) {
+	runTestdataFile("citm_catalog.json", b.Fatal, func(name string, data []byte) {
+		p, err := IncludePaths("/events/name", "/performances/start", "/venueNames")
+		if err != nil {
+			b.Fatal(err)
+		}
+		var (
+			d = DecodeBytes(data)
+			e = GetEncoder()
+		)
+		defer PutEncoder(e)
+
+		b.ReportAllocs()
+		b.SetBytes(int64(len(data)))
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			d.ResetBytes(data)
+			e.Reset()
+			if err := p.Apply(e, d); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -53,6 +53,10 @@ func (w *Writer) Reset() {
 // ResetWriter resets underlying buffer and sets output writer.
 func (w *Writer) ResetWriter(out io.Writer) {
 	w.Buf = w.Buf[:0]
+	if cap(w.Buf) == 0 {
+		// Buffer is dropped on write error, streaming needs non-empty one.
+		w.Buf = make([]byte, 0, encoderBufSize)
+	}
 	if w.stream == nil {
 		w.stream = newStreamState(out)
 	}
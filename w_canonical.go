@@ -0,0 +1,284 @@
+package jx
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/go-faster/errors"
+
+	"github.com/go-faster/jx/internal/byteseq"
+)
+
+// Float64Canonical encodes float64 as ECMAScript Number.prototype.toString
+// does, as required by RFC 8785.
+//
+// NB: Infinities and NaN are represented as null.
+func (w *Writer) Float64Canonical(v float64) bool {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return w.Null()
+	}
+	var tmp [32]byte
+	return w.Raw(floatCanonicalAppend(tmp[:0], v))
+}
+
+// floatCanonicalAppend appends finite float as ECMAScript number.
+//
+// See https://tc39.es/ecma262/#sec-numeric-types-number-tostring.
+func floatCanonicalAppend(b []byte, v float64) []byte {
+	if v == 0 {
+		// Including negative zero.
+		return append(b, '0')
+	}
+	if v < 0 {
+		b = append(b, '-')
+		v = -v
+	}
+
+	// Shortest digits, which are "d.ddde±dd".
+	var (
+		tmp    [32]byte
+		digits [32]byte
+	)
+	s := strconv.AppendFloat(tmp[:0], v, 'e', -1, 64)
+	k := 0 // count of digits
+	i := 0
+	for ; s[i] != 'e'; i++ {
+		if s[i] != '.' {
+			digits[k] = s[i]
+			k++
+		}
+	}
+	exp, _ := strconv.Atoi(string(s[i+1:]))
+	// Value is 0.digits × 10^n.
+	n := exp + 1
+
+	switch {
+	case k <= n && n <= 21:
+		b = append(b, digits[:k]...)
+		for ; n > k; n-- {
+			b = append(b, '0')
+		}
+	case 0 < n && n <= 21:
+		b = append(b, digits[:n]...)
+		b = append(b, '.')
+		b = append(b, digits[n:k]...)
+	case -6 < n && n <= 0:
+		b = append(b, '0', '.')
+		for ; n < 0; n++ {
+			b = append(b, '0')
+		}
+		b = append(b, digits[:k]...)
+	default:
+		b = append(b, digits[0])
+		if k > 1 {
+			b = append(b, '.')
+			b = append(b, digits[1:k]...)
+		}
+		b = append(b, 'e')
+		if exp > 0 {
+			b = append(b, '+')
+		}
+		b = strconv.AppendInt(b, int64(exp), 10)
+	}
+	return b
+}
+
+// strCanonicalAppend appends string with minimal escaping of RFC 8785.
+func strCanonicalAppend[S byteseq.Byteseq](b []byte, v S) []byte {
+	b = append(b, '"')
+	start := 0
+	for i := 0; i < len(v); i++ {
+		c := v[i]
+		if safeSet[c] == 0 {
+			continue
+		}
+		b = append(b, v[start:i]...)
+		switch c {
+		case '\\', '"':
+			b = append(b, '\\', c)
+		case '\b':
+			b = append(b, '\\', 'b')
+		case '\t':
+			b = append(b, '\\', 't')
+		case '\n':
+			b = append(b, '\\', 'n')
+		case '\f':
+			b = append(b, '\\', 'f')
+		case '\r':
+			b = append(b, '\\', 'r')
+		default:
+			b = append(b, '\\', 'u', '0', '0', hexChars[c>>4], hexChars[c&0xF])
+		}
+		start = i + 1
+	}
+	b = append(b, v[start:]...)
+	return append(b, '"')
+}
+
+// Canonicalize reads value from d and writes it to w in canonical form of
+// JSON Canonicalization Scheme, as defined in RFC 8785:
+//
+//   - no whitespace,
+//   - object members sorted by UTF-16 code units of their names,
+//   - numbers serialized as ECMAScript does, see Writer.Float64Canonical,
+//   - strings with minimal escaping.
+//
+// So semantically equal inputs produce byte-identical outputs, suitable
+// for hashing and signing.
+//
+// Returns error if value is not I-JSON (RFC 7493): if it has invalid
+// UTF-8, lone surrogates, duplicate member names or numbers out of float64
+// range.
+func Canonicalize(w *Writer, d *Decoder) error {
+	mode := d.utf8
+	d.utf8 = UTF8Strict
+	defer func() {
+		d.utf8 = mode
+	}()
+
+	c := canonicalizer{d: d}
+	buf, err := c.value(nil)
+	if err != nil {
+		return err
+	}
+	w.Raw(buf)
+	return nil
+}
+
+// canonicalizer reads values of Canonicalize.
+type canonicalizer struct {
+	d   *Decoder
+	str []byte // decoded string
+	tmp []byte // members of object being sorted
+}
+
+// canonicalMember is object member, which canonical value is written at
+// [start, end) of buffer.
+type canonicalMember struct {
+	key        string
+	start, end int
+}
+
+func (c *canonicalizer) value(b []byte) ([]byte, error) {
+	d := c.d
+	switch t := d.Next(); t {
+	case Object:
+		return c.obj(b)
+	case Array:
+		b = append(b, '[')
+		n := 0
+		if err := d.Arr(func(d *Decoder) error {
+			if n > 0 {
+				b = append(b, ',')
+			}
+			n++
+			var err error
+			b, err = c.value(b)
+			return err
+		}); err != nil {
+			return b, err
+		}
+		return append(b, ']'), nil
+	case String:
+		s, err := d.StrAppend(c.str[:0])
+		if err != nil {
+			return b, err
+		}
+		c.str = s
+		return strCanonicalAppend(b, s), nil
+	case Number:
+		num, err := d.Num()
+		if err != nil {
+			return b, err
+		}
+		f, err := strconv.ParseFloat(string(num), 64)
+		if err != nil {
+			return b, errors.Wrapf(err, "number %s", num)
+		}
+		return floatCanonicalAppend(b, f), nil
+	case Bool:
+		v, err := d.Bool()
+		if err != nil {
+			return b, err
+		}
+		return strconv.AppendBool(b, v), nil
+	case Null:
+		if err := d.Null(); err != nil {
+			return b, err
+		}
+		return append(b, "null"...), nil
+	default:
+		if err := d.Skip(); err != nil {
+			return b, err
+		}
+		return b, errors.Errorf("unexpected type %s", t)
+	}
+}
+
+func (c *canonicalizer) obj(b []byte) ([]byte, error) {
+	var (
+		start   = len(b)
+		members []canonicalMember
+	)
+	if err := c.d.ObjBytes(func(d *Decoder, key []byte) error {
+		m := canonicalMember{key: string(key), start: len(b)}
+		var err error
+		if b, err = c.value(b); err != nil {
+			return err
+		}
+		m.end = len(b)
+		members = append(members, m)
+		return nil
+	}); err != nil {
+		return b, err
+	}
+	sort.Slice(members, func(i, j int) bool {
+		return lessUTF16(members[i].key, members[j].key)
+	})
+
+	// Values are written in input order, reorder them.
+	c.tmp = append(c.tmp[:0], b[start:]...)
+	b = append(b[:start], '{')
+	for i, m := range members {
+		if i > 0 {
+			if members[i-1].key == m.key {
+				return b, errors.Errorf("duplicate field %q", m.key)
+			}
+			b = append(b, ',')
+		}
+		b = strCanonicalAppend(b, m.key)
+		b = append(b, ':')
+		b = append(b, c.tmp[m.start-start:m.end-start]...)
+	}
+	return append(b, '}'), nil
+}
+
+// lessUTF16 reports whether a is less than b, comparing UTF-16 code units.
+//
+// Strings should be valid UTF-8.
+func lessUTF16(a, b string) bool {
+	for a != "" && b != "" {
+		ra, na := utf8.DecodeRuneInString(a)
+		rb, nb := utf8.DecodeRuneInString(b)
+		if ra != rb {
+			a1, a2 := utf16.EncodeRune(ra)
+			b1, b2 := utf16.EncodeRune(rb)
+			if a1 == utf8.RuneError {
+				// Not surrogate pair.
+				a1, a2 = ra, 0
+			}
+			if b1 == utf8.RuneError {
+				b1, b2 = rb, 0
+			}
+			if a1 != b1 {
+				return a1 < b1
+			}
+			return a2 < b2
+		}
+		a, b = a[na:], b[nb:]
+	}
+	return len(a) < len(b)
+}
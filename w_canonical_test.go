@@ -0,0 +1,172 @@
+package jx
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriter_Float64Canonical(t *testing.T) {
+	// Test vectors of RFC 8785, appendix B.
+	for _, tt := range []struct {
+		bits   uint64
+		expect string
+	}{
+		{0x0000000000000000, "0"},
+		{0x8000000000000000, "0"},
+		{0x0000000000000001, "5e-324"},
+		{0x8000000000000001, "-5e-324"},
+		{0x7fefffffffffffff, "1.7976931348623157e+308"},
+		{0xffefffffffffffff, "-1.7976931348623157e+308"},
+		{0x4340000000000000, "9007199254740992"},
+		{0xc340000000000000, "-9007199254740992"},
+		{0x4430000000000000, "295147905179352830000"},
+		{0x7fffffffffffffff, "null"},
+		{0x7ff0000000000000, "null"},
+		{0x44b52d02c7e14af5, "9.999999999999997e+22"},
+		{0x44b52d02c7e14af6, "1e+23"},
+		{0x44b52d02c7e14af7, "1.0000000000000001e+23"},
+		{0x444b1ae4d6e2ef4e, "999999999999999700000"},
+		{0x444b1ae4d6e2ef4f, "999999999999999900000"},
+		{0x444b1ae4d6e2ef50, "1e+21"},
+		{0x3eb0c6f7a0b5ed8c, "9.999999999999997e-7"},
+		{0x3eb0c6f7a0b5ed8d, "0.000001"},
+		{0x41b3de4355555553, "333333333.3333332"},
+		{0x41b3de4355555554, "333333333.33333325"},
+		{0x41b3de4355555555, "333333333.3333333"},
+		{0x41b3de4355555556, "333333333.3333334"},
+		{0x41b3de4355555557, "333333333.33333343"},
+		{0xbecbf647612f3696, "-0.0000033333333333333333"},
+		{0x43143ff3c1cb0959, "1424953923781206.2"},
+	} {
+		v := math.Float64frombits(tt.bits)
+		var w Writer
+		w.Float64Canonical(v)
+		require.Equal(t, tt.expect, w.String(), "%x", tt.bits)
+
+		var e Encoder
+		e.ArrStart()
+		e.Float64Canonical(v)
+		e.Float64Canonical(v)
+		e.ArrEnd()
+		require.Equal(t, "["+tt.expect+","+tt.expect+"]", e.String())
+	}
+	for _, tt := range []struct {
+		v      float64
+		expect string
+	}{
+		{1, "1"},
+		{-1.5, "-1.5"},
+		{100, "100"},
+		{0.1, "0.1"},
+		{1e20, "100000000000000000000"},
+		{1.5e21, "1.5e+21"},
+		{1e-6, "0.000001"},
+		{1.25e-7, "1.25e-7"},
+		{123e-20, "1.23e-18"},
+	} {
+		var w Writer
+		w.Float64Canonical(tt.v)
+		require.Equal(t, tt.expect, w.String(), "%v", tt.v)
+	}
+}
+
+func TestCanonicalize(t *testing.T) {
+	for i, tt := range []struct {
+		input  string
+		expect string
+	}{
+		// RFC 8785, section 3.2.2.
+		{
+			`{
+  "numbers": [333333333.33333329, 1E30, 4.50, 2e-3, 0.000000000000000000000000001],
+  "string": "\u20ac$\u000F\u000aA'\u0042\u0022\u005c\\\"\/",
+  "literals": [null, true, false]
+}`,
+			`{"literals":[null,true,false],"numbers":[333333333.3333333,1e+30,4.5,0.002,1e-27],"string":"€$\u000f\nA'B\"\\\\\"/"}`,
+		},
+		// RFC 8785, section 3.2.3.
+		{
+			`{
+  "\u20ac": "Euro Sign",
+  "\r": "Carriage Return",
+  "\ufb33": "Hebrew Letter Dalet With Dagesh",
+  "1": "One",
+  "\ud83d\ude00": "Emoji: Grinning Face",
+  "\u0080": "Control",
+  "\u00f6": "Latin Small Letter O With Diaeresis"
+}`,
+			"{\"\\r\":\"Carriage Return\",\"1\":\"One\",\"\u0080\":\"Control\",\"\u00f6\":\"Latin Small Letter O With Diaeresis\",\"\u20ac\":\"Euro Sign\",\"\U0001F600\":\"Emoji: Grinning Face\",\"\ufb33\":\"Hebrew Letter Dalet With Dagesh\"}",
+		},
+		{`1`, `1`},
+		{` -0.0 `, `0`},
+		{`"\b\f\u0001\u001f\u007f"`, "\"\\b\\f\\u0001\\u001f\u007f\""},
+		{`[]`, `[]`},
+		{`{}`, `{}`},
+		{`[{"b":[],"a":{}}, {"b":1}]`, `[{"a":{},"b":[]},{"b":1}]`},
+		{`{"b":{"d":1,"c":2},"a":[{"z":1,"y":2}]}`, `{"a":[{"y":2,"z":1}],"b":{"c":2,"d":1}}`},
+		{`{"aa":1,"a":2,"":3}`, `{"":3,"a":2,"aa":1}`},
+	} {
+		tt := tt
+		t.Run(fmt.Sprintf("Test%d", i+1), func(t *testing.T) {
+			for _, d := range []*Decoder{
+				DecodeStr(tt.input),
+				Decode(iotest.OneByteReader(strings.NewReader(tt.input)), 1),
+			} {
+				var w Writer
+				require.NoError(t, Canonicalize(&w, d))
+				require.Equal(t, tt.expect, w.String())
+			}
+		})
+	}
+	t.Run("Error", func(t *testing.T) {
+		for _, input := range []string{
+			`{"a":1,"b":2,"a":3}`,
+			`{"a":{"b":1,"b":1}}`,
+			`"\ud800"`,
+			"\"\xff\"",
+			`1e400`,
+			`[1,]`,
+			`{"a":}`,
+			`x`,
+		} {
+			d := DecodeStr(input)
+			var w Writer
+			require.Error(t, Canonicalize(&w, d), input)
+			require.Equal(t, UTF8Unchecked, d.utf8)
+		}
+	})
+	t.Run("Testdata", func(t *testing.T) {
+		runTestdata(t.Fatal, func(name string, data []byte) {
+			var w Writer
+			require.NoError(t, Canonicalize(&w, DecodeBytes(data)), name)
+			// Canonical form is idempotent.
+			var again Writer
+			require.NoError(t, Canonicalize(&again, DecodeBytes(w.Buf)), name)
+			require.Equal(t, w.String(), again.String(), name)
+		})
+	})
+}
+
+func TestLessUTF16(t *testing.T) {
+	for _, tt := range []struct {
+		a, b string
+	}{
+		{"", "a"},
+		{"a", "b"},
+		{"a", "aa"},
+		{"\r", "1"},
+		{"\u00f6", "\u20ac"},
+		{"\U0001F600", "\ufb33"},
+		{"\U0001F600", "\U0001F601"},
+		{"\ud7ff", "\U00010000"},
+	} {
+		require.True(t, lessUTF16(tt.a, tt.b), "%q < %q", tt.a, tt.b)
+		require.False(t, lessUTF16(tt.b, tt.a), "%q < %q", tt.b, tt.a)
+	}
+	require.False(t, lessUTF16("a", "a"))
+}
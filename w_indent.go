@@ -0,0 +1,179 @@
+package jx
+
+import "github.com/go-faster/errors"
+
+// Compact reads single value from d and writes it to w without
+// insignificant whitespace, like json.Compact.
+//
+// Strings and numbers are copied as is, so number literals and escape
+// sequences are preserved. Both d and w can be streaming, so documents of
+// any size are reformatted in constant memory.
+func Compact(w *Writer, d *Decoder) error {
+	f := reformatter{w: w, d: d}
+	return f.run()
+}
+
+// Indent reads single value from d and writes it to w indented, like
+// json.Indent.
+//
+// Each element of object or array begins on new line, starting with
+// prefix followed by one or more copies of indent according to nesting.
+// First line has no prefix, empty objects and arrays are written as {}
+// and [].
+//
+// Strings and numbers are copied as is, see Compact.
+func Indent(w *Writer, d *Decoder, prefix, indent string) error {
+	f := reformatter{w: w, d: d, pretty: true, prefix: prefix, indent: indent}
+	return f.run()
+}
+
+// reformatter copies value from Decoder to Writer, changing whitespace.
+type reformatter struct {
+	w *Writer
+	d *Decoder
+
+	pretty bool
+	prefix string
+	indent string
+	depth  int
+}
+
+func (f *reformatter) run() error {
+	if err := f.value(); err != nil {
+		return err
+	}
+	return f.err()
+}
+
+// err returns write error of streaming Writer.
+func (f *reformatter) err() error {
+	if s := f.w.stream; s != nil && s.fail() {
+		return s.writeErr
+	}
+	return nil
+}
+
+func (f *reformatter) value() error {
+	d := f.d
+	c, err := d.next()
+	if err != nil {
+		return err
+	}
+	switch c {
+	case '{', '[':
+		return f.container(c)
+	default:
+		d.unread()
+		raw, err := d.Raw()
+		if err != nil {
+			return err
+		}
+		f.w.Raw(raw)
+		return f.err()
+	}
+}
+
+// container copies object or array, which bracket is consumed.
+func (f *reformatter) container(start byte) error {
+	var (
+		d   = f.d
+		w   = f.w
+		obj = start == '{'
+		end = byte(']')
+	)
+	if obj {
+		end = '}'
+	}
+	if err := d.incDepth(); err != nil {
+		return errors.Wrap(err, "inc")
+	}
+	c, err := d.more()
+	if err != nil {
+		return err
+	}
+	if c == end {
+		w.twoBytes(start, end)
+		return d.decDepth()
+	}
+	d.unread()
+
+	w.byte(start)
+	f.depth++
+	for n := 1; ; n++ {
+		f.newline()
+		if obj {
+			if err := f.key(); err != nil {
+				return err
+			}
+		}
+		if err := f.value(); err != nil {
+			return err
+		}
+
+		c, err := d.more()
+		if err != nil {
+			return errors.Wrapf(err, `"," or %q expected`, end)
+		}
+		switch c {
+		case end:
+			f.depth--
+			f.newline()
+			w.byte(end)
+			return d.decDepth()
+		case ',':
+		default:
+			err := d.badToken(c, d.offset()-1)
+			return errors.Wrapf(err, `"," or %q expected`, end)
+		}
+		if obj {
+			err = d.checkObjLen(n + 1)
+		} else {
+			err = d.checkArrLen(n + 1)
+		}
+		if err != nil {
+			return err
+		}
+		w.byte(',')
+	}
+}
+
+// key copies object key and colon.
+func (f *reformatter) key() error {
+	d := f.d
+	c, err := d.more()
+	if err != nil {
+		return errors.Wrap(err, `'"' expected`)
+	}
+	if c != '"' {
+		err := d.badToken(c, d.offset()-1)
+		return errors.Wrap(err, `'"' expected`)
+	}
+	d.unread()
+	raw, err := d.Raw()
+	if err != nil {
+		return errors.Wrap(err, "field name")
+	}
+	// Raw is invalidated by next read of streaming decoder.
+	f.w.Raw(raw)
+	if err := d.consume(':'); err != nil {
+		return errors.Wrap(err, `":" expected`)
+	}
+	if f.pretty {
+		f.w.twoBytes(':', ' ')
+	} else {
+		f.w.byte(':')
+	}
+	return nil
+}
+
+func (f *reformatter) newline() {
+	if !f.pretty {
+		return
+	}
+	w := f.w
+	w.byte('\n')
+	w.rawStr(f.prefix)
+	for i := 0; i < f.depth; i++ {
+		w.rawStr(f.indent)
+	}
+}
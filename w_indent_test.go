@@ -0,0 +1,125 @@
+package jx
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/go-faster/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompact(t *testing.T) {
+	runTestdata(t.Fatal, func(name string, data []byte) {
+		t.Run(name, func(t *testing.T) {
+			var expected bytes.Buffer
+			require.NoError(t, json.Compact(&expected, data))
+
+			for _, d := range []*Decoder{
+				DecodeBytes(data),
+				Decode(iotest.HalfReader(bytes.NewReader(data)), 512),
+			} {
+				var w Writer
+				require.NoError(t, Compact(&w, d))
+				require.Equal(t, expected.String(), w.String())
+			}
+		})
+	})
+}
+
+func TestIndent(t *testing.T) {
+	runTestdata(t.Fatal, func(name string, data []byte) {
+		t.Run(name, func(t *testing.T) {
+			for _, tt := range []struct {
+				prefix, indent string
+			}{
+				{"", "  "},
+				{"> ", "\t"},
+				{"", ""},
+			} {
+				var expected bytes.Buffer
+				require.NoError(t, json.Indent(&expected, data, tt.prefix, tt.indent))
+
+				for _, d := range []*Decoder{
+					DecodeBytes(data),
+					Decode(iotest.HalfReader(bytes.NewReader(data)), 512),
+				} {
+					var w Writer
+					require.NoError(t, Indent(&w, d, tt.prefix, tt.indent))
+					// Trailing whitespace of input is kept by json.Indent.
+					require.Equal(t, strings.TrimRight(expected.String(), " \t\r\n"), w.String())
+				}
+			}
+		})
+	})
+}
+
+func TestIndent_cases(t *testing.T) {
+	for _, tt := range []struct {
+		input, compact, indent string
+	}{
+		{` 1 `, `1`, `1`},
+		{`[ ]`, `[]`, `[]`},
+		{`{ }`, `{}`, `{}`},
+		{
+			`{ "a\u0041" : [ 1.50, "\/", { } ], "b" : 1E3 }`,
+			`{"a\u0041":[1.50,"\/",{}],"b":1E3}`,
+			"{\n\t\"a\\u0041\": [\n\t\t1.50,\n\t\t\"\\/\",\n\t\t{}\n\t],\n\t\"b\": 1E3\n}",
+		},
+	} {
+		var w Writer
+		require.NoError(t, Compact(&w, DecodeStr(tt.input)))
+		require.Equal(t, tt.compact, w.String())
+
+		w.Reset()
+		require.NoError(t, Indent(&w, DecodeStr(tt.input), "", "\t"))
+		require.Equal(t, tt.indent, w.String())
+	}
+
+	t.Run("Multiple", func(t *testing.T) {
+		d := DecodeStr(`{"a": 1} [2, 3]`)
+		var w Writer
+		require.NoError(t, Compact(&w, d))
+		require.NoError(t, Compact(&w, d))
+		require.Equal(t, `{"a":1}[2,3]`, w.String())
+	})
+	t.Run("Error", func(t *testing.T) {
+		for _, input := range []string{
+			``,
+			`{`,
+			`{"a"}`,
+			`{"a":}`,
+			`{"a":1,}`,
+			`{"a":1 "b":2}`,
+			`{1:1}`,
+			`[1,]`,
+			`[1 2]`,
+			`[1,2`,
+			`"foo`,
+			`tru`,
+		} {
+			var w Writer
+			require.Error(t, Compact(&w, DecodeStr(input)), input)
+			require.Error(t, Indent(&w, DecodeStr(input), "", " "), input)
+		}
+	})
+	t.Run("Limits", func(t *testing.T) {
+		d := DecodeStr(`[[[1]]]`)
+		d.SetLimits(Limits{Depth: 2})
+		var w Writer
+		require.ErrorIs(t, Compact(&w, d), ErrMaxDepth)
+
+		d = DecodeStr(`{"a":1,"b":2,"c":3}`)
+		d.SetLimits(Limits{ObjLen: 2})
+		require.ErrorIs(t, Compact(&w, d), ErrMaxObjLen)
+	})
+	t.Run("WriteError", func(t *testing.T) {
+		errTest := errors.New("test")
+		var w Writer
+		w.ResetWriter(&errWriter{err: errTest})
+		input := `[` + strings.Repeat(`"foo",`, encoderBufSize) + `1]`
+		require.ErrorIs(t, Indent(&w, DecodeStr(input), "", " "), errTest)
+	})
+}